@@ -0,0 +1,51 @@
+package graph
+
+import (
+	"errors"
+	"net/http"
+
+	_ "github.com/danilosano/web-golang-api/cmd/httperrors"
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/go-playground/validator/v10"
+)
+
+// Error is a resolver error carrying the code, and the invalid fields, that the
+// REST API would report for the same failure in the GraphQL error extensions.
+type Error struct {
+	Message string
+	Code    string
+	Fields  []web.FieldError
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Extensions() map[string]any {
+	ext := map[string]any{"code": e.Code}
+	if len(e.Fields) > 0 {
+		ext["fields"] = e.Fields
+	}
+	return ext
+}
+
+// resolveError maps err like the REST error handler does: validation errors list
+// the invalid fields, errors registered with web.RegisterError get their code, and
+// anything else is reported as an internal error without its message.
+func resolveError(err error) error {
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		return &Error{Message: "invalid input", Code: web.StatusCode(http.StatusBadRequest), Fields: web.FieldErrors(verrs)}
+	}
+
+	mapping, ok := web.LookupError(err)
+	if !ok {
+		return &Error{Message: "internal server error", Code: web.StatusCode(http.StatusInternalServerError)}
+	}
+
+	code := mapping.Code
+	if code == "" {
+		code = web.StatusCode(mapping.Status)
+	}
+	return &Error{Message: err.Error(), Code: code}
+}
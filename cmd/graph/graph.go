@@ -0,0 +1,75 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// Request is a GraphQL request as sent over HTTP.
+type Request struct {
+	Query         string         `json:"query" form:"query" binding:"required"`
+	OperationName string         `json:"operationName" form:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// IsMutation reports whether req runs a mutation. Requests that cannot be parsed
+// are not, as they are rejected before running anything.
+func IsMutation(req Request) bool {
+	doc, err := parse(req)
+	if err != nil {
+		return false
+	}
+	op := operation(doc, req.OperationName)
+	return op != nil && op.Operation == ast.OperationTypeMutation
+}
+
+// Execute parses and validates req against schema, rejects it when it exceeds the
+// limits, and otherwise runs it.
+func Execute(ctx context.Context, schema graphql.Schema, limits Limits, req Request) *graphql.Result {
+	doc, err := parse(req)
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	if result := graphql.ValidateDocument(&schema, doc, nil); !result.IsValid {
+		return &graphql.Result{Errors: result.Errors}
+	}
+
+	if err := limits.check(doc, req.OperationName, req.Variables); err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	})
+}
+
+func parse(req Request) (*ast.Document, error) {
+	return parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{
+		Body: []byte(req.Query),
+		Name: "GraphQL request",
+	})})
+}
+
+// operation returns the operation of doc named operationName, or the last one
+// when no name is given.
+func operation(doc *ast.Document, operationName string) *ast.OperationDefinition {
+	var op *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		if def, ok := def.(*ast.OperationDefinition); ok {
+			if operationName == "" || (def.Name != nil && def.Name.Value == operationName) {
+				op = def
+			}
+		}
+	}
+	return op
+}
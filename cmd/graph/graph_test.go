@@ -0,0 +1,229 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	mocks "github.com/danilosano/web-golang-api/pkg/tests/customers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	customerNumber = 2
+	resultCustomer = dto.ResultCustomerRequest{
+		ID:             1,
+		CustomerNumber: &customerNumber,
+		FirstName:      "Danilo",
+		LastName:       "Sano",
+		CreatedAt:      time.Date(2021, 10, 10, 0, 0, 0, 0, time.UTC),
+	}
+)
+
+func execute(t *testing.T, s customer.Service, limits Limits, req Request) map[string]any {
+	t.Helper()
+	schema, err := NewSchema(s)
+	require.NoError(t, err)
+
+	body, err := json.Marshal(Execute(context.Background(), schema, limits, req))
+	require.NoError(t, err)
+	var result map[string]any
+	require.NoError(t, json.Unmarshal(body, &result))
+	return result
+}
+
+func TestQueries(t *testing.T) {
+	t.Run("A customer is fetched by ID with only the requested fields.", func(t *testing.T) {
+		mockService := new(mocks.CustomersServiceMock)
+		mockService.On("Get", mock.Anything, 1).Return(resultCustomer, nil)
+
+		result := execute(t, mockService, DefaultLimits, Request{Query: `{ customer(id: 1) { id firstName createdAt updatedAt } }`})
+
+		assert.Nil(t, result["errors"])
+		assert.Equal(t, map[string]any{"customer": map[string]any{
+			"id": float64(1), "firstName": "Danilo", "createdAt": "2021-10-10T00:00:00Z", "updatedAt": nil,
+		}}, result["data"])
+	})
+
+	t.Run("A customer is fetched by number.", func(t *testing.T) {
+		mockService := new(mocks.CustomersServiceMock)
+		mockService.On("GetByCustomerNumber", mock.Anything, 2).Return(resultCustomer, nil)
+
+		result := execute(t, mockService, DefaultLimits, Request{Query: `{ customerByNumber(customerNumber: 2) { id customerNumber } }`})
+
+		assert.Equal(t, map[string]any{"customerByNumber": map[string]any{"id": float64(1), "customerNumber": float64(2)}}, result["data"])
+	})
+
+	t.Run("The customers listing passes the filter and the pagination to the service.", func(t *testing.T) {
+		mockService := new(mocks.CustomersServiceMock)
+		filter := dto.CustomerFilter{FirstName: "Dan", Page: 2, PageSize: 5}
+		mockService.On("List", mock.Anything, filter).Return(dto.CustomerPage{Customers: []dto.ResultCustomerRequest{resultCustomer}, Page: 2, PageSize: 5, Total: 6}, nil)
+
+		result := execute(t, mockService, DefaultLimits, Request{
+			Query:     `query List($size: Int) { customers(filter: {firstName: "Dan"}, page: 2, pageSize: $size) { total customers { lastName } } }`,
+			Variables: map[string]any{"size": 5},
+		})
+
+		assert.Nil(t, result["errors"])
+		assert.Equal(t, map[string]any{"customers": map[string]any{
+			"total": float64(6), "customers": []any{map[string]any{"lastName": "Sano"}},
+		}}, result["data"])
+	})
+
+	t.Run("The customers can be filtered by status and tags.", func(t *testing.T) {
+		mockService := new(mocks.CustomersServiceMock)
+		filter := dto.CustomerFilter{Status: "active", Tags: []string{"vip", "churn-risk"}, TagMatch: "all", Page: 1, PageSize: 20}
+		mockService.On("List", mock.Anything, filter).Return(dto.CustomerPage{Customers: []dto.ResultCustomerRequest{resultCustomer}, Page: 1, PageSize: 20, Total: 1}, nil)
+
+		result := execute(t, mockService, DefaultLimits, Request{
+			Query: `{ customers(filter: {status: "active", tags: ["vip", "churn-risk"], tagMatch: "all"}) { total customers { status } } }`,
+		})
+
+		assert.Nil(t, result["errors"])
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("When the customer does not exist, the error carries the not_found code.", func(t *testing.T) {
+		mockService := new(mocks.CustomersServiceMock)
+		mockService.On("Get", mock.Anything, 9).Return(nil, customer.ErrorCustomerNotFound)
+
+		result := execute(t, mockService, DefaultLimits, Request{Query: `{ customer(id: 9) { id } }`})
+
+		errs := result["errors"].([]any)
+		require.Len(t, errs, 1)
+		assert.Equal(t, "customer not found", errs[0].(map[string]any)["message"])
+		assert.Equal(t, map[string]any{"code": "not_found"}, errs[0].(map[string]any)["extensions"])
+	})
+
+	t.Run("Unexpected errors are reported without their message.", func(t *testing.T) {
+		mockService := new(mocks.CustomersServiceMock)
+		mockService.On("Get", mock.Anything, 1).Return(nil, errors.New("connection refused"))
+
+		result := execute(t, mockService, DefaultLimits, Request{Query: `{ customer(id: 1) { id } }`})
+
+		errs := result["errors"].([]any)
+		assert.Equal(t, "internal server error", errs[0].(map[string]any)["message"])
+	})
+}
+
+func TestMutations(t *testing.T) {
+	t.Run("A customer is created through the service.", func(t *testing.T) {
+		mockService := new(mocks.CustomersServiceMock)
+		mockService.On("Save", mock.Anything, dto.CreateCustomerRequest{CustomerNumber: &customerNumber, FirstName: "Danilo", LastName: "Sano"}).Return(resultCustomer, nil)
+
+		result := execute(t, mockService, DefaultLimits, Request{Query: `mutation { createCustomer(input: {customerNumber: 2, firstName: "Danilo", lastName: "Sano"}) { id } }`})
+
+		assert.Equal(t, map[string]any{"createCustomer": map[string]any{"id": float64(1)}}, result["data"])
+	})
+
+	t.Run("The attributes and the parent are kept through an update, inline or as variables.", func(t *testing.T) {
+		parentID := 5
+		input := dto.UpdateCustomerRequest{
+			CustomerNumber: &customerNumber,
+			FirstName:      "Danilo",
+			LastName:       "Sano",
+			Attributes:     domain.Attributes{"tier": "gold", "seats": float64(12), "regions": []any{"eu"}},
+			ParentID:       &parentID,
+		}
+		updated := resultCustomer
+		updated.Attributes = input.Attributes
+		updated.ParentID = &parentID
+		mockService := new(mocks.CustomersServiceMock)
+		mockService.On("Update", mock.Anything, input, 1).Return(updated, nil)
+		expected := map[string]any{"updateCustomer": map[string]any{
+			"attributes": map[string]any{"tier": "gold", "seats": float64(12), "regions": []any{"eu"}},
+			"parentId":   float64(5),
+		}}
+
+		result := execute(t, mockService, DefaultLimits, Request{Query: `mutation {
+			updateCustomer(id: 1, input: {customerNumber: 2, firstName: "Danilo", lastName: "Sano", attributes: {tier: "gold", seats: 12, regions: ["eu"]}, parentId: 5}) { attributes parentId }
+		}`})
+		assert.Nil(t, result["errors"])
+		assert.Equal(t, expected, result["data"])
+
+		result = execute(t, mockService, DefaultLimits, Request{
+			Query: `mutation Update($input: CustomerInput!) { updateCustomer(id: 1, input: $input) { attributes parentId } }`,
+			Variables: map[string]any{"input": map[string]any{
+				"customerNumber": 2, "firstName": "Danilo", "lastName": "Sano",
+				"attributes": map[string]any{"tier": "gold", "seats": float64(12), "regions": []any{"eu"}}, "parentId": 5,
+			}},
+		})
+		assert.Nil(t, result["errors"])
+		assert.Equal(t, expected, result["data"])
+		mockService.AssertNumberOfCalls(t, "Update", 2)
+	})
+
+	t.Run("When the parent would become a descendant, an unprocessable entity error is returned.", func(t *testing.T) {
+		mockService := new(mocks.CustomersServiceMock)
+		mockService.On("Update", mock.Anything, mock.Anything, 1).Return(nil, customer.ErrorHierarchyCycle)
+
+		result := execute(t, mockService, DefaultLimits, Request{Query: `mutation { updateCustomer(id: 1, input: {customerNumber: 2, firstName: "Danilo", lastName: "Sano", parentId: 3}) { id } }`})
+
+		errs := result["errors"].([]any)
+		require.Len(t, errs, 1)
+		assert.Equal(t, map[string]any{"code": "unprocessable_entity"}, errs[0].(map[string]any)["extensions"])
+	})
+
+	t.Run("When the input is invalid, the invalid fields are listed and the service is not called.", func(t *testing.T) {
+		mockService := new(mocks.CustomersServiceMock)
+
+		result := execute(t, mockService, DefaultLimits, Request{Query: `mutation { updateCustomer(id: 1, input: {customerNumber: 0, firstName: "", lastName: "Sano"}) { id } }`})
+
+		errs := result["errors"].([]any)
+		require.Len(t, errs, 1)
+		assert.Equal(t, map[string]any{"code": "bad_request", "fields": []any{
+			map[string]any{"field": "customer_number", "message": "must be greater than 0"},
+			map[string]any{"field": "first_name", "message": "is required"},
+		}}, errs[0].(map[string]any)["extensions"])
+		mockService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("A customer is deleted through the service.", func(t *testing.T) {
+		mockService := new(mocks.CustomersServiceMock)
+		mockService.On("Delete", mock.Anything, 1).Return(nil)
+
+		result := execute(t, mockService, DefaultLimits, Request{Query: `mutation { deleteCustomer(id: 1) }`})
+
+		assert.Equal(t, map[string]any{"deleteCustomer": true}, result["data"])
+	})
+}
+
+func TestIsMutation(t *testing.T) {
+	document := `query One { customer(id: 1) { id } } mutation Drop { deleteCustomer(id: 1) }`
+
+	assert.True(t, IsMutation(Request{Query: `mutation { deleteCustomer(id: 1) }`}))
+	assert.True(t, IsMutation(Request{Query: document, OperationName: "Drop"}))
+	assert.False(t, IsMutation(Request{Query: document, OperationName: "One"}))
+	assert.False(t, IsMutation(Request{Query: `{ customer(id: 1) { id } }`}))
+	assert.False(t, IsMutation(Request{Query: `mutation {`}))
+}
+
+func TestLimits(t *testing.T) {
+	t.Run("A query deeper than the limit is rejected before reaching the service.", func(t *testing.T) {
+		mockService := new(mocks.CustomersServiceMock)
+
+		result := execute(t, mockService, Limits{MaxDepth: 2}, Request{Query: `{ customers { customers { id } } }`})
+
+		errs := result["errors"].([]any)
+		assert.Equal(t, "query depth 3 exceeds the maximum of 2", errs[0].(map[string]any)["message"])
+		mockService.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+	})
+
+	t.Run("The complexity counts the listed fields once per requested item, including fragments.", func(t *testing.T) {
+		mockService := new(mocks.CustomersServiceMock)
+
+		result := execute(t, mockService, Limits{MaxComplexity: 100}, Request{
+			Query: `{ customers(pageSize: 50) { ...page } } fragment page on CustomerPage { total customers { id firstName } }`,
+		})
+
+		errs := result["errors"].([]any)
+		assert.Equal(t, "query complexity 201 exceeds the maximum of 100", errs[0].(map[string]any)["message"])
+	})
+}
@@ -0,0 +1,115 @@
+package graph
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Limits bounds the cost of a query before it is executed. Zero disables a limit.
+type Limits struct {
+	// MaxDepth is the deepest level of nested selections allowed.
+	MaxDepth int
+	// MaxComplexity is the highest cost allowed, where every field costs one and
+	// the fields selected below a paginated field count once per requested item.
+	MaxComplexity int
+}
+
+// DefaultLimits allow every query of the schema while rejecting abusive ones.
+var DefaultLimits = Limits{MaxDepth: 8, MaxComplexity: 1000}
+
+// check returns an error when the operation to run exceeds the limits.
+func (l Limits) check(doc *ast.Document, operationName string, variables map[string]any) error {
+	a := analyzer{fragments: map[string]*ast.FragmentDefinition{}, variables: variables}
+	for _, def := range doc.Definitions {
+		if def, ok := def.(*ast.FragmentDefinition); ok {
+			a.fragments[def.Name.Value] = def
+		}
+	}
+	op := operation(doc, operationName)
+	if op == nil {
+		return nil
+	}
+
+	depth, complexity := a.selectionSet(op.SelectionSet, true)
+	if l.MaxDepth > 0 && depth > l.MaxDepth {
+		return fmt.Errorf("query depth %d exceeds the maximum of %d", depth, l.MaxDepth)
+	}
+	if l.MaxComplexity > 0 && complexity > l.MaxComplexity {
+		return fmt.Errorf("query complexity %d exceeds the maximum of %d", complexity, l.MaxComplexity)
+	}
+	return nil
+}
+
+type analyzer struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]any
+}
+
+// selectionSet returns the depth and the complexity of set. Fragment cycles are
+// rejected by the schema validation, which runs first.
+func (a analyzer) selectionSet(set *ast.SelectionSet, root bool) (int, int) {
+	if set == nil {
+		return 0, 0
+	}
+
+	var depth, complexity int
+	for _, sel := range set.Selections {
+		var d, c int
+		switch sel := sel.(type) {
+		case *ast.Field:
+			d, c = a.selectionSet(sel.SelectionSet, false)
+			multiplier := 1
+			if root {
+				multiplier = a.multiplier(sel)
+			}
+			d, c = d+1, 1+c*multiplier
+		case *ast.InlineFragment:
+			d, c = a.selectionSet(sel.SelectionSet, root)
+		case *ast.FragmentSpread:
+			if frag, ok := a.fragments[sel.Name.Value]; ok {
+				d, c = a.selectionSet(frag.SelectionSet, root)
+			}
+		}
+		depth = max(depth, d)
+		complexity += c
+	}
+	return depth, complexity
+}
+
+// multiplier returns how many times the selections below the root field f are
+// resolved: the requested page size for the customers listing, once otherwise.
+func (a analyzer) multiplier(f *ast.Field) int {
+	if f.Name.Value != "customers" || f.SelectionSet == nil {
+		return 1
+	}
+
+	for _, arg := range f.Arguments {
+		if arg.Name.Value != "pageSize" {
+			continue
+		}
+		switch v := arg.Value.(type) {
+		case *ast.IntValue:
+			if n, err := strconv.Atoi(v.Value); err == nil && n > 0 {
+				return n
+			}
+		case *ast.Variable:
+			if n, ok := toInt(a.variables[v.Name.Value]); ok && n > 0 {
+				return n
+			}
+		}
+	}
+	return customer.DefaultPageSize
+}
+
+func toInt(v any) (int, bool) {
+	switch v := v.(type) {
+	case int:
+		return v, true
+	case float64:
+		return int(v), true
+	}
+	return 0, false
+}
@@ -0,0 +1,304 @@
+// Package graph exposes the customer service through a GraphQL schema.
+package graph
+
+import (
+	"strconv"
+
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// attributesType holds the custom attributes of a customer as a JSON object,
+// numbers being read as in the REST API.
+var attributesType = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Attributes",
+	Description: "The custom attributes of a customer, as a JSON object keyed by attribute name.",
+	Serialize:   func(value any) any { return value },
+	ParseValue: func(value any) any {
+		if attributes, ok := value.(map[string]any); ok {
+			return attributes
+		}
+		return nil
+	},
+	ParseLiteral: func(valueAST ast.Value) any {
+		if object, ok := valueAST.(*ast.ObjectValue); ok {
+			return literal(object)
+		}
+		return nil
+	},
+})
+
+// literal converts an inline GraphQL value to the value JSON would decode to.
+func literal(valueAST ast.Value) any {
+	switch v := valueAST.(type) {
+	case *ast.IntValue:
+		n, _ := strconv.ParseFloat(v.Value, 64)
+		return n
+	case *ast.FloatValue:
+		n, _ := strconv.ParseFloat(v.Value, 64)
+		return n
+	case *ast.StringValue:
+		return v.Value
+	case *ast.BooleanValue:
+		return v.Value
+	case *ast.EnumValue:
+		return v.Value
+	case *ast.ListValue:
+		list := make([]any, 0, len(v.Values))
+		for _, item := range v.Values {
+			list = append(list, literal(item))
+		}
+		return list
+	case *ast.ObjectValue:
+		object := make(map[string]any, len(v.Fields))
+		for _, field := range v.Fields {
+			object[field.Name.Value] = literal(field.Value)
+		}
+		return object
+	}
+	return nil
+}
+
+var customerType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Customer",
+	Fields: graphql.Fields{
+		"id":             customerField(graphql.NewNonNull(graphql.Int), func(c dto.ResultCustomerRequest) any { return c.ID }),
+		"customerNumber": customerField(graphql.Int, func(c dto.ResultCustomerRequest) any { return c.CustomerNumber }),
+		"firstName":      customerField(graphql.NewNonNull(graphql.String), func(c dto.ResultCustomerRequest) any { return c.FirstName }),
+		"lastName":       customerField(graphql.NewNonNull(graphql.String), func(c dto.ResultCustomerRequest) any { return c.LastName }),
+		"status":         customerField(graphql.NewNonNull(graphql.String), func(c dto.ResultCustomerRequest) any { return c.Status }),
+		"createdAt":      customerField(graphql.DateTime, func(c dto.ResultCustomerRequest) any { return c.CreatedAt }),
+		"updatedAt":      customerField(graphql.DateTime, func(c dto.ResultCustomerRequest) any { return c.UpdatedAt }),
+		"attributes":     customerField(attributesType, func(c dto.ResultCustomerRequest) any { return c.Attributes }),
+		"parentId":       customerField(graphql.Int, func(c dto.ResultCustomerRequest) any { return c.ParentID }),
+	},
+})
+
+var customerPageType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "CustomerPage",
+	Fields: graphql.Fields{
+		"customers": pageField(graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(customerType))), func(p dto.CustomerPage) any { return p.Customers }),
+		"page":      pageField(graphql.NewNonNull(graphql.Int), func(p dto.CustomerPage) any { return p.Page }),
+		"pageSize":  pageField(graphql.NewNonNull(graphql.Int), func(p dto.CustomerPage) any { return p.PageSize }),
+		"total":     pageField(graphql.NewNonNull(graphql.Int), func(p dto.CustomerPage) any { return p.Total }),
+	},
+})
+
+var customerFilterType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "CustomerFilter",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"customerNumber": &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		"firstName":      &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "Matches the first names starting with the value."},
+		"lastName":       &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "Matches the last names starting with the value."},
+		"email":          &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "Matches the customers with this email, in any case."},
+		"status":         &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "One of prospect, active, suspended or closed."},
+		"tags":           &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String)), Description: "Matches the customers carrying the tags, regardless of case."},
+		"tagMatch":       &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "Whether the customers carry any (default) or all of the tags."},
+		"attributes":     &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String)), Description: "Matches the customers holding every custom attribute value, each given as name:value."},
+	},
+})
+
+var customerInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "CustomerInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"customerNumber": &graphql.InputObjectFieldConfig{Type: graphql.Int, Description: "Required on update. Allocated by the server on create when left out and numbering is enabled."},
+		"firstName":      &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"lastName":       &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"attributes":     &graphql.InputObjectFieldConfig{Type: attributesType, Description: "Replaced as a whole on update, so left out clears them."},
+		"parentId":       &graphql.InputObjectFieldConfig{Type: graphql.Int, Description: "The parent customer. Left out on update, the customer is detached."},
+	},
+})
+
+// NewSchema returns the GraphQL schema whose queries and mutations delegate to s.
+func NewSchema(s customer.Service) (graphql.Schema, error) {
+	r := &resolver{service: s}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"customer": &graphql.Field{
+				Type:    customerType,
+				Args:    graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.Int)}},
+				Resolve: r.customer,
+			},
+			"customerByNumber": &graphql.Field{
+				Type:    customerType,
+				Args:    graphql.FieldConfigArgument{"customerNumber": {Type: graphql.NewNonNull(graphql.Int)}},
+				Resolve: r.customerByNumber,
+			},
+			"customers": &graphql.Field{
+				Type: graphql.NewNonNull(customerPageType),
+				Args: graphql.FieldConfigArgument{
+					"filter":   {Type: customerFilterType},
+					"page":     {Type: graphql.Int, DefaultValue: 1},
+					"pageSize": {Type: graphql.Int, DefaultValue: customer.DefaultPageSize},
+				},
+				Resolve: r.customers,
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createCustomer": &graphql.Field{
+				Type:    graphql.NewNonNull(customerType),
+				Args:    graphql.FieldConfigArgument{"input": {Type: graphql.NewNonNull(customerInputType)}},
+				Resolve: r.createCustomer,
+			},
+			"updateCustomer": &graphql.Field{
+				Type: graphql.NewNonNull(customerType),
+				Args: graphql.FieldConfigArgument{
+					"id":    {Type: graphql.NewNonNull(graphql.Int)},
+					"input": {Type: graphql.NewNonNull(customerInputType)},
+				},
+				Resolve: r.updateCustomer,
+			},
+			"deleteCustomer": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.Boolean),
+				Args:    graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.Int)}},
+				Resolve: r.deleteCustomer,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+type resolver struct {
+	service customer.Service
+}
+
+func (r *resolver) customer(p graphql.ResolveParams) (any, error) {
+	c, err := r.service.Get(p.Context, p.Args["id"].(int))
+	if err != nil {
+		return nil, resolveError(err)
+	}
+	return c, nil
+}
+
+func (r *resolver) customerByNumber(p graphql.ResolveParams) (any, error) {
+	c, err := r.service.GetByCustomerNumber(p.Context, p.Args["customerNumber"].(int))
+	if err != nil {
+		return nil, resolveError(err)
+	}
+	return c, nil
+}
+
+func (r *resolver) customers(p graphql.ResolveParams) (any, error) {
+	f := dto.CustomerFilter{
+		Page:     p.Args["page"].(int),
+		PageSize: p.Args["pageSize"].(int),
+	}
+	if filter, ok := p.Args["filter"].(map[string]any); ok {
+		if number, ok := filter["customerNumber"].(int); ok {
+			f.CustomerNumber = &number
+		}
+		f.FirstName, _ = filter["firstName"].(string)
+		f.LastName, _ = filter["lastName"].(string)
+		f.Email, _ = filter["email"].(string)
+		f.Status, _ = filter["status"].(string)
+		f.TagMatch, _ = filter["tagMatch"].(string)
+		if tags, ok := filter["tags"].([]any); ok {
+			for _, tag := range tags {
+				name, _ := tag.(string)
+				f.Tags = append(f.Tags, name)
+			}
+		}
+		if attributes, ok := filter["attributes"].([]any); ok {
+			for _, attribute := range attributes {
+				value, _ := attribute.(string)
+				f.Attributes = append(f.Attributes, value)
+			}
+		}
+	}
+	if err := binding.Validator.ValidateStruct(&f); err != nil {
+		return nil, resolveError(err)
+	}
+
+	page, err := r.service.List(p.Context, f)
+	if err != nil {
+		return nil, resolveError(err)
+	}
+	return page, nil
+}
+
+func (r *resolver) createCustomer(p graphql.ResolveParams) (any, error) {
+	input := customerInput(p.Args["input"])
+	if err := binding.Validator.ValidateStruct(&input); err != nil {
+		return nil, resolveError(err)
+	}
+
+	c, err := r.service.Save(p.Context, input)
+	if err != nil {
+		return nil, resolveError(err)
+	}
+	return c, nil
+}
+
+func (r *resolver) updateCustomer(p graphql.ResolveParams) (any, error) {
+	input := dto.UpdateCustomerRequest(customerInput(p.Args["input"]))
+	if err := binding.Validator.ValidateStruct(&input); err != nil {
+		return nil, resolveError(err)
+	}
+
+	c, err := r.service.Update(p.Context, input, p.Args["id"].(int))
+	if err != nil {
+		return nil, resolveError(err)
+	}
+	return c, nil
+}
+
+func (r *resolver) deleteCustomer(p graphql.ResolveParams) (any, error) {
+	if err := r.service.Delete(p.Context, p.Args["id"].(int)); err != nil {
+		return nil, resolveError(err)
+	}
+	return true, nil
+}
+
+// customerInput returns the fields of a CustomerInput, the customer number and
+// the parent being nil when left out.
+func customerInput(arg any) dto.CreateCustomerRequest {
+	input, _ := arg.(map[string]any)
+	var c dto.CreateCustomerRequest
+	if n, ok := input["customerNumber"].(int); ok {
+		c.CustomerNumber = &n
+	}
+	if id, ok := input["parentId"].(int); ok {
+		c.ParentID = &id
+	}
+	if attributes, ok := input["attributes"].(map[string]any); ok {
+		c.Attributes = domain.Attributes(attributes)
+	}
+	c.FirstName, _ = input["firstName"].(string)
+	c.LastName, _ = input["lastName"].(string)
+	return c
+}
+
+func customerField(t graphql.Output, get func(dto.ResultCustomerRequest) any) *graphql.Field {
+	return &graphql.Field{
+		Type: t,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			if c, ok := p.Source.(dto.ResultCustomerRequest); ok {
+				return get(c), nil
+			}
+			return nil, nil
+		},
+	}
+}
+
+func pageField(t graphql.Output, get func(dto.CustomerPage) any) *graphql.Field {
+	return &graphql.Field{
+		Type: t,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			if page, ok := p.Source.(dto.CustomerPage); ok {
+				return get(page), nil
+			}
+			return nil, nil
+		},
+	}
+}
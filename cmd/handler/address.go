@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/danilosano/web-golang-api/internal/address"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	web.RegisterError(address.ErrorAddressNotFound, http.StatusNotFound)
+}
+
+type AddressHandler struct {
+	service address.Service
+}
+
+func NewAddressHandler(s address.Service) *AddressHandler {
+	return &AddressHandler{
+		service: s,
+	}
+}
+
+// CreateAddress godoc
+// @Summary Create customer address
+// @Tags Addresses
+// @Description Add a billing or shipping address to a customer. The postal code must match the format of the country. A default address replaces the previous default of its type.
+// @Accept json,xml,application/msgpack
+// @Produce json,xml,application/msgpack
+// @Param id path int true "Customer ID"
+// @Param address body dto.AddressRequest true "Address to be created"
+// @Success 201 {object} web.Responses{data=domain.Address} "Success"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 422 {object} web.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/addresses [post]
+func (a *AddressHandler) Store(c *gin.Context) {
+	customerID, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.AddressRequest
+	if err := web.ShouldBind(c, &req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	addr, err := a.service.Save(c.Request.Context(), req, customerID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusCreated, addr)
+}
+
+// GetAddresses godoc
+// @Summary List customer addresses
+// @Tags Addresses
+// @Produce json,xml,application/msgpack,text/csv
+// @Param id path int true "Customer ID"
+// @Success 200 {object} web.Responses{data=[]domain.Address} "Success"
+// @Success 204 "No Content"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/addresses [get]
+func (a *AddressHandler) GetAll(c *gin.Context) {
+	customerID, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	addresses, err := a.service.GetAll(c.Request.Context(), customerID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if addresses == nil {
+		web.Success(c, http.StatusNoContent, addresses)
+		return
+	}
+
+	web.Success(c, http.StatusOK, addresses)
+}
+
+// GetAddress godoc
+// @Summary Get customer address
+// @Tags Addresses
+// @Produce json,xml,application/msgpack
+// @Param id path int true "Customer ID"
+// @Param address_id path int true "Address ID"
+// @Success 200 {object} web.Responses{data=domain.Address} "Success"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/addresses/{address_id} [get]
+func (a *AddressHandler) Get(c *gin.Context) {
+	customerID, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	id, ok := IDParam(c, "address_id")
+	if !ok {
+		return
+	}
+
+	addr, err := a.service.Get(c.Request.Context(), customerID, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusOK, addr)
+}
+
+// UpdateAddress godoc
+// @Summary Update customer address
+// @Tags Addresses
+// @Accept json,xml,application/msgpack
+// @Produce json,xml,application/msgpack
+// @Param id path int true "Customer ID"
+// @Param address_id path int true "Address ID"
+// @Param address body dto.AddressRequest true "Address to be updated"
+// @Success 200 {object} web.Responses{data=domain.Address} "Success"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 422 {object} web.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/addresses/{address_id} [put]
+func (a *AddressHandler) Update(c *gin.Context) {
+	customerID, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	id, ok := IDParam(c, "address_id")
+	if !ok {
+		return
+	}
+
+	var req dto.AddressRequest
+	if err := web.ShouldBind(c, &req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	addr, err := a.service.Update(c.Request.Context(), req, customerID, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusOK, addr)
+}
+
+// DeleteAddress godoc
+// @Summary Delete customer address
+// @Tags Addresses
+// @Param id path int true "Customer ID"
+// @Param address_id path int true "Address ID"
+// @Success 204
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/addresses/{address_id} [delete]
+func (a *AddressHandler) Delete(c *gin.Context) {
+	customerID, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	id, ok := IDParam(c, "address_id")
+	if !ok {
+		return
+	}
+
+	if err := a.service.Delete(c.Request.Context(), customerID, id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusNoContent, nil)
+}
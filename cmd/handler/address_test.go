@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/address"
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/middleware"
+	mocks "github.com/danilosano/web-golang-api/pkg/tests/addresses"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	mockedAddress = domain.Address{
+		ID:         1,
+		CustomerID: 1,
+		Type:       domain.AddressTypeShipping,
+		Line1:      "Avenida Paulista, 1000",
+		City:       "São Paulo",
+		Region:     "SP",
+		PostalCode: "01310-100",
+		Country:    "BR",
+		Default:    true,
+		CreatedAt:  time.Date(2021, 10, 10, 0, 0, 0, 0, time.UTC),
+	}
+
+	addressInput = dto.AddressRequest{
+		Type:       domain.AddressTypeShipping,
+		Line1:      "Avenida Paulista, 1000",
+		City:       "São Paulo",
+		Region:     "SP",
+		PostalCode: "01310-100",
+		Country:    "BR",
+		Default:    true,
+	}
+
+	jsonAddressInput = `{
+		"type": "shipping",
+		"line1": "Avenida Paulista, 1000",
+		"city": "São Paulo",
+		"region": "SP",
+		"postal_code": "01310-100",
+		"country": "BR",
+		"default": true
+	}`
+)
+
+func InitServerWithAddressesRoute(t *testing.T) (*gin.Engine, *mocks.AddressServiceMock, context.Context) {
+	t.Helper()
+	server := testutil.CreateServer()
+	server.Use(middleware.ErrorHandler())
+	mockService := new(mocks.AddressServiceMock)
+	handler := NewAddressHandler(mockService)
+	server.POST(pathCustomer+":id/addresses", handler.Store)
+	server.GET(pathCustomer+":id/addresses", handler.GetAll)
+	server.GET(pathCustomer+":id/addresses/:address_id", handler.Get)
+	server.PUT(pathCustomer+":id/addresses/:address_id", handler.Update)
+	server.DELETE(pathCustomer+":id/addresses/:address_id", handler.Delete)
+	return server, mockService, context.Background()
+}
+
+func TestStoreAddress(t *testing.T) {
+	t.Run("When the address is valid, it is created and returned with a 201 code.", func(t *testing.T) {
+		var result struct {
+			Data domain.Address `json:"data"`
+		}
+		server, service, ctx := InitServerWithAddressesRoute(t)
+		service.On("Save", ctx, addressInput, 1).Return(mockedAddress, nil)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathCustomer+"1/addresses", jsonAddressInput)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &result)
+		assert.Nil(t, err)
+		assert.Equal(t, mockedAddress, result.Data)
+	})
+
+	t.Run("When the postal code does not match the country, a 400 code will be returned.", func(t *testing.T) {
+		var resp web.ErrorResponse
+		server, _, _ := InitServerWithAddressesRoute(t)
+
+		body := `{"type": "billing", "line1": "1600 Amphitheatre Pkwy", "city": "Mountain View", "postal_code": "01310-100", "country": "US"}`
+		request, response := testutil.MakeRequest(http.MethodPost, pathCustomer+"1/addresses", body)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.Equal(t, []web.FieldError{{Field: "postal_code", Message: "must be a valid postal code of the country"}}, resp.Fields)
+	})
+
+	t.Run("When the type and the country are unknown, a 400 code will be returned.", func(t *testing.T) {
+		var resp web.ErrorResponse
+		server, _, _ := InitServerWithAddressesRoute(t)
+
+		body := `{"type": "home", "line1": "Rua A", "city": "Rio", "postal_code": "20000-000", "country": "XX"}`
+		request, response := testutil.MakeRequest(http.MethodPost, pathCustomer+"1/addresses", body)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.Contains(t, resp.Fields, web.FieldError{Field: "type", Message: "must be one of: billing, shipping"})
+		assert.Contains(t, resp.Fields, web.FieldError{Field: "country", Message: "must be an ISO 3166-1 alpha-2 country code"})
+	})
+
+	t.Run("When the customer does not exist, a 404 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithAddressesRoute(t)
+		service.On("Save", ctx, addressInput, 9).Return(nil, customer.ErrorCustomerNotFound)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathCustomer+"9/addresses", jsonAddressInput)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestGetAllAddresses(t *testing.T) {
+	t.Run("When the customer has addresses, they are returned with a 200 code.", func(t *testing.T) {
+		var result struct {
+			Data []domain.Address `json:"data"`
+		}
+		server, service, ctx := InitServerWithAddressesRoute(t)
+		service.On("GetAll", ctx, 1).Return([]domain.Address{mockedAddress}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1/addresses", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &result)
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.Address{mockedAddress}, result.Data)
+	})
+
+	t.Run("When the customer has no addresses, a 204 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithAddressesRoute(t)
+		service.On("GetAll", ctx, 1).Return(nil, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1/addresses", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+}
+
+func TestGetAddress(t *testing.T) {
+	t.Run("When the address exists, it is returned with a 200 code.", func(t *testing.T) {
+		server, service, ctx := InitServerWithAddressesRoute(t)
+		service.On("Get", ctx, 1, 1).Return(mockedAddress, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1/addresses/1", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("When the address does not exist, a 404 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithAddressesRoute(t)
+		service.On("Get", ctx, 1, 2).Return(nil, address.ErrorAddressNotFound)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1/addresses/2", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("When the address id is wrong, a 400 code will be returned.", func(t *testing.T) {
+		server, _, _ := InitServerWithAddressesRoute(t)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1/addresses/a", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestUpdateAddress(t *testing.T) {
+	t.Run("When the address is valid, it is updated and returned with a 200 code.", func(t *testing.T) {
+		server, service, ctx := InitServerWithAddressesRoute(t)
+		service.On("Update", ctx, addressInput, 1, 1).Return(mockedAddress, nil)
+
+		request, response := testutil.MakeRequest(http.MethodPut, pathCustomer+"1/addresses/1", jsonAddressInput)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("When the address does not exist, a 404 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithAddressesRoute(t)
+		service.On("Update", ctx, addressInput, 1, 2).Return(nil, address.ErrorAddressNotFound)
+
+		request, response := testutil.MakeRequest(http.MethodPut, pathCustomer+"1/addresses/2", jsonAddressInput)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestDeleteAddress(t *testing.T) {
+	t.Run("When the address exists, it is deleted with a 204 code.", func(t *testing.T) {
+		server, service, ctx := InitServerWithAddressesRoute(t)
+		service.On("Delete", ctx, 1, 1).Return(nil)
+
+		request, response := testutil.MakeRequest(http.MethodDelete, pathCustomer+"1/addresses/1", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+
+	t.Run("When the address does not exist, a 404 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithAddressesRoute(t)
+		service.On("Delete", ctx, 1, 2).Return(address.ErrorAddressNotFound)
+
+		request, response := testutil.MakeRequest(http.MethodDelete, pathCustomer+"1/addresses/2", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
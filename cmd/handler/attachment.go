@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/danilosano/web-golang-api/internal/attachment"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/blob"
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// multipartOverhead is the room left in the request body for the multipart
+// boundaries and the other fields of the form.
+const multipartOverhead = 64 << 10
+
+func init() {
+	web.RegisterError(attachment.ErrorAttachmentNotFound, http.StatusNotFound)
+	web.RegisterError(attachment.ErrorAttachmentTooLarge, http.StatusRequestEntityTooLarge)
+	web.RegisterError(attachment.ErrorUnsupportedMediaType, http.StatusUnsupportedMediaType)
+	web.RegisterError(attachment.ErrorChecksumMismatch, http.StatusUnprocessableEntity)
+	web.RegisterError(blob.ErrorBlobNotFound, http.StatusNotFound)
+}
+
+type AttachmentHandler struct {
+	service attachment.Service
+	maxSize int64
+}
+
+// NewAttachmentHandler returns a handler refusing request bodies that cannot
+// hold a file of at most maxSize bytes.
+func NewAttachmentHandler(s attachment.Service, maxSize int64) *AttachmentHandler {
+	return &AttachmentHandler{
+		service: s,
+		maxSize: maxSize,
+	}
+}
+
+// UploadAttachment godoc
+// @Summary Upload customer attachment
+// @Tags Attachments
+// @Description Attach a document to a customer. The media type is detected from the content, and the optional sha256 field is checked against the file received.
+// @Accept multipart/form-data
+// @Produce json,xml,application/msgpack
+// @Param id path int true "Customer ID"
+// @Param file formData file true "File to be attached"
+// @Param sha256 formData string false "Hex-encoded SHA-256 of the file"
+// @Success 201 {object} web.Responses{data=domain.Attachment} "Success"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 413 {object} web.ErrorResponse "Request Entity Too Large"
+// @Failure 415 {object} web.ErrorResponse "Unsupported Media Type"
+// @Failure 422 {object} web.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/attachments [post]
+func (h *AttachmentHandler) Store(c *gin.Context) {
+	customerID, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(c.ContentType()); mediaType != binding.MIMEMultipartPOSTForm {
+		_ = c.Error(fmt.Errorf("%w: %s", web.ErrUnsupportedMediaType, c.ContentType()))
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxSize+multipartOverhead)
+	var req dto.AttachmentRequest
+	if err := c.ShouldBindWith(&req, binding.FormMultipart); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			_ = c.Error(attachment.ErrorAttachmentTooLarge)
+			return
+		}
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	file, err := req.File.Open()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	defer file.Close()
+
+	result, err := h.service.Upload(c.Request.Context(), customerID, dto.AttachmentFile{
+		Name:     req.File.Filename,
+		Size:     req.File.Size,
+		Content:  file,
+		Checksum: req.Checksum,
+	})
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusCreated, result)
+}
+
+// GetAttachments godoc
+// @Summary List customer attachments
+// @Tags Attachments
+// @Description Get the attachments of a customer, newest first
+// @Produce json,xml,application/msgpack
+// @Param id path int true "Customer ID"
+// @Success 200 {object} web.Responses{data=[]domain.Attachment} "Success"
+// @Success 204 "No Content"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/attachments [get]
+func (h *AttachmentHandler) GetAll(c *gin.Context) {
+	customerID, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	attachments, err := h.service.GetAll(c.Request.Context(), customerID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if attachments == nil {
+		web.Success(c, http.StatusNoContent, attachments)
+		return
+	}
+
+	web.Success(c, http.StatusOK, attachments)
+}
+
+// DownloadAttachment godoc
+// @Summary Download customer attachment
+// @Tags Attachments
+// @Description Get the content of an attachment. The Digest header holds its SHA-256.
+// @Produce octet-stream
+// @Param id path int true "Customer ID"
+// @Param attachment_id path int true "Attachment ID"
+// @Success 200 {file} file "Success"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/attachments/{attachment_id} [get]
+func (h *AttachmentHandler) Download(c *gin.Context) {
+	customerID, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	id, ok := IDParam(c, "attachment_id")
+	if !ok {
+		return
+	}
+
+	a, content, err := h.service.Download(c.Request.Context(), customerID, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	defer content.Close()
+
+	headers := map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": a.FileName}),
+		"ETag":                `"` + a.Checksum + `"`,
+	}
+	if sum, err := hex.DecodeString(a.Checksum); err == nil {
+		headers["Digest"] = "sha-256=" + base64.StdEncoding.EncodeToString(sum)
+	}
+
+	c.DataFromReader(http.StatusOK, a.Size, a.ContentType, content, headers)
+}
+
+// DeleteAttachment godoc
+// @Summary Delete customer attachment
+// @Tags Attachments
+// @Param id path int true "Customer ID"
+// @Param attachment_id path int true "Attachment ID"
+// @Success 204
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/attachments/{attachment_id} [delete]
+func (h *AttachmentHandler) Delete(c *gin.Context) {
+	customerID, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	id, ok := IDParam(c, "attachment_id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), customerID, id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusNoContent, nil)
+}
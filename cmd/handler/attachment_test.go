@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/attachment"
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/middleware"
+	mocks "github.com/danilosano/web-golang-api/pkg/tests/attachments"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	attachmentContent = "%PDF-1.7\n"
+
+	mockedAttachment = domain.Attachment{
+		ID:          1,
+		CustomerID:  1,
+		FileName:    "contrat signé.pdf",
+		ContentType: "application/pdf",
+		Size:        int64(len(attachmentContent)),
+		Checksum:    "a0f3a0d2bfa1a8c5bc0f1c0f2f0a8c1d4c3a3e1b8f2c9e0d7b6a5f4e3d2c1b0a",
+		UploadedBy:  "agent-1",
+		CreatedAt:   time.Date(2021, 10, 10, 0, 0, 0, 0, time.UTC),
+	}
+)
+
+func InitServerWithAttachmentsRoute(t *testing.T) (*gin.Engine, *mocks.AttachmentServiceMock) {
+	t.Helper()
+	server := testutil.CreateServer()
+	server.Use(middleware.ErrorHandler())
+	mockService := new(mocks.AttachmentServiceMock)
+	handler := NewAttachmentHandler(mockService, 1<<10)
+	server.POST(pathCustomer+":id/attachments", handler.Store)
+	server.GET(pathCustomer+":id/attachments", handler.GetAll)
+	server.GET(pathCustomer+":id/attachments/:attachment_id", handler.Download)
+	server.DELETE(pathCustomer+":id/attachments/:attachment_id", handler.Delete)
+	return server, mockService
+}
+
+// makeUploadRequest builds a multipart request holding content as the file
+// field, along with the other fields given.
+func makeUploadRequest(t *testing.T, url, name, content string, fields map[string]string) (*http.Request, *httptest.ResponseRecorder) {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for field, value := range fields {
+		assert.NoError(t, form.WriteField(field, value))
+	}
+	if name != "" {
+		part, err := form.CreateFormFile("file", name)
+		assert.NoError(t, err)
+		_, err = io.WriteString(part, content)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, form.Close())
+
+	request := httptest.NewRequest(http.MethodPost, url, &body)
+	request.Header.Set("Content-Type", form.FormDataContentType())
+	return request, httptest.NewRecorder()
+}
+
+func TestStoreAttachment(t *testing.T) {
+	t.Run("The uploaded file is passed to the service and the attachment returned with a 201 code.", func(t *testing.T) {
+		var result struct {
+			Data domain.Attachment `json:"data"`
+		}
+		server, service := InitServerWithAttachmentsRoute(t)
+		service.On("Upload", mock.Anything, 1, mock.MatchedBy(func(f dto.AttachmentFile) bool {
+			content, _ := io.ReadAll(f.Content)
+			return f.Name == "contrat signé.pdf" && f.Size == int64(len(attachmentContent)) &&
+				string(content) == attachmentContent && f.Checksum == mockedAttachment.Checksum
+		})).Return(mockedAttachment, nil)
+
+		request, response := makeUploadRequest(t, pathCustomer+"1/attachments", "contrat signé.pdf", attachmentContent, map[string]string{"sha256": mockedAttachment.Checksum})
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &result)
+		assert.Nil(t, err)
+		assert.Equal(t, mockedAttachment, result.Data)
+	})
+
+	t.Run("If the file is missing or the checksum malformed, a 400 code will be returned.", func(t *testing.T) {
+		server, service := InitServerWithAttachmentsRoute(t)
+
+		request, response := makeUploadRequest(t, pathCustomer+"1/attachments", "", "", nil)
+		server.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+
+		request, response = makeUploadRequest(t, pathCustomer+"1/attachments", "contract.pdf", attachmentContent, map[string]string{"sha256": "abc"})
+		server.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		service.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("If the body is not a multipart form, a 415 code will be returned.", func(t *testing.T) {
+		server, _ := InitServerWithAttachmentsRoute(t)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathCustomer+"1/attachments", `{"file": "contract.pdf"}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, response.Code)
+	})
+
+	t.Run("If the body is over the limit, a 413 code will be returned before reaching the service.", func(t *testing.T) {
+		server, service := InitServerWithAttachmentsRoute(t)
+
+		request, response := makeUploadRequest(t, pathCustomer+"1/attachments", "contract.pdf", strings.Repeat("a", 1<<17), nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, response.Code)
+		service.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("The errors of the service are mapped to their status codes.", func(t *testing.T) {
+		for err, status := range map[error]int{
+			customer.ErrorCustomerNotFound:       http.StatusNotFound,
+			attachment.ErrorAttachmentTooLarge:   http.StatusRequestEntityTooLarge,
+			attachment.ErrorUnsupportedMediaType: http.StatusUnsupportedMediaType,
+			attachment.ErrorChecksumMismatch:     http.StatusUnprocessableEntity,
+		} {
+			server, service := InitServerWithAttachmentsRoute(t)
+			service.On("Upload", mock.Anything, 1, mock.Anything).Return(nil, err)
+
+			request, response := makeUploadRequest(t, pathCustomer+"1/attachments", "contract.pdf", attachmentContent, nil)
+			server.ServeHTTP(response, request)
+
+			assert.Equal(t, status, response.Code, err.Error())
+		}
+	})
+}
+
+func TestGetAllAttachments(t *testing.T) {
+	t.Run("The attachments of the customer are returned with a 200 code.", func(t *testing.T) {
+		server, service := InitServerWithAttachmentsRoute(t)
+		service.On("GetAll", mock.Anything, 1).Return([]domain.Attachment{mockedAttachment}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1/attachments", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.NotContains(t, response.Body.String(), "storage_key")
+	})
+
+	t.Run("If the customer has no attachment, a 204 code will be returned.", func(t *testing.T) {
+		server, service := InitServerWithAttachmentsRoute(t)
+		service.On("GetAll", mock.Anything, 1).Return(nil, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1/attachments", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+}
+
+func TestDownloadAttachment(t *testing.T) {
+	t.Run("The content is returned with its type, name and digest.", func(t *testing.T) {
+		server, service := InitServerWithAttachmentsRoute(t)
+		service.On("Download", mock.Anything, 1, 1).Return(mockedAttachment, io.NopCloser(strings.NewReader(attachmentContent)), nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1/attachments/1", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, attachmentContent, response.Body.String())
+		assert.Equal(t, "application/pdf", response.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename*=utf-8''contrat%20sign%C3%A9.pdf`, response.Header().Get("Content-Disposition"))
+		assert.Equal(t, `"`+mockedAttachment.Checksum+`"`, response.Header().Get("ETag"))
+		assert.Equal(t, "sha-256=oPOg0r+hqMW8DxwPLwqMHUw6PhuPLJ4Ne2pfTj0sGwo=", response.Header().Get("Digest"))
+	})
+
+	t.Run("If the attachment does not exist, a 404 code will be returned.", func(t *testing.T) {
+		server, service := InitServerWithAttachmentsRoute(t)
+		service.On("Download", mock.Anything, 1, 9).Return(nil, nil, attachment.ErrorAttachmentNotFound)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1/attachments/9", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestDeleteAttachment(t *testing.T) {
+	t.Run("If the deletion is successful, a 204 code will be returned.", func(t *testing.T) {
+		server, service := InitServerWithAttachmentsRoute(t)
+		service.On("Delete", mock.Anything, 1, 1).Return(nil)
+
+		request, response := testutil.MakeRequest(http.MethodDelete, pathCustomer+"1/attachments/1", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+}
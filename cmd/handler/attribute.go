@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/danilosano/web-golang-api/internal/attribute"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type AttributeHandler struct {
+	service attribute.Service
+}
+
+func NewAttributeHandler(s attribute.Service) *AttributeHandler {
+	return &AttributeHandler{
+		service: s,
+	}
+}
+
+// CreateAttribute godoc
+// @Summary Create attribute definition
+// @Tags Attributes
+// @Description Define a custom attribute of the customers. Its name is the key of the value in the attributes of a customer.
+// @Accept json
+// @Produce json
+// @Param attribute body dto.AttributeDefinitionRequest true "Definition to be created"
+// @Success 201 {object} web.Responses{data=domain.AttributeDefinition} "Success"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 409 {object} web.ErrorResponse "Conflict"
+// @Failure 422 {object} web.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/attributes [post]
+func (a *AttributeHandler) Store(c *gin.Context) {
+	var req dto.AttributeDefinitionRequest
+	if err := web.ShouldBind(c, &req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	definition, err := a.service.Save(c.Request.Context(), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusCreated, definition)
+}
+
+// GetAttributes godoc
+// @Summary List attribute definitions
+// @Tags Attributes
+// @Produce json
+// @Success 200 {object} web.Responses{data=[]domain.AttributeDefinition} "Success"
+// @Success 204 "No Content"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/attributes [get]
+func (a *AttributeHandler) GetAll(c *gin.Context) {
+	definitions, err := a.service.GetAll(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if definitions == nil {
+		web.Success(c, http.StatusNoContent, definitions)
+		return
+	}
+
+	web.Success(c, http.StatusOK, definitions)
+}
+
+// GetAttribute godoc
+// @Summary Get attribute definition
+// @Tags Attributes
+// @Produce json
+// @Param id path int true "Attribute ID"
+// @Success 200 {object} web.Responses{data=domain.AttributeDefinition} "Success"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/attributes/{id} [get]
+func (a *AttributeHandler) Get(c *gin.Context) {
+	id, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	definition, err := a.service.Get(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusOK, definition)
+}
+
+// UpdateAttribute godoc
+// @Summary Update attribute definition
+// @Tags Attributes
+// @Description Replace a definition. The values customers already hold are checked the next time they are updated.
+// @Accept json
+// @Produce json
+// @Param id path int true "Attribute ID"
+// @Param attribute body dto.AttributeDefinitionRequest true "Definition to be updated"
+// @Success 200 {object} web.Responses{data=domain.AttributeDefinition} "Success"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 409 {object} web.ErrorResponse "Conflict"
+// @Failure 422 {object} web.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/attributes/{id} [put]
+func (a *AttributeHandler) Update(c *gin.Context) {
+	id, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.AttributeDefinitionRequest
+	if err := web.ShouldBind(c, &req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	definition, err := a.service.Update(c.Request.Context(), req, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusOK, definition)
+}
+
+// DeleteAttribute godoc
+// @Summary Delete attribute definition
+// @Tags Attributes
+// @Param id path int true "Attribute ID"
+// @Success 204
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/attributes/{id} [delete]
+func (a *AttributeHandler) Delete(c *gin.Context) {
+	id, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := a.service.Delete(c.Request.Context(), id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusNoContent, nil)
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/attribute"
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/middleware"
+	mocks "github.com/danilosano/web-golang-api/pkg/tests/attributes"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+const pathAttribute = "/api/v1/attributes/"
+
+var (
+	mockedAttribute = domain.AttributeDefinition{
+		ID:        1,
+		Name:      "tier",
+		Type:      domain.AttributeTypeEnum,
+		Required:  true,
+		Options:   []string{"silver", "gold"},
+		CreatedAt: time.Date(2021, 10, 10, 0, 0, 0, 0, time.UTC),
+	}
+
+	attributeInput = dto.AttributeDefinitionRequest{
+		Name:     "tier",
+		Type:     domain.AttributeTypeEnum,
+		Required: true,
+		Options:  []string{"silver", "gold"},
+	}
+
+	jsonAttributeInput = `{"name": "tier", "type": "enum", "required": true, "options": ["silver", "gold"]}`
+)
+
+func InitServerWithAttributesRoute(t *testing.T) (*gin.Engine, *mocks.AttributeServiceMock, context.Context) {
+	t.Helper()
+	server := testutil.CreateServer()
+	server.Use(middleware.ErrorHandler())
+	mockService := new(mocks.AttributeServiceMock)
+	handler := NewAttributeHandler(mockService)
+	server.POST(pathAttribute, handler.Store)
+	server.GET(pathAttribute, handler.GetAll)
+	server.GET(pathAttribute+":id", handler.Get)
+	server.PUT(pathAttribute+":id", handler.Update)
+	server.DELETE(pathAttribute+":id", handler.Delete)
+	return server, mockService, context.Background()
+}
+
+func TestStoreAttribute(t *testing.T) {
+	t.Run("When the definition is valid, it is created and returned with a 201 code.", func(t *testing.T) {
+		var result struct {
+			Data domain.AttributeDefinition `json:"data"`
+		}
+		server, service, ctx := InitServerWithAttributesRoute(t)
+		service.On("Save", ctx, attributeInput).Return(mockedAttribute, nil)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathAttribute, jsonAttributeInput)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &result)
+		assert.Nil(t, err)
+		assert.Equal(t, mockedAttribute, result.Data)
+	})
+
+	t.Run("When the type is unknown, a 400 code will be returned.", func(t *testing.T) {
+		var resp web.ErrorResponse
+		server, _, _ := InitServerWithAttributesRoute(t)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathAttribute, `{"name": "tier", "type": "color"}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.Equal(t, []web.FieldError{{Field: "type", Message: "must be one of: string, number, date, enum"}}, resp.Fields)
+	})
+
+	t.Run("When the definition is inconsistent, a 400 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithAttributesRoute(t)
+		service.On("Save", ctx, attributeInput).Return(nil, fmt.Errorf("%w: an enum needs options", attribute.ErrorInvalidDefinition))
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathAttribute, jsonAttributeInput)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("When the name is taken, a 409 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithAttributesRoute(t)
+		service.On("Save", ctx, attributeInput).Return(nil, attribute.ErrorAttributeAlreadyExist)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathAttribute, jsonAttributeInput)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+}
+
+func TestGetAllAttributes(t *testing.T) {
+	t.Run("When there are definitions, they are returned with a 200 code.", func(t *testing.T) {
+		server, service, ctx := InitServerWithAttributesRoute(t)
+		service.On("GetAll", ctx).Return([]domain.AttributeDefinition{mockedAttribute}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathAttribute, "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("When there are no definitions, a 204 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithAttributesRoute(t)
+		service.On("GetAll", ctx).Return(nil, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathAttribute, "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+}
+
+func TestUpdateAttribute(t *testing.T) {
+	t.Run("When the definition does not exist, a 404 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithAttributesRoute(t)
+		service.On("Update", ctx, attributeInput, 2).Return(nil, attribute.ErrorAttributeNotFound)
+
+		request, response := testutil.MakeRequest(http.MethodPut, pathAttribute+"2", jsonAttributeInput)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestDeleteAttribute(t *testing.T) {
+	t.Run("When the definition exists, it is deleted with a 204 code.", func(t *testing.T) {
+		server, service, ctx := InitServerWithAttributesRoute(t)
+		service.On("Delete", ctx, 1).Return(nil)
+
+		request, response := testutil.MakeRequest(http.MethodDelete, pathAttribute+"1", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+}
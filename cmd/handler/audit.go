@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/danilosano/web-golang-api/internal/audit"
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	service audit.Service
+}
+
+func NewAuditHandler(s audit.Service) *AuditHandler {
+	return &AuditHandler{
+		service: s,
+	}
+}
+
+// GetCustomerHistory godoc
+// @Summary Customer history
+// @Tags Customers
+// @Description Get every change made to a customer, oldest first
+// @Produce json
+// @Param id path int true "Customer ID"
+// @Success 200 {object} web.Responses{data=[]domain.AuditEntry} "Success"
+// @Success 204 "No Content"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/history [get]
+func (a *AuditHandler) History(c *gin.Context) {
+	id, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	entries, err := a.service.GetByCustomerID(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if entries == nil {
+		web.Success(c, http.StatusNoContent, entries)
+		return
+	}
+
+	web.Success(c, http.StatusOK, entries)
+}
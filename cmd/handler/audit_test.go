@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/pkg/middleware"
+	mocks "github.com/danilosano/web-golang-api/pkg/tests/audit"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func InitServerWithAuditRoute(t *testing.T) (*gin.Engine, *mocks.AuditServiceMock, context.Context) {
+	t.Helper()
+	server := testutil.CreateServer()
+	server.Use(middleware.ErrorHandler())
+	mockService := new(mocks.AuditServiceMock)
+	handler := NewAuditHandler(mockService)
+	server.GET(pathCustomer+":id/history", handler.History)
+	return server, mockService, context.Background()
+}
+
+func TestHistory(t *testing.T) {
+	t.Run("When the customer has changes, they are returned with a 200 code.", func(t *testing.T) {
+		var result struct {
+			Data []domain.AuditEntry `json:"data"`
+		}
+		entries := []domain.AuditEntry{{ID: 1, Actor: "agent-1", Action: domain.AuditActionCreate, CustomerID: 1, After: json.RawMessage(`{"id":1}`)}}
+		server, service, ctx := InitServerWithAuditRoute(t)
+		service.On("GetByCustomerID", ctx, 1).Return(entries, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1/history", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &result)
+		assert.Nil(t, err)
+		assert.Equal(t, entries, result.Data)
+	})
+
+	t.Run("When the customer has no changes, a 204 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithAuditRoute(t)
+		service.On("GetByCustomerID", ctx, 1).Return(nil, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1/history", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+
+	t.Run("When the parameter id is wrong, a 400 code will be returned.", func(t *testing.T) {
+		var resp web.ErrorResponse
+		server, _, _ := InitServerWithAuditRoute(t)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"abc/history", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.Equal(t, "invalid input ID", resp.Message)
+	})
+
+	t.Run("When the backend returns an unexpected error, return code 500.", func(t *testing.T) {
+		server, service, ctx := InitServerWithAuditRoute(t)
+		service.On("GetByCustomerID", ctx, 1).Return(nil, errors.New("generic error"))
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1/history", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusInternalServerError, response.Code)
+	})
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+// CustomersNotModified sets the validators of a response holding customers and
+// answers 304 when the client already has it, see web.NotModified. Last-Modified
+// is the latest change among the customers, and the weak ETag a digest of their
+// representation and of the media type negotiated for it, so that it changes
+// with any field, even within the same second, and differs between formats.
+func CustomersNotModified(c *gin.Context, customers ...dto.ResultCustomerRequest) bool {
+	var lastModified time.Time
+	for _, customer := range customers {
+		if modified := customer.LastModified(); modified.After(lastModified) {
+			lastModified = modified
+		}
+	}
+
+	var etag string
+	if body, err := json.Marshal(customers); err == nil {
+		h := sha256.New()
+		h.Write(body)
+		h.Write([]byte(web.Format(c, customers)))
+		etag = fmt.Sprintf(`W/"%s"`, hex.EncodeToString(h.Sum(nil))[:32])
+	}
+
+	web.Vary(c, "Accept")
+	return web.NotModified(c, lastModified, etag)
+}
@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/danilosano/web-golang-api/internal/contact"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	web.RegisterError(contact.ErrorContactNotFound, http.StatusNotFound)
+	web.RegisterError(contact.ErrorPrimaryEmailAlreadyExist, http.StatusConflict)
+	web.RegisterError(contact.ErrorInvalidEmail, http.StatusBadRequest)
+	web.RegisterError(contact.ErrorInvalidPhoneNumber, http.StatusBadRequest)
+
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterStructValidation(validateContact, dto.ContactRequest{})
+	}
+}
+
+// validateContact reports a value that is not an email address or a phone
+// number, as its type requires, as an invalid field.
+func validateContact(sl validator.StructLevel) {
+	req := sl.Current().Interface().(dto.ContactRequest)
+	if req.Value == "" {
+		return
+	}
+
+	switch _, err := contact.Normalize(req.Type, req.Value, req.Region); {
+	case errors.Is(err, contact.ErrorInvalidEmail):
+		sl.ReportError(req.Value, "value", "Value", "email", "")
+	case errors.Is(err, contact.ErrorInvalidPhoneNumber):
+		sl.ReportError(req.Value, "value", "Value", "e164", "")
+	}
+}
+
+type ContactHandler struct {
+	service contact.Service
+}
+
+func NewContactHandler(s contact.Service) *ContactHandler {
+	return &ContactHandler{
+		service: s,
+	}
+}
+
+// CreateContact godoc
+// @Summary Create customer contact
+// @Tags Contacts
+// @Description Add an email address or a phone number to a customer. Emails are stored in lower case and phones in E.164 format. A primary contact replaces the previous primary of its type, and a primary email must not be the primary email of another customer.
+// @Accept json,xml,application/msgpack
+// @Produce json,xml,application/msgpack
+// @Param id path int true "Customer ID"
+// @Param contact body dto.ContactRequest true "Contact to be created"
+// @Success 201 {object} web.Responses{data=domain.Contact} "Success"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 409 {object} web.ErrorResponse "Conflict"
+// @Failure 422 {object} web.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/contacts [post]
+func (h *ContactHandler) Store(c *gin.Context) {
+	customerID, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.ContactRequest
+	if err := web.ShouldBind(c, &req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	result, err := h.service.Save(c.Request.Context(), req, customerID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusCreated, result)
+}
+
+// GetContacts godoc
+// @Summary List customer contacts
+// @Tags Contacts
+// @Produce json,xml,application/msgpack,text/csv
+// @Param id path int true "Customer ID"
+// @Success 200 {object} web.Responses{data=[]domain.Contact} "Success"
+// @Success 204 "No Content"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/contacts [get]
+func (h *ContactHandler) GetAll(c *gin.Context) {
+	customerID, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	contacts, err := h.service.GetAll(c.Request.Context(), customerID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if contacts == nil {
+		web.Success(c, http.StatusNoContent, contacts)
+		return
+	}
+
+	web.Success(c, http.StatusOK, contacts)
+}
+
+// GetContact godoc
+// @Summary Get customer contact
+// @Tags Contacts
+// @Produce json,xml,application/msgpack
+// @Param id path int true "Customer ID"
+// @Param contact_id path int true "Contact ID"
+// @Success 200 {object} web.Responses{data=domain.Contact} "Success"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/contacts/{contact_id} [get]
+func (h *ContactHandler) Get(c *gin.Context) {
+	customerID, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	id, ok := IDParam(c, "contact_id")
+	if !ok {
+		return
+	}
+
+	result, err := h.service.Get(c.Request.Context(), customerID, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusOK, result)
+}
+
+// UpdateContact godoc
+// @Summary Update customer contact
+// @Tags Contacts
+// @Accept json,xml,application/msgpack
+// @Produce json,xml,application/msgpack
+// @Param id path int true "Customer ID"
+// @Param contact_id path int true "Contact ID"
+// @Param contact body dto.ContactRequest true "Contact to be updated"
+// @Success 200 {object} web.Responses{data=domain.Contact} "Success"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 409 {object} web.ErrorResponse "Conflict"
+// @Failure 422 {object} web.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/contacts/{contact_id} [put]
+func (h *ContactHandler) Update(c *gin.Context) {
+	customerID, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	id, ok := IDParam(c, "contact_id")
+	if !ok {
+		return
+	}
+
+	var req dto.ContactRequest
+	if err := web.ShouldBind(c, &req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	result, err := h.service.Update(c.Request.Context(), req, customerID, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusOK, result)
+}
+
+// DeleteContact godoc
+// @Summary Delete customer contact
+// @Tags Contacts
+// @Param id path int true "Customer ID"
+// @Param contact_id path int true "Contact ID"
+// @Success 204
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/contacts/{contact_id} [delete]
+func (h *ContactHandler) Delete(c *gin.Context) {
+	customerID, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	id, ok := IDParam(c, "contact_id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), customerID, id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusNoContent, nil)
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/contact"
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/middleware"
+	mocks "github.com/danilosano/web-golang-api/pkg/tests/contacts"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	mockedContact = domain.Contact{
+		ID:         1,
+		CustomerID: 1,
+		Type:       domain.ContactTypePhone,
+		Label:      "mobile",
+		Value:      "+5511987654321",
+		Primary:    true,
+		CreatedAt:  time.Date(2021, 10, 10, 0, 0, 0, 0, time.UTC),
+	}
+
+	contactInput = dto.ContactRequest{
+		Type:    domain.ContactTypePhone,
+		Label:   "mobile",
+		Value:   "(11) 98765-4321",
+		Region:  "BR",
+		Primary: true,
+	}
+
+	jsonContactInput = `{"type": "phone", "label": "mobile", "value": "(11) 98765-4321", "region": "BR", "primary": true}`
+)
+
+func InitServerWithContactsRoute(t *testing.T) (*gin.Engine, *mocks.ContactServiceMock, context.Context) {
+	t.Helper()
+	server := testutil.CreateServer()
+	server.Use(middleware.ErrorHandler())
+	mockService := new(mocks.ContactServiceMock)
+	handler := NewContactHandler(mockService)
+	server.POST(pathCustomer+":id/contacts", handler.Store)
+	server.GET(pathCustomer+":id/contacts", handler.GetAll)
+	server.GET(pathCustomer+":id/contacts/:contact_id", handler.Get)
+	server.PUT(pathCustomer+":id/contacts/:contact_id", handler.Update)
+	server.DELETE(pathCustomer+":id/contacts/:contact_id", handler.Delete)
+	return server, mockService, context.Background()
+}
+
+func TestStoreContact(t *testing.T) {
+	t.Run("When the contact is valid, it is created and returned with a 201 code.", func(t *testing.T) {
+		var result struct {
+			Data domain.Contact `json:"data"`
+		}
+		server, service, ctx := InitServerWithContactsRoute(t)
+		service.On("Save", ctx, contactInput, 1).Return(mockedContact, nil)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathCustomer+"1/contacts", jsonContactInput)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &result)
+		assert.Nil(t, err)
+		assert.Equal(t, mockedContact, result.Data)
+	})
+
+	t.Run("When the value does not match the type, a 400 code will be returned.", func(t *testing.T) {
+		for body, message := range map[string]string{
+			`{"type": "email", "value": "danilo@"}`:             "must be a valid email address",
+			`{"type": "phone", "value": "123", "region": "BR"}`: "must be a valid phone number",
+		} {
+			var resp web.ErrorResponse
+			server, _, _ := InitServerWithContactsRoute(t)
+
+			request, response := testutil.MakeRequest(http.MethodPost, pathCustomer+"1/contacts", body)
+			server.ServeHTTP(response, request)
+
+			assert.Equal(t, http.StatusBadRequest, response.Code)
+			err := json.Unmarshal(response.Body.Bytes(), &resp)
+			assert.Nil(t, err)
+			assert.Equal(t, []web.FieldError{{Field: "value", Message: message}}, resp.Fields)
+		}
+	})
+
+	t.Run("When the value is missing, only the required rule is reported.", func(t *testing.T) {
+		var resp web.ErrorResponse
+		server, _, _ := InitServerWithContactsRoute(t)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathCustomer+"1/contacts", `{"type": "email"}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.Equal(t, []web.FieldError{{Field: "value", Message: "is required"}}, resp.Fields)
+	})
+
+	t.Run("When the primary email belongs to another customer, a 409 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithContactsRoute(t)
+		service.On("Save", ctx, contactInput, 1).Return(nil, contact.ErrorPrimaryEmailAlreadyExist)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathCustomer+"1/contacts", jsonContactInput)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+}
+
+func TestGetAllContacts(t *testing.T) {
+	t.Run("When the customer has contacts, they are returned with a 200 code.", func(t *testing.T) {
+		server, service, ctx := InitServerWithContactsRoute(t)
+		service.On("GetAll", ctx, 1).Return([]domain.Contact{mockedContact}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1/contacts", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("When the customer has no contacts, a 204 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithContactsRoute(t)
+		service.On("GetAll", ctx, 1).Return(nil, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1/contacts", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+}
+
+func TestUpdateContact(t *testing.T) {
+	t.Run("When the contact does not exist, a 404 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithContactsRoute(t)
+		service.On("Update", ctx, contactInput, 1, 2).Return(nil, contact.ErrorContactNotFound)
+
+		request, response := testutil.MakeRequest(http.MethodPut, pathCustomer+"1/contacts/2", jsonContactInput)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestDeleteContact(t *testing.T) {
+	t.Run("When the contact exists, it is deleted with a 204 code.", func(t *testing.T) {
+		server, service, ctx := InitServerWithContactsRoute(t)
+		service.On("Delete", ctx, 1, 1).Return(nil)
+
+		request, response := testutil.MakeRequest(http.MethodDelete, pathCustomer+"1/contacts/1", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+}
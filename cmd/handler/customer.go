@@ -1,32 +1,39 @@
 package handler
 
 import (
-	"errors"
+	"context"
 	"net/http"
 	"strconv"
 
+	_ "github.com/danilosano/web-golang-api/cmd/httperrors"
 	"github.com/danilosano/web-golang-api/internal/customer"
 	"github.com/danilosano/web-golang-api/internal/domain/dto"
 	"github.com/danilosano/web-golang-api/pkg/web"
 	"github.com/gin-gonic/gin"
 )
 
+// emailLookupLimit bounds the customers returned by a lookup by email, which
+// only a few customers share.
+const emailLookupLimit = 100
+
 type CustomerHandler struct {
-	service customer.Service
+	service  customer.Service
+	expander Expander
 }
 
-func NewCustomerHandler(s customer.Service) *CustomerHandler {
+func NewCustomerHandler(s customer.Service, expander Expander) *CustomerHandler {
 	return &CustomerHandler{
-		service: s,
+		service:  s,
+		expander: expander,
 	}
 }
 
 // CreateCustomers godoc
 // @Summary Create customer
 // @Tags Customers
-// @Description create customer
-// @Accept json
-// @Produce json
+// @Description Create a customer. When customer_number is left out, the server allocates the next one if numbering is enabled. parent_id makes it a subsidiary of another customer.
+// @Accept json,xml,application/msgpack
+// @Produce json,xml,application/msgpack
 // @Param customer body dto.CreateCustomerRequest true "Customer to be created"
 // @Success 201 {object} web.Responses{data=dto.CreateCustomerRequest} "Success"
 // @Failure 400 {object} web.ErrorResponse "Bad Request"
@@ -36,25 +43,14 @@ func NewCustomerHandler(s customer.Service) *CustomerHandler {
 // @Router /api/v1/customers [post]
 func (s *CustomerHandler) Store(c *gin.Context) {
 	var req dto.CreateCustomerRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		web.Error(c, http.StatusUnprocessableEntity, err.Error())
-		return
-	}
-
-	if err := req.Validate(); err != nil {
-		web.Error(c, http.StatusBadRequest, err.Error())
+	if err := web.ShouldBind(c, &req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
 	sctn, err := s.service.Save(c.Request.Context(), req)
-
 	if err != nil {
-		if errors.Is(err, customer.ErrorCustomerNumberAlreadyExist) {
-			web.Error(c, http.StatusConflict, err.Error())
-			return
-		}
-
-		web.Error(c, http.StatusInternalServerError, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
@@ -66,18 +62,41 @@ func (s *CustomerHandler) Store(c *gin.Context) {
 // @Description Get all customers
 // @Tags Customers
 // @Accept json
-// @Produce json
-// @Success 200 {object} web.Responses{data=[]domain.Customer} "Success"
+// @Produce json,xml,application/msgpack,text/csv
+// @Param If-None-Match header string false "ETag of the copy held by the client"
+// @Param If-Modified-Since header string false "Last-Modified date of the copy held by the client"
+// @Param email query string false "Only the customers with this email, in any case"
+// @Param expand query string false "Related resources to embed" Enums(addresses, contacts, tags)
+// @Success 200 {object} web.Responses{data=[]dto.ResultCustomerRequest} "Success"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
 // @Failure 500 {object} web.ErrorResponse "Internal Server Error"
 // @Router /api/v1/customers [get]
 func (s *CustomerHandler) GetAll(c *gin.Context) {
-	listCustomers, err := s.service.GetAll(c.Request.Context())
+	var query struct {
+		Email string `json:"email" form:"email" binding:"omitempty,email,varchar=255"`
+	}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	listCustomers, err := s.getAll(c.Request.Context(), query.Email)
 	if err != nil {
-		web.Error(c, http.StatusInternalServerError, err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	if err := s.expander.Expand(c, listCustomers); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if CustomersNotModified(c, listCustomers...) {
 		return
 	}
 
-	if listCustomers == nil {
+	if len(listCustomers) == 0 {
 		web.Success(c, http.StatusNoContent, listCustomers)
 		return
 	} else {
@@ -86,11 +105,24 @@ func (s *CustomerHandler) GetAll(c *gin.Context) {
 	}
 }
 
+// getAll returns every customer, or only those with the given email when set.
+func (s *CustomerHandler) getAll(ctx context.Context, email string) ([]dto.ResultCustomerRequest, error) {
+	if email == "" {
+		return s.service.GetAll(ctx)
+	}
+
+	page, err := s.service.List(ctx, dto.CustomerFilter{Email: email, PageSize: emailLookupLimit})
+	if err != nil {
+		return nil, err
+	}
+	return page.Customers, nil
+}
+
 // DeleteCustomer godoc
 // @Summary Delete customer
 // @Tags Customers
 // @Description delete customer
-// @Produce json
+// @Produce json,xml,application/msgpack
 // @Param id path int true "Customer ID"
 // @Success 200
 // @Failure 400 {object} web.ErrorResponse "Bad Request"
@@ -111,11 +143,7 @@ func (s *CustomerHandler) Delete(c *gin.Context) {
 
 	err = s.service.Delete(c.Request.Context(), int(id))
 	if err != nil {
-		if errors.Is(err, customer.ErrorCustomerNotFound) {
-			web.Error(c, http.StatusNotFound, "customer not found")
-			return
-		}
-		web.Error(c, http.StatusInternalServerError, err.Error())
+		_ = c.Error(err)
 		return
 	}
 	web.Success(c, http.StatusNoContent, nil)
@@ -125,14 +153,15 @@ func (s *CustomerHandler) Delete(c *gin.Context) {
 // @Summary Update customer
 // @Tags Customers
 // @Description update customerv
-// @Accept json
-// @Produce json
+// @Accept json,xml,application/msgpack
+// @Produce json,xml,application/msgpack
 // @Param id path int true "Customer ID"
 // @Param customer body dto.UpdateCustomerRequest true "Customer to be updated"
 // @Success 200 {object} web.Responses{data=dto.UpdateCustomerRequest} "Success"
 // @Failure 400 {object} web.ErrorResponse "Bad Request"
 // @Failure 404 {object} web.ErrorResponse "Not Found"
 // @Failure 409 {object} web.ErrorResponse "Conflict"
+// @Failure 422 {object} web.ErrorResponse "Unprocessable Entity"
 // @Failure 500 {object} web.ErrorResponse "Internal Server Error"
 // @Router /api/v1/customers/{id} [patch]
 func (s *CustomerHandler) Update(c *gin.Context) {
@@ -143,29 +172,14 @@ func (s *CustomerHandler) Update(c *gin.Context) {
 	}
 
 	var req dto.UpdateCustomerRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		web.Error(c, http.StatusUnprocessableEntity, err.Error())
-		return
-	}
-
-	if err := req.Validate(); err != nil {
-		web.Error(c, http.StatusBadRequest, err.Error())
+	if err := web.ShouldBind(c, &req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
 	sctn, err := s.service.Update(c.Request.Context(), req, int(id))
-
 	if err != nil {
-		if errors.Is(err, customer.ErrorCustomerNumberAlreadyExist) {
-			web.Error(c, http.StatusConflict, err.Error())
-			return
-		}
-		if errors.Is(err, customer.ErrorCustomerNotFound) {
-			web.Error(c, http.StatusNotFound, err.Error())
-			return
-		}
-
-		web.Error(c, http.StatusInternalServerError, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
@@ -176,33 +190,41 @@ func (s *CustomerHandler) Update(c *gin.Context) {
 // @Summary Get customer
 // @Tags Customers
 // @Description Get customer by ID
-// @Customer json
+// @Produce json,xml,application/msgpack
+// @Param If-None-Match header string false "ETag of the copy held by the client"
+// @Param If-Modified-Since header string false "Last-Modified date of the copy held by the client"
 // @Param id path int true "Customer ID"
-// @Success 200 {object} web.Responses{data=domain.Customer} "Success"
+// @Param expand query string false "Related resources to embed" Enums(addresses, contacts, tags)
+// @Success 200 {object} web.Responses{data=dto.ResultCustomerRequest} "Success"
+// @Success 301 "Merged into the customer given by the Location header"
+// @Success 304 "Not Modified"
 // @Failure 400 {object} web.ErrorResponse "Bad Request"
 // @Failure 404 {object} web.ErrorResponse "Not Found"
 // @Failure 500 {object} web.ErrorResponse "Internal Server Error"
 // @Router /api/v1/customers/{id} [get]
 func (s *CustomerHandler) Get(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
-	if err != nil {
-		web.Error(c, http.StatusBadRequest, "invalid input ID")
+	id, ok := IDParam(c, "id")
+	if !ok {
 		return
 	}
 
-	if id == 0 {
-		web.Error(c, http.StatusBadRequest, "invalid id provided: id must be a positive non-zero number")
-		return
-	}
-
-	sctn, err := s.service.Get(c.Request.Context(), int(id))
+	sctn, err := s.service.Get(c.Request.Context(), id)
 	if err != nil {
-		if errors.Is(err, customer.ErrorCustomerNotFound) {
-			web.Error(c, http.StatusNotFound, err.Error())
+		if RedirectMerged(c, err) {
 			return
 		}
+		_ = c.Error(err)
+		return
+	}
+
+	expanded := []dto.ResultCustomerRequest{sctn}
+	if err := s.expander.Expand(c, expanded); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	sctn = expanded[0]
 
-		web.Error(c, http.StatusInternalServerError, err.Error())
+	if CustomersNotModified(c, sctn) {
 		return
 	}
 
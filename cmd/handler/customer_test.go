@@ -3,19 +3,27 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/danilosano/web-golang-api/internal/customer"
 	"github.com/danilosano/web-golang-api/internal/domain"
 	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/middleware"
+	addressMocks "github.com/danilosano/web-golang-api/pkg/tests/addresses"
+	contactMocks "github.com/danilosano/web-golang-api/pkg/tests/contacts"
 	mocks "github.com/danilosano/web-golang-api/pkg/tests/customers"
+	tagMocks "github.com/danilosano/web-golang-api/pkg/tests/tags"
 	"github.com/danilosano/web-golang-api/pkg/testutil"
 	"github.com/danilosano/web-golang-api/pkg/web"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 const (
@@ -25,8 +33,9 @@ const (
 func InitServerWithCustomersRoute(t *testing.T) (*gin.Engine, *mocks.CustomersServiceMock, context.Context) {
 	t.Helper()
 	server := testutil.CreateServer()
+	server.Use(middleware.ErrorHandler())
 	mockService := new(mocks.CustomersServiceMock)
-	handler := NewCustomerHandler(mockService)
+	handler := NewCustomerHandler(mockService, Expander{})
 	server.GET(pathCustomer, handler.GetAll)
 	server.GET(pathCustomer+":id", handler.Get)
 	server.POST(pathCustomer, handler.Store)
@@ -88,6 +97,36 @@ func TestCreate(t *testing.T) {
 		assert.Equal(t, mockedResultCustomer, data)
 	})
 
+	t.Run("A customer sent in XML is created and returned in the format asked for.", func(t *testing.T) {
+		var data dto.ResultCustomerRequest
+		server, service, ctx := InitServerWithCustomersRoute(t)
+		service.On("Save", ctx, input).Return(mockedResultCustomer, nil)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathCustomer, `<customer>
+			<customer_number>2</customer_number>
+			<first_name>Danilo</first_name>
+			<last_name>Sano</last_name>
+		</customer>`)
+		request.Header.Set("Content-Type", "application/xml")
+		request.Header.Set("Accept", "application/xml")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+		err := xml.Unmarshal(response.Body.Bytes(), &data)
+		assert.Nil(t, err)
+		assert.Equal(t, mockedResultCustomer, data)
+	})
+
+	t.Run("If the body is in an unsupported format, a 415 code will be returned.", func(t *testing.T) {
+		server, _, _ := InitServerWithCustomersRoute(t)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathCustomer, "customer_number=2")
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, response.Code)
+	})
+
 	t.Run("If the JSON object does not contain the required fields, a 422 code will be returned.", func(t *testing.T) {
 		server, service, ctx := InitServerWithCustomersRoute(t)
 		service.On("Save", ctx, input).Return(domain.Customer{}, nil)
@@ -102,6 +141,45 @@ func TestCreate(t *testing.T) {
 		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
 	})
 
+	t.Run("When several fields are invalid, a 400 code will be returned listing all of them.", func(t *testing.T) {
+		var resp web.ErrorResponse
+		server, _, _ := InitServerWithCustomersRoute(t)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathCustomer, `{
+			"customer_number":0,
+			"first_name":"`+strings.Repeat("á", 101)+`"
+		}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.Equal(t, []web.FieldError{
+			{Field: "customer_number", Message: "must be greater than 0"},
+			{Field: "first_name", Message: "must be at most 100 characters long"},
+			{Field: "last_name", Message: "is required"},
+		}, resp.Fields)
+	})
+
+	t.Run("A name of exactly 100 multi-byte characters is accepted.", func(t *testing.T) {
+		server, service, ctx := InitServerWithCustomersRoute(t)
+		longName := strings.Repeat("á", 100)
+		service.On("Save", ctx, dto.CreateCustomerRequest{
+			CustomerNumber: &CustomerNumber,
+			FirstName:      longName,
+			LastName:       "Sano",
+		}).Return(mockedResultCustomer, nil)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathCustomer, `{
+			"customer_number":2,
+			"first_name":"`+longName+`",
+			"last_name":"Sano"
+		}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+	})
+
 	t.Run("If the customer_number already exists, it will return a 409 Conflict error.", func(t *testing.T) {
 		var resp web.ErrorResponse
 		server, service, ctx := InitServerWithCustomersRoute(t)
@@ -116,6 +194,17 @@ func TestCreate(t *testing.T) {
 		assert.Equal(t, customer.ErrorCustomerNumberAlreadyExist.Error(), resp.Message)
 	})
 
+	t.Run("Without a customer_number, the customer is passed on for the service to number, a 400 code being returned when it does not.", func(t *testing.T) {
+		server, service, ctx := InitServerWithCustomersRoute(t)
+		service.On("Save", ctx, dto.CreateCustomerRequest{FirstName: "Danilo", LastName: "Sano"}).Return(nil, customer.ErrorCustomerNumberRequired)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathCustomer, `{"first_name": "Danilo", "last_name": "Sano"}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		service.AssertExpectations(t)
+	})
+
 	t.Run("When data entry is successful, but an internal server error occurs when creating.", func(t *testing.T) {
 		server, service, ctx := InitServerWithCustomersRoute(t)
 		service.On("Save", ctx, input).Return(domain.Customer{}, errors.New("generic error"))
@@ -158,6 +247,41 @@ func TestGetAll(t *testing.T) {
 
 		assert.Equal(t, http.StatusInternalServerError, response.Code)
 	})
+
+	t.Run("When an email is given, only the customers with that email are returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithCustomersRoute(t)
+		filter := dto.CustomerFilter{Email: "danilo@example.com", PageSize: emailLookupLimit}
+		service.On("List", ctx, filter).Return(dto.CustomerPage{Customers: []dto.ResultCustomerRequest{mockedResultCustomer}, Page: 1, PageSize: emailLookupLimit, Total: 1}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"?email=danilo@example.com", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		service.AssertNotCalled(t, "GetAll", mock.Anything)
+	})
+
+	t.Run("When no customer has the email, a 204 code will be returned.", func(t *testing.T) {
+		server, service, _ := InitServerWithCustomersRoute(t)
+		service.On("List", mock.Anything, mock.Anything).Return(dto.CustomerPage{Customers: []dto.ResultCustomerRequest{}}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"?email=nobody@example.com", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+
+	t.Run("When the email is invalid, a 400 code will be returned.", func(t *testing.T) {
+		var resp web.ErrorResponse
+		server, _, _ := InitServerWithCustomersRoute(t)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"?email=danilo", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.Equal(t, []web.FieldError{{Field: "email", Message: "must be a valid email address"}}, resp.Fields)
+	})
 }
 
 func TestGet(t *testing.T) {
@@ -196,6 +320,76 @@ func TestGet(t *testing.T) {
 		assert.Equal(t, mockedResultCustomer, data)
 	})
 
+	t.Run("When the client already has the current customer, a 304 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithCustomersRoute(t)
+		service.On("Get", ctx, 1).Return(mockedResultCustomer, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1", "")
+		server.ServeHTTP(response, request)
+		etag := response.Header().Get("ETag")
+		assert.Equal(t, "Sun, 10 Oct 2021 00:00:00 GMT", response.Header().Get("Last-Modified"))
+
+		request, response = testutil.MakeRequest(http.MethodGet, pathCustomer+"1", "")
+		request.Header.Set("If-None-Match", etag)
+		server.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusNotModified, response.Code)
+		assert.Empty(t, response.Body.String())
+
+		updated := mockedResultCustomer
+		updatedAt := time.Date(2021, 10, 11, 0, 0, 0, 0, time.UTC)
+		updated.UpdatedAt = &updatedAt
+		service.ExpectedCalls = nil
+		service.On("Get", ctx, 1).Return(updated, nil)
+
+		request, response = testutil.MakeRequest(http.MethodGet, pathCustomer+"1", "")
+		request.Header.Set("If-None-Match", etag)
+		server.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.NotEqual(t, etag, response.Header().Get("ETag"))
+	})
+
+	t.Run("A change within the same second changes the ETag.", func(t *testing.T) {
+		server, service, ctx := InitServerWithCustomersRoute(t)
+		service.On("Get", ctx, 1).Return(mockedResultCustomer, nil).Once()
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1", "")
+		server.ServeHTTP(response, request)
+		etag := response.Header().Get("ETag")
+
+		renamed := mockedResultCustomer
+		renamed.LastName = "Santos"
+		service.On("Get", ctx, 1).Return(renamed, nil).Once()
+
+		request, response = testutil.MakeRequest(http.MethodGet, pathCustomer+"1", "")
+		request.Header.Set("If-None-Match", etag)
+		server.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.NotEqual(t, etag, response.Header().Get("ETag"))
+	})
+
+	t.Run("Each format has its own ETag and the response varies on Accept.", func(t *testing.T) {
+		server, service, ctx := InitServerWithCustomersRoute(t)
+		service.On("Get", ctx, 1).Return(mockedResultCustomer, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1", "")
+		server.ServeHTTP(response, request)
+		etag := response.Header().Get("ETag")
+		assert.Equal(t, []string{"Accept"}, response.Header().Values("Vary"))
+
+		request, response = testutil.MakeRequest(http.MethodGet, pathCustomer+"1", "")
+		request.Header.Set("Accept", web.MIMEXML)
+		request.Header.Set("If-None-Match", etag)
+		server.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.NotEqual(t, etag, response.Header().Get("ETag"))
+
+		request.Header.Set("If-None-Match", response.Header().Get("ETag"))
+		response = httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusNotModified, response.Code)
+		assert.Equal(t, []string{"Accept"}, response.Header().Values("Vary"))
+	})
+
 	t.Run("When the backend returns an unexpected error, return code 500.", func(t *testing.T) {
 		server, service, ctx := InitServerWithCustomersRoute(t)
 		service.On("Get", ctx, 1).Return(domain.Customer{}, errors.New("generic error"))
@@ -289,12 +483,20 @@ func TestUpdate(t *testing.T) {
 		assert.Equal(t, "invalid input ID", resp.Message)
 	})
 
-	t.Run("When a field is missing in the request body, a 422 code will be returned.", func(t *testing.T) {
+	t.Run("When fields are missing in the request body, a 400 code will be returned listing each of them.", func(t *testing.T) {
+		var resp web.ErrorResponse
 		server, _, _ := InitServerWithCustomersRoute(t)
 		request, response := testutil.MakeRequest(http.MethodPut, pathCustomer+"1", `{}`)
 		server.ServeHTTP(response, request)
 
-		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.Equal(t, []web.FieldError{
+			{Field: "customer_number", Message: "is required"},
+			{Field: "first_name", Message: "is required"},
+			{Field: "last_name", Message: "is required"},
+		}, resp.Fields)
 	})
 
 	t.Run("When there is an invalid value in the request body, a 400 code will be returned.", func(t *testing.T) {
@@ -310,7 +512,8 @@ func TestUpdate(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, response.Code)
 		err := json.Unmarshal(response.Body.Bytes(), &resp)
 		assert.Nil(t, err)
-		assert.Equal(t, "invalid input: customer number must be greater than 0", resp.Message)
+		assert.Equal(t, "invalid input", resp.Message)
+		assert.Equal(t, []web.FieldError{{Field: "customer_number", Message: "must be greater than 0"}}, resp.Fields)
 	})
 
 	t.Run("If the customer number to be updated already exists, a 409 code will be returned.", func(t *testing.T) {
@@ -392,3 +595,138 @@ func TestDelete(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, response.Code)
 	})
 }
+
+func TestExpand(t *testing.T) {
+	initServer := func(t *testing.T) (*gin.Engine, *mocks.CustomersServiceMock, *addressMocks.AddressServiceMock) {
+		t.Helper()
+		server := testutil.CreateServer()
+		server.Use(middleware.ErrorHandler())
+		customers := new(mocks.CustomersServiceMock)
+		addresses := new(addressMocks.AddressServiceMock)
+		handler := NewCustomerHandler(customers, Expander{Addresses: addresses, Contacts: new(contactMocks.ContactServiceMock)})
+		server.GET(pathCustomer, handler.GetAll)
+		server.GET(pathCustomer+":id", handler.Get)
+		return server, customers, addresses
+	}
+
+	t.Run("When the addresses are expanded, the customer is returned with them.", func(t *testing.T) {
+		var result struct {
+			Data dto.ResultCustomerRequest `json:"data"`
+		}
+		server, customers, addresses := initServer(t)
+		customers.On("Get", context.Background(), 1).Return(mockedResultCustomer, nil)
+		addresses.On("GetByCustomerIDs", context.Background(), []int{1}).Return(map[int][]domain.Address{1: {mockedAddress}}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1?expand=addresses", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &result)
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.Address{mockedAddress}, result.Data.Addresses)
+	})
+
+	t.Run("When the addresses are expanded in a list, they are loaded at once.", func(t *testing.T) {
+		var result struct {
+			Data []dto.ResultCustomerRequest `json:"data"`
+		}
+		other := mockedResultCustomer
+		other.ID = 2
+		server, customers, addresses := initServer(t)
+		customers.On("GetAll", context.Background()).Return([]dto.ResultCustomerRequest{mockedResultCustomer, other}, nil)
+		addresses.On("GetByCustomerIDs", context.Background(), []int{1, 2}).Return(map[int][]domain.Address{1: {mockedAddress}}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"?expand=orders,addresses", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &result)
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.Address{mockedAddress}, result.Data[0].Addresses)
+		assert.Nil(t, result.Data[1].Addresses)
+		addresses.AssertNumberOfCalls(t, "GetByCustomerIDs", 1)
+	})
+
+	t.Run("Without expansion, the addresses are neither loaded nor returned.", func(t *testing.T) {
+		server, customers, addresses := initServer(t)
+		customers.On("Get", context.Background(), 1).Return(mockedResultCustomer, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.NotContains(t, response.Body.String(), "addresses")
+		addresses.AssertNotCalled(t, "GetByCustomerIDs", mock.Anything, mock.Anything)
+	})
+
+	t.Run("A change of the expanded addresses changes the ETag.", func(t *testing.T) {
+		server, customers, addresses := initServer(t)
+		customers.On("Get", context.Background(), 1).Return(mockedResultCustomer, nil)
+		addresses.On("GetByCustomerIDs", context.Background(), []int{1}).Return(map[int][]domain.Address{1: {mockedAddress}}, nil).Once()
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1?expand=addresses", "")
+		server.ServeHTTP(response, request)
+		etag := response.Header().Get("ETag")
+
+		updated := mockedAddress
+		updatedAt := mockedAddress.CreatedAt.Add(time.Hour)
+		updated.UpdatedAt = &updatedAt
+		addresses.On("GetByCustomerIDs", context.Background(), []int{1}).Return(map[int][]domain.Address{1: {updated}}, nil).Once()
+		request, response = testutil.MakeRequest(http.MethodGet, pathCustomer+"1?expand=addresses", "")
+		request.Header.Set("If-None-Match", etag)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.NotEqual(t, etag, response.Header().Get("ETag"))
+	})
+}
+
+func TestExpandContacts(t *testing.T) {
+	t.Run("When the contacts and the addresses are expanded, the customer is returned with both.", func(t *testing.T) {
+		var result struct {
+			Data dto.ResultCustomerRequest `json:"data"`
+		}
+		server := testutil.CreateServer()
+		server.Use(middleware.ErrorHandler())
+		customers := new(mocks.CustomersServiceMock)
+		addresses := new(addressMocks.AddressServiceMock)
+		contacts := new(contactMocks.ContactServiceMock)
+		server.GET(pathCustomer+":id", NewCustomerHandler(customers, Expander{Addresses: addresses, Contacts: contacts}).Get)
+		customers.On("Get", context.Background(), 1).Return(mockedResultCustomer, nil)
+		addresses.On("GetByCustomerIDs", context.Background(), []int{1}).Return(map[int][]domain.Address{1: {mockedAddress}}, nil)
+		contacts.On("GetByCustomerIDs", context.Background(), []int{1}).Return(map[int][]domain.Contact{1: {mockedContact}}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1?expand=contacts,addresses", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &result)
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.Address{mockedAddress}, result.Data.Addresses)
+		assert.Equal(t, []domain.Contact{mockedContact}, result.Data.Contacts)
+	})
+}
+
+func TestExpandTags(t *testing.T) {
+	t.Run("When the tags are expanded, the customer is returned with them and dated by the latest one.", func(t *testing.T) {
+		var result struct {
+			Data dto.ResultCustomerRequest `json:"data"`
+		}
+		server := testutil.CreateServer()
+		server.Use(middleware.ErrorHandler())
+		customers := new(mocks.CustomersServiceMock)
+		tags := new(tagMocks.TagServiceMock)
+		server.GET(pathCustomer+":id", NewCustomerHandler(customers, Expander{Tags: tags}).Get)
+		customers.On("Get", context.Background(), 1).Return(mockedResultCustomer, nil)
+		tagged := []domain.Tag{{Name: "vip", TaggedAt: mockedResultCustomer.CreatedAt.Add(time.Hour)}}
+		tags.On("GetByCustomerIDs", context.Background(), []int{1}).Return(map[int][]domain.Tag{1: tagged}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1?expand=tags", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &result)
+		assert.Nil(t, err)
+		assert.Equal(t, tagged, result.Data.Tags)
+		assert.Equal(t, tagged[0].TaggedAt.Format(http.TimeFormat), response.Header().Get("Last-Modified"))
+	})
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/danilosano/web-golang-api/internal/address"
+	"github.com/danilosano/web-golang-api/internal/contact"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/internal/tag"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// ExpandAddresses embeds the addresses in the customers returned.
+	ExpandAddresses = "addresses"
+	// ExpandContacts embeds the contacts in the customers returned.
+	ExpandContacts = "contacts"
+	// ExpandTags embeds the tags in the customers returned.
+	ExpandTags = "tags"
+)
+
+// Expander embeds in customers the related resources the client asked for with
+// ?expand=a,b, loading each kind of resource with a single query. Unknown names
+// are ignored.
+type Expander struct {
+	Addresses address.Service
+	Contacts  contact.Service
+	Tags      tag.Service
+}
+
+// Expand fills the related resources of customers asked for by the request.
+func (e Expander) Expand(c *gin.Context, customers []dto.ResultCustomerRequest) error {
+	if len(customers) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(customers))
+	for i, customer := range customers {
+		ids[i] = customer.ID
+	}
+
+	if expands(c, ExpandAddresses) {
+		byCustomer, err := e.Addresses.GetByCustomerIDs(c.Request.Context(), ids)
+		if err != nil {
+			return err
+		}
+		for i := range customers {
+			customers[i].Addresses = byCustomer[customers[i].ID]
+		}
+	}
+
+	if expands(c, ExpandContacts) {
+		byCustomer, err := e.Contacts.GetByCustomerIDs(c.Request.Context(), ids)
+		if err != nil {
+			return err
+		}
+		for i := range customers {
+			customers[i].Contacts = byCustomer[customers[i].ID]
+		}
+	}
+
+	if expands(c, ExpandTags) {
+		byCustomer, err := e.Tags.GetByCustomerIDs(c.Request.Context(), ids)
+		if err != nil {
+			return err
+		}
+		for i := range customers {
+			customers[i].Tags = byCustomer[customers[i].ID]
+		}
+	}
+
+	return nil
+}
+
+// expands reports whether the request asks to embed the named related resource.
+func expands(c *gin.Context, name string) bool {
+	for _, value := range c.QueryArray("expand") {
+		for _, e := range strings.Split(value, ",") {
+			if strings.TrimSpace(e) == name {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/danilosano/web-golang-api/cmd/graph"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/graphql-go/graphql"
+)
+
+type GraphQLHandler struct {
+	schema graphql.Schema
+	limits graph.Limits
+}
+
+func NewGraphQLHandler(schema graphql.Schema, limits graph.Limits) *GraphQLHandler {
+	return &GraphQLHandler{
+		schema: schema,
+		limits: limits,
+	}
+}
+
+// GraphQL godoc
+// @Summary GraphQL endpoint
+// @Tags GraphQL
+// @Description Run a GraphQL query or mutation on customers. Errors are reported in the "errors" field of the result, with the REST error code in their extensions.
+// @Accept json
+// @Produce json
+// @Param request body graph.Request true "GraphQL request"
+// @Success 200 {object} object "GraphQL result"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 422 {object} web.ErrorResponse "Unprocessable Entity"
+// @Router /api/v1/graphql [post]
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	var req graph.Request
+	if err := c.ShouldBindBodyWith(&req, binding.JSON); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	c.JSON(http.StatusOK, graph.Execute(c.Request.Context(), h.schema, h.limits, req))
+}
+
+// Mutations runs next, such as a stricter rate limit, only for the requests
+// running a mutation.
+func (h *GraphQLHandler) Mutations(next gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req graph.Request
+		if err := c.ShouldBindBodyWith(&req, binding.JSON); err == nil && graph.IsMutation(req) {
+			next(c)
+		}
+	}
+}
+
+// Playground serves GraphiQL, an in-browser IDE sending its queries to endpoint.
+func Playground(endpoint string) gin.HandlerFunc {
+	page := []byte(`<!DOCTYPE html>
+<html>
+<head>
+  <title>GraphiQL</title>
+  <link rel="stylesheet" href="https://unpkg.com/graphiql@3/graphiql.min.css" />
+</head>
+<body style="margin: 0;">
+  <div id="graphiql" style="height: 100vh;"></div>
+  <script crossorigin src="https://unpkg.com/react@18/umd/react.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/react-dom@18/umd/react-dom.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/graphiql@3/graphiql.min.js"></script>
+  <script>
+    const fetcher = GraphiQL.createFetcher({ url: "` + endpoint + `" });
+    ReactDOM.createRoot(document.getElementById("graphiql")).render(React.createElement(GraphiQL, { fetcher }));
+  </script>
+</body>
+</html>`)
+
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", page)
+	}
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/danilosano/web-golang-api/cmd/graph"
+	"github.com/danilosano/web-golang-api/pkg/middleware"
+	mocks "github.com/danilosano/web-golang-api/pkg/tests/customers"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	pathGraphQL = "/api/v1/graphql"
+)
+
+func TestGraphQL(t *testing.T) {
+	mockService := new(mocks.CustomersServiceMock)
+	schema, err := graph.NewSchema(mockService)
+	assert.Nil(t, err)
+	server := testutil.CreateServer()
+	server.Use(middleware.ErrorHandler())
+	server.POST(pathGraphQL, NewGraphQLHandler(schema, graph.DefaultLimits).Query)
+	server.GET(pathGraphQL, Playground(pathGraphQL))
+
+	t.Run("When the query succeeds, its data is returned with a 200 code.", func(t *testing.T) {
+		var result struct {
+			Data map[string]map[string]any `json:"data"`
+		}
+		mockService.On("Get", mock.Anything, 1).Return(mockedResultCustomer, nil).Once()
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathGraphQL, `{"query": "query One($id: Int!) { customer(id: $id) { lastName } }", "variables": {"id": 1}}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &result)
+		assert.Nil(t, err)
+		assert.Equal(t, "Sano", result.Data["customer"]["lastName"])
+	})
+
+	t.Run("If the request has no query, a 400 code will be returned.", func(t *testing.T) {
+		request, response := testutil.MakeRequest(http.MethodPost, pathGraphQL, `{"variables": {}}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("Only the mutations go through the handlers of Mutations.", func(t *testing.T) {
+		var mutations int
+		server := testutil.CreateServer()
+		server.Use(middleware.ErrorHandler())
+		handler := NewGraphQLHandler(schema, graph.DefaultLimits)
+		server.POST(pathGraphQL, handler.Mutations(func(*gin.Context) { mutations++ }), handler.Query)
+		mockService.On("Get", mock.Anything, 1).Return(mockedResultCustomer, nil).Once()
+		mockService.On("Delete", mock.Anything, 1).Return(nil).Once()
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathGraphQL, `{"query": "{ customer(id: 1) { lastName } }"}`)
+		server.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, 0, mutations)
+
+		request, response = testutil.MakeRequest(http.MethodPost, pathGraphQL, `{"query": "mutation { deleteCustomer(id: 1) }"}`)
+		server.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, 1, mutations)
+		assert.Contains(t, response.Body.String(), `"deleteCustomer":true`)
+	})
+
+	t.Run("The playground page points GraphiQL to the endpoint.", func(t *testing.T) {
+		request, response := testutil.MakeRequest(http.MethodGet, pathGraphQL, "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.True(t, strings.Contains(response.Body.String(), `url: "/api/v1/graphql"`))
+	})
+}
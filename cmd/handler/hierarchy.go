@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type HierarchyHandler struct {
+	service customer.Service
+}
+
+func NewHierarchyHandler(s customer.Service) *HierarchyHandler {
+	return &HierarchyHandler{
+		service: s,
+	}
+}
+
+// GetChildren godoc
+// @Summary List subsidiaries
+// @Tags Customers
+// @Description Get the customers whose parent account is the given customer
+// @Produce json,xml,application/msgpack
+// @Param id path int true "Customer ID"
+// @Success 200 {object} web.Responses{data=[]dto.ResultCustomerRequest} "Success"
+// @Success 204 "No Content"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/children [get]
+func (h *HierarchyHandler) Children(c *gin.Context) {
+	id, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	children, err := h.service.Children(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if len(children) == 0 {
+		web.Success(c, http.StatusNoContent, children)
+		return
+	}
+
+	web.Success(c, http.StatusOK, children)
+}
+
+// GetAncestors godoc
+// @Summary List parent accounts
+// @Tags Customers
+// @Description Get the parent of the customer, its parent in turn and so on up to the top of the hierarchy, nearest first
+// @Produce json,xml,application/msgpack
+// @Param id path int true "Customer ID"
+// @Success 200 {object} web.Responses{data=[]dto.ResultCustomerRequest} "Success"
+// @Success 204 "No Content"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/ancestors [get]
+func (h *HierarchyHandler) Ancestors(c *gin.Context) {
+	id, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	ancestors, err := h.service.Ancestors(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if len(ancestors) == 0 {
+		web.Success(c, http.StatusNoContent, ancestors)
+		return
+	}
+
+	web.Success(c, http.StatusOK, ancestors)
+}
+
+// GetTree godoc
+// @Summary Get customer hierarchy
+// @Tags Customers
+// @Description Get the customer along with all of its subsidiaries, nested
+// @Produce json,xml,application/msgpack
+// @Param id path int true "Customer ID"
+// @Success 200 {object} web.Responses{data=dto.CustomerTree} "Success"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/tree [get]
+func (h *HierarchyHandler) Tree(c *gin.Context) {
+	id, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	tree, err := h.service.Tree(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusOK, tree)
+}
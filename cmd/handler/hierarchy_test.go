@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/middleware"
+	mocks "github.com/danilosano/web-golang-api/pkg/tests/customers"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func InitServerWithHierarchyRoutes(t *testing.T) (*gin.Engine, *mocks.CustomersServiceMock, context.Context) {
+	t.Helper()
+	server := testutil.CreateServer()
+	server.Use(middleware.ErrorHandler())
+	mockService := new(mocks.CustomersServiceMock)
+	handler := NewHierarchyHandler(mockService)
+	server.GET(pathCustomer+":id/children", handler.Children)
+	server.GET(pathCustomer+":id/ancestors", handler.Ancestors)
+	server.GET(pathCustomer+":id/tree", handler.Tree)
+	return server, mockService, context.Background()
+}
+
+func TestChildren(t *testing.T) {
+	t.Run("The subsidiaries are returned with a 200 code.", func(t *testing.T) {
+		var result struct {
+			Data []dto.ResultCustomerRequest `json:"data"`
+		}
+		parentID := 1
+		children := []dto.ResultCustomerRequest{{ID: 2, FirstName: "Acme", LastName: "Ltd", ParentID: &parentID}}
+		server, service, ctx := InitServerWithHierarchyRoutes(t)
+		service.On("Children", ctx, 1).Return(children, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1/children", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &result)
+		assert.Nil(t, err)
+		assert.Equal(t, children, result.Data)
+	})
+
+	t.Run("If the customer has no subsidiary, a 204 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithHierarchyRoutes(t)
+		service.On("Children", ctx, 1).Return([]dto.ResultCustomerRequest{}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1/children", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+}
+
+func TestAncestors(t *testing.T) {
+	t.Run("The ancestors are returned with a 200 code.", func(t *testing.T) {
+		server, service, ctx := InitServerWithHierarchyRoutes(t)
+		service.On("Ancestors", ctx, 2).Return([]dto.ResultCustomerRequest{{ID: 1, FirstName: "Acme", LastName: "Holding"}}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"2/ancestors", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("If the customer does not exist, a 404 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithHierarchyRoutes(t)
+		service.On("Ancestors", ctx, 2).Return(nil, customer.ErrorCustomerNotFound)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"2/ancestors", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestTree(t *testing.T) {
+	t.Run("The nested hierarchy is returned with a 200 code.", func(t *testing.T) {
+		var result struct {
+			Data dto.CustomerTree `json:"data"`
+		}
+		parentID := 1
+		tree := dto.CustomerTree{
+			Customer: dto.ResultCustomerRequest{ID: 1, FirstName: "Acme", LastName: "Holding"},
+			Children: []dto.CustomerTree{{Customer: dto.ResultCustomerRequest{ID: 2, FirstName: "Acme", LastName: "Ltd", ParentID: &parentID}}},
+		}
+		server, service, ctx := InitServerWithHierarchyRoutes(t)
+		service.On("Tree", ctx, 1).Return(tree, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1/tree", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &result)
+		assert.Nil(t, err)
+		assert.Equal(t, tree, result.Data)
+	})
+}
+
+func TestUpdateParent(t *testing.T) {
+	t.Run("The errors of an invalid parent are mapped to a 422 code.", func(t *testing.T) {
+		for _, err := range []error{customer.ErrorParentNotFound, customer.ErrorHierarchyCycle} {
+			server, service, _ := InitServerWithCustomersRoute(t)
+			service.On("Update", mock.Anything, mock.Anything, 1).Return(dto.ResultCustomerRequest{}, err)
+
+			request, response := testutil.MakeRequest(http.MethodPut, pathCustomer+"1", `{"customer_number": 2, "first_name": "Acme", "last_name": "Ltd", "parent_id": 3}`)
+			server.ServeHTTP(response, request)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, response.Code, err.Error())
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type MergeHandler struct {
+	service customer.Service
+}
+
+func NewMergeHandler(s customer.Service) *MergeHandler {
+	return &MergeHandler{
+		service: s,
+	}
+}
+
+// GetDuplicates godoc
+// @Summary Find duplicate customers
+// @Tags Customers
+// @Description Get the customers that may be the same person: those sharing a contact value first, then those with a similar name regardless of case, accents and word order
+// @Produce json,xml,application/msgpack
+// @Param id path int true "Customer ID"
+// @Success 200 {object} web.Responses{data=[]dto.DuplicateCandidate} "Success"
+// @Success 204 "No Content"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/duplicates [get]
+func (h *MergeHandler) Duplicates(c *gin.Context) {
+	id, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	candidates, err := h.service.FindDuplicates(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if candidates == nil {
+		web.Success(c, http.StatusNoContent, candidates)
+		return
+	}
+
+	web.Success(c, http.StatusOK, candidates)
+}
+
+// MergeCustomers godoc
+// @Summary Merge customers
+// @Tags Customers
+// @Description Move the addresses, contacts, notes, attachments, tags and subsidiaries of the source customer to the target one and delete the source. The source then redirects to the target, and both record the merge in their audit trail.
+// @Accept json,xml,application/msgpack
+// @Produce json,xml,application/msgpack
+// @Param merge body dto.MergeRequest true "Customers to be merged"
+// @Success 200 {object} web.Responses{data=dto.ResultCustomerRequest} "Success"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 422 {object} web.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/merge [post]
+func (h *MergeHandler) Merge(c *gin.Context) {
+	var req dto.MergeRequest
+	if err := web.ShouldBind(c, &req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	result, err := h.service.Merge(c.Request.Context(), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusOK, result)
+}
+
+// RedirectMerged redirects the requests for a customer merged into another one to
+// the same path under the other customer, reporting whether err was such a merge.
+func RedirectMerged(c *gin.Context, err error) bool {
+	var merged *customer.MergedError
+	if !errors.As(err, &merged) {
+		return false
+	}
+
+	location := *c.Request.URL
+	location.Path = path.Join(path.Dir(location.Path), strconv.Itoa(merged.TargetID))
+	c.Redirect(http.StatusMovedPermanently, location.RequestURI())
+	return true
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/middleware"
+	mocks "github.com/danilosano/web-golang-api/pkg/tests/customers"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func InitServerWithMergeRoutes(t *testing.T) (*gin.Engine, *mocks.CustomersServiceMock, context.Context) {
+	t.Helper()
+	server := testutil.CreateServer()
+	server.Use(middleware.ErrorHandler())
+	mockService := new(mocks.CustomersServiceMock)
+	handler := NewMergeHandler(mockService)
+	server.GET(pathCustomer+":id/duplicates", handler.Duplicates)
+	server.POST(pathCustomer+"merge", handler.Merge)
+	return server, mockService, context.Background()
+}
+
+func TestDuplicates(t *testing.T) {
+	t.Run("The duplicate candidates are returned with a 200 code.", func(t *testing.T) {
+		var result struct {
+			Data []dto.DuplicateCandidate `json:"data"`
+		}
+		candidates := []dto.DuplicateCandidate{{
+			Customer:       dto.ResultCustomerRequest{ID: 2, FirstName: "Danilo", LastName: "Sano"},
+			NameSimilarity: 1,
+			SharedContacts: []string{"danilo@example.com"},
+		}}
+		server, service, ctx := InitServerWithMergeRoutes(t)
+		service.On("FindDuplicates", ctx, 1).Return(candidates, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1/duplicates", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &result)
+		assert.Nil(t, err)
+		assert.Equal(t, candidates, result.Data)
+	})
+
+	t.Run("If there is no candidate, a 204 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithMergeRoutes(t)
+		service.On("FindDuplicates", ctx, 1).Return(nil, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1/duplicates", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+}
+
+func TestMerge(t *testing.T) {
+	t.Run("The target customer is returned with a 200 code.", func(t *testing.T) {
+		target := dto.ResultCustomerRequest{ID: 2, FirstName: "Danilo", LastName: "Sano"}
+		server, service, ctx := InitServerWithMergeRoutes(t)
+		service.On("Merge", ctx, dto.MergeRequest{SourceID: 1, TargetID: 2}).Return(target, nil)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathCustomer+"merge", `{"source_id": 1, "target_id": 2}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("The errors of the merge are mapped to their status codes.", func(t *testing.T) {
+		for err, status := range map[error]int{
+			customer.ErrorMergeSameCustomer:    http.StatusUnprocessableEntity,
+			customer.ErrorCustomerNotFound:     http.StatusNotFound,
+			&customer.MergedError{TargetID: 3}: http.StatusNotFound,
+		} {
+			server, service, ctx := InitServerWithMergeRoutes(t)
+			service.On("Merge", ctx, dto.MergeRequest{SourceID: 1, TargetID: 2}).Return(nil, err)
+
+			request, response := testutil.MakeRequest(http.MethodPost, pathCustomer+"merge", `{"source_id": 1, "target_id": 2}`)
+			server.ServeHTTP(response, request)
+
+			assert.Equal(t, status, response.Code, err.Error())
+		}
+	})
+
+	t.Run("If an ID is missing, a 400 code will be returned.", func(t *testing.T) {
+		server, _, _ := InitServerWithMergeRoutes(t)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathCustomer+"merge", `{"source_id": 1}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestGetMergedCustomer(t *testing.T) {
+	t.Run("A merged customer redirects to the customer it was merged into, keeping the query.", func(t *testing.T) {
+		server, service, ctx := InitServerWithCustomersRoute(t)
+		service.On("Get", ctx, 1).Return(nil, &customer.MergedError{TargetID: 7})
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1?expand=contacts", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusMovedPermanently, response.Code)
+		assert.Equal(t, pathCustomer+"7?expand=contacts", response.Header().Get("Location"))
+	})
+}
@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/internal/note"
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	web.RegisterError(note.ErrorNoteNotFound, http.StatusNotFound)
+}
+
+type NoteHandler struct {
+	service note.Service
+}
+
+func NewNoteHandler(s note.Service) *NoteHandler {
+	return &NoteHandler{
+		service: s,
+	}
+}
+
+// CreateNote godoc
+// @Summary Create customer note
+// @Tags Notes
+// @Description Record a call or any other interaction with a customer. The author is the authenticated user.
+// @Accept json,xml,application/msgpack
+// @Produce json,xml,application/msgpack
+// @Param id path int true "Customer ID"
+// @Param note body dto.NoteRequest true "Note to be created"
+// @Success 201 {object} web.Responses{data=domain.Note} "Success"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 422 {object} web.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/notes [post]
+func (h *NoteHandler) Store(c *gin.Context) {
+	customerID, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.NoteRequest
+	if err := web.ShouldBind(c, &req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	result, err := h.service.Save(c.Request.Context(), req, customerID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusCreated, result)
+}
+
+// GetNotes godoc
+// @Summary List customer notes
+// @Tags Notes
+// @Description Get the notes of a customer, pinned ones first and then newest first
+// @Produce json,xml,application/msgpack
+// @Param id path int true "Customer ID"
+// @Success 200 {object} web.Responses{data=[]domain.Note} "Success"
+// @Success 204 "No Content"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/notes [get]
+func (h *NoteHandler) GetAll(c *gin.Context) {
+	customerID, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	notes, err := h.service.GetAll(c.Request.Context(), customerID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if notes == nil {
+		web.Success(c, http.StatusNoContent, notes)
+		return
+	}
+
+	web.Success(c, http.StatusOK, notes)
+}
+
+// GetNote godoc
+// @Summary Get customer note
+// @Tags Notes
+// @Produce json,xml,application/msgpack
+// @Param id path int true "Customer ID"
+// @Param note_id path int true "Note ID"
+// @Success 200 {object} web.Responses{data=domain.Note} "Success"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/notes/{note_id} [get]
+func (h *NoteHandler) Get(c *gin.Context) {
+	customerID, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	id, ok := IDParam(c, "note_id")
+	if !ok {
+		return
+	}
+
+	result, err := h.service.Get(c.Request.Context(), customerID, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusOK, result)
+}
+
+// UpdateNote godoc
+// @Summary Update customer note
+// @Tags Notes
+// @Description Edit the body of a note or pin it. The previous body is kept in the edit history of the note.
+// @Accept json,xml,application/msgpack
+// @Produce json,xml,application/msgpack
+// @Param id path int true "Customer ID"
+// @Param note_id path int true "Note ID"
+// @Param note body dto.NoteRequest true "Note to be updated"
+// @Success 200 {object} web.Responses{data=domain.Note} "Success"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 422 {object} web.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/notes/{note_id} [put]
+func (h *NoteHandler) Update(c *gin.Context) {
+	customerID, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	id, ok := IDParam(c, "note_id")
+	if !ok {
+		return
+	}
+
+	var req dto.NoteRequest
+	if err := web.ShouldBind(c, &req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	result, err := h.service.Update(c.Request.Context(), req, customerID, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusOK, result)
+}
+
+// DeleteNote godoc
+// @Summary Delete customer note
+// @Tags Notes
+// @Param id path int true "Customer ID"
+// @Param note_id path int true "Note ID"
+// @Success 204
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/notes/{note_id} [delete]
+func (h *NoteHandler) Delete(c *gin.Context) {
+	customerID, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	id, ok := IDParam(c, "note_id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), customerID, id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusNoContent, nil)
+}
+
+// GetNoteRevisions godoc
+// @Summary Note edit history
+// @Tags Notes
+// @Description Get the previous bodies of a note, oldest first
+// @Produce json,xml,application/msgpack
+// @Param id path int true "Customer ID"
+// @Param note_id path int true "Note ID"
+// @Success 200 {object} web.Responses{data=[]domain.NoteRevision} "Success"
+// @Success 204 "No Content"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/notes/{note_id}/revisions [get]
+func (h *NoteHandler) Revisions(c *gin.Context) {
+	customerID, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	id, ok := IDParam(c, "note_id")
+	if !ok {
+		return
+	}
+
+	revisions, err := h.service.GetRevisions(c.Request.Context(), customerID, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if revisions == nil {
+		web.Success(c, http.StatusNoContent, revisions)
+		return
+	}
+
+	web.Success(c, http.StatusOK, revisions)
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/internal/note"
+	"github.com/danilosano/web-golang-api/pkg/middleware"
+	mocks "github.com/danilosano/web-golang-api/pkg/tests/notes"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	mockedNote = domain.Note{
+		ID:         1,
+		CustomerID: 1,
+		Author:     "agent-1",
+		Body:       "Called about the late invoice.",
+		Pinned:     true,
+		CreatedAt:  time.Date(2021, 10, 10, 0, 0, 0, 0, time.UTC),
+	}
+
+	noteInput = dto.NoteRequest{Body: "Called about the late invoice.", Pinned: true}
+
+	jsonNoteInput = `{"body": "Called about the late invoice.", "pinned": true}`
+)
+
+func InitServerWithNotesRoute(t *testing.T) (*gin.Engine, *mocks.NoteServiceMock, context.Context) {
+	t.Helper()
+	server := testutil.CreateServer()
+	server.Use(middleware.ErrorHandler())
+	mockService := new(mocks.NoteServiceMock)
+	handler := NewNoteHandler(mockService)
+	server.POST(pathCustomer+":id/notes", handler.Store)
+	server.GET(pathCustomer+":id/notes", handler.GetAll)
+	server.GET(pathCustomer+":id/notes/:note_id", handler.Get)
+	server.PUT(pathCustomer+":id/notes/:note_id", handler.Update)
+	server.DELETE(pathCustomer+":id/notes/:note_id", handler.Delete)
+	server.GET(pathCustomer+":id/notes/:note_id/revisions", handler.Revisions)
+	return server, mockService, context.Background()
+}
+
+func TestStoreNote(t *testing.T) {
+	t.Run("When the note is valid, it is created and returned with a 201 code.", func(t *testing.T) {
+		var result struct {
+			Data domain.Note `json:"data"`
+		}
+		server, service, ctx := InitServerWithNotesRoute(t)
+		service.On("Save", ctx, noteInput, 1).Return(mockedNote, nil)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathCustomer+"1/notes", jsonNoteInput)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &result)
+		assert.Nil(t, err)
+		assert.Equal(t, mockedNote, result.Data)
+	})
+
+	t.Run("When the body is missing, a 400 code will be returned.", func(t *testing.T) {
+		var resp web.ErrorResponse
+		server, _, _ := InitServerWithNotesRoute(t)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathCustomer+"1/notes", `{"pinned": true}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.Equal(t, []web.FieldError{{Field: "body", Message: "is required"}}, resp.Fields)
+	})
+}
+
+func TestUpdateNote(t *testing.T) {
+	t.Run("When the note does not exist, a 404 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithNotesRoute(t)
+		service.On("Update", ctx, noteInput, 1, 2).Return(nil, note.ErrorNoteNotFound)
+
+		request, response := testutil.MakeRequest(http.MethodPut, pathCustomer+"1/notes/2", jsonNoteInput)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestNoteRevisions(t *testing.T) {
+	t.Run("When the note was edited, its previous bodies are returned with a 200 code.", func(t *testing.T) {
+		server, service, ctx := InitServerWithNotesRoute(t)
+		service.On("GetRevisions", ctx, 1, 1).Return([]domain.NoteRevision{{ID: 1, NoteID: 1, Body: "Called.", Editor: "agent-1"}}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1/notes/1/revisions", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("When the note was never edited, a 204 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithNotesRoute(t)
+		service.On("GetRevisions", ctx, 1, 1).Return(nil, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1/notes/1/revisions", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+}
+
+func TestDeleteNote(t *testing.T) {
+	t.Run("When the note exists, it is deleted with a 204 code.", func(t *testing.T) {
+		server, service, ctx := InitServerWithNotesRoute(t)
+		service.On("Delete", ctx, 1, 1).Return(nil)
+
+		request, response := testutil.MakeRequest(http.MethodDelete, pathCustomer+"1/notes/1", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+}
@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+// IDParam parses the positive ID in the named path parameter, answering 400 and
+// returning false when it is invalid.
+func IDParam(c *gin.Context, name string) (int, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 0)
+	if err != nil {
+		web.Error(c, http.StatusBadRequest, "invalid input ID")
+		return 0, false
+	}
+
+	if id == 0 {
+		web.Error(c, http.StatusBadRequest, "invalid id provided: id must be a positive non-zero number")
+		return 0, false
+	}
+
+	return int(id), true
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/internal/search"
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	searcher search.Searcher
+}
+
+func NewSearchHandler(s search.Searcher) *SearchHandler {
+	return &SearchHandler{
+		searcher: s,
+	}
+}
+
+// SearchCustomers godoc
+// @Summary Search customers
+// @Tags Customers
+// @Description Full-text search of customers by name, tolerant to partial and misspelled names, most relevant first
+// @Produce json
+// @Param q query string true "Name, or part of it, to search for"
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Results per page, up to 100 (default 20)"
+// @Success 200 {object} web.Responses{data=dto.CustomerSearchPage} "Success"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/search [get]
+func (s *SearchHandler) Customers(c *gin.Context) {
+	var req dto.SearchCustomersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	page, err := s.searcher.Search(c.Request.Context(), req.Query, req.Page, req.PageSize)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusOK, page)
+}
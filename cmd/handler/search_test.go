@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/internal/search"
+	"github.com/danilosano/web-golang-api/pkg/middleware"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchCustomers(t *testing.T) {
+	index := search.NewMemoryIndex()
+	index.Index(mockedResultCustomer)
+	server := testutil.CreateServer()
+	server.Use(middleware.ErrorHandler())
+	server.GET(pathCustomer+"search", NewSearchHandler(index).Customers)
+
+	t.Run("When the search matches, the ranked results are returned with a 200 code.", func(t *testing.T) {
+		var result struct {
+			Data dto.CustomerSearchPage `json:"data"`
+		}
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"search?q=danil&page_size=5", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &result)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, result.Data.Total)
+		assert.Equal(t, 5, result.Data.PageSize)
+		assert.Equal(t, mockedResultCustomer, result.Data.Results[0].ResultCustomerRequest)
+	})
+
+	t.Run("When the query is missing, a 400 code will be returned.", func(t *testing.T) {
+		var resp web.ErrorResponse
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"search?page=0", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.Equal(t, []web.FieldError{{Field: "q", Message: "is required"}}, resp.Fields)
+	})
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type StatusHandler struct {
+	service customer.Service
+}
+
+func NewStatusHandler(s customer.Service) *StatusHandler {
+	return &StatusHandler{
+		service: s,
+	}
+}
+
+// ChangeCustomerStatus godoc
+// @Summary Change customer status
+// @Tags Customers
+// @Description Move a customer along its lifecycle: prospect, active, suspended and closed
+// @Accept json,xml,application/msgpack
+// @Produce json,xml,application/msgpack
+// @Param id path int true "Customer ID"
+// @Param status body dto.CustomerStatusRequest true "New status and the reason for it"
+// @Success 200 {object} web.Responses{data=dto.ResultCustomerRequest} "Success"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 409 {object} web.ErrorResponse "Conflict"
+// @Failure 422 {object} web.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/status [post]
+func (s *StatusHandler) Change(c *gin.Context) {
+	id, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.CustomerStatusRequest
+	if err := web.ShouldBind(c, &req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	result, err := s.service.ChangeStatus(c.Request.Context(), id, req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusOK, result)
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/middleware"
+	mocks "github.com/danilosano/web-golang-api/pkg/tests/customers"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func InitServerWithStatusRoute(t *testing.T) (*gin.Engine, *mocks.CustomersServiceMock, context.Context) {
+	t.Helper()
+	server := testutil.CreateServer()
+	server.Use(middleware.ErrorHandler())
+	mockService := new(mocks.CustomersServiceMock)
+	handler := NewStatusHandler(mockService)
+	server.POST(pathCustomer+":id/status", handler.Change)
+	return server, mockService, context.Background()
+}
+
+func TestChangeStatus(t *testing.T) {
+	input := dto.CustomerStatusRequest{Status: domain.CustomerStatusActive, Reason: "Signed the contract"}
+
+	t.Run("The customer in its new status is returned with a 200 code.", func(t *testing.T) {
+		var result struct {
+			Data dto.ResultCustomerRequest `json:"data"`
+		}
+		active := dto.ResultCustomerRequest{ID: 1, FirstName: "Danilo", LastName: "Sano", Status: domain.CustomerStatusActive}
+		server, service, ctx := InitServerWithStatusRoute(t)
+		service.On("ChangeStatus", ctx, 1, input).Return(active, nil)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathCustomer+"1/status", `{"status": "active", "reason": "Signed the contract"}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &result)
+		assert.Nil(t, err)
+		assert.Equal(t, active, result.Data)
+	})
+
+	t.Run("An unknown status, or a missing reason, is answered with a 400 code.", func(t *testing.T) {
+		var resp web.ErrorResponse
+		server, _, _ := InitServerWithStatusRoute(t)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathCustomer+"1/status", `{"status": "archived"}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.Len(t, resp.Fields, 2)
+	})
+
+	t.Run("An illegal transition is answered with a 409 code.", func(t *testing.T) {
+		server, service, ctx := InitServerWithStatusRoute(t)
+		service.On("ChangeStatus", ctx, 1, input).Return(nil, fmt.Errorf("%w: from closed to active", customer.ErrorIllegalStatusTransition))
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathCustomer+"1/status", `{"status": "active", "reason": "Signed the contract"}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+
+	t.Run("When the customer does not exist, a 404 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithStatusRoute(t)
+		service.On("ChangeStatus", ctx, 9, input).Return(nil, customer.ErrorCustomerNotFound)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathCustomer+"9/status", `{"status": "active", "reason": "Signed the contract"}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"io"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/stream"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+// streamHeartbeat keeps idle connections open through proxies.
+const streamHeartbeat = 15 * time.Second
+
+type StreamHandler struct {
+	hub *stream.Hub
+}
+
+func NewStreamHandler(h *stream.Hub) *StreamHandler {
+	return &StreamHandler{
+		hub: h,
+	}
+}
+
+// StreamCustomers godoc
+// @Summary Stream customer changes
+// @Tags Customers
+// @Description Server-Sent Events stream of customer.created, customer.updated and customer.deleted events.
+// @Description Reconnecting clients send Last-Event-ID to receive the events they missed.
+// @Produce text/event-stream
+// @Param Last-Event-ID header string false "ID of the last event received"
+// @Success 200 {object} domain.Event "Stream of events"
+// @Router /api/v1/customers/stream [get]
+func (s *StreamHandler) Customers(c *gin.Context) {
+	replay, events, cancel := s.hub.Subscribe(c.GetHeader("Last-Event-ID"))
+	defer cancel()
+
+	// Set before the first flush, which sends the headers even with nothing to replay.
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	for _, e := range replay {
+		c.Render(-1, toSSE(e))
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			c.Render(-1, toSSE(e))
+		case <-heartbeat.C:
+			_, _ = io.WriteString(c.Writer, ": heartbeat\n\n")
+		}
+		c.Writer.Flush()
+	}
+}
+
+func toSSE(e domain.Event) sse.Event {
+	return sse.Event{
+		Id:    e.ID,
+		Event: e.Type,
+		Data:  e,
+	}
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/stream"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamCustomers(t *testing.T) {
+	t.Run("Missed events are replayed after Last-Event-ID and new events are pushed as they happen.", func(t *testing.T) {
+		hub := stream.NewHub(10)
+		for _, id := range []string{"e1", "e2"} {
+			_ = hub.Publish(context.Background(), domain.Event{ID: id, Type: domain.EventCustomerCreated, CustomerID: 1})
+		}
+
+		server := testutil.CreateServer()
+		server.GET(pathCustomer+"stream", NewStreamHandler(hub).Customers)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"stream", "")
+		request = request.WithContext(ctx)
+		request.Header.Set("Last-Event-ID", "e1")
+
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			_ = hub.Publish(context.Background(), domain.Event{ID: "e3", Type: domain.EventCustomerDeleted, CustomerID: 1})
+			time.Sleep(50 * time.Millisecond)
+			cancel()
+		}()
+		server.ServeHTTP(response, request)
+
+		body := response.Body.String()
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "text/event-stream", response.Header().Get("Content-Type"))
+		assert.NotContains(t, body, "id:e1\n")
+		assert.Contains(t, body, "id:e2\nevent:customer.created\n")
+		assert.Contains(t, body, "id:e3\nevent:customer.deleted\n")
+		assert.Less(t, strings.Index(body, "id:e2"), strings.Index(body, "id:e3"))
+	})
+
+	t.Run("A new subscription without Last-Event-ID is sent as an event stream right away.", func(t *testing.T) {
+		hub := stream.NewHub(10)
+		server := testutil.CreateServer()
+		server.GET(pathCustomer+"stream", NewStreamHandler(hub).Customers)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"stream", "")
+		request = request.WithContext(ctx)
+
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			cancel()
+		}()
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "text/event-stream", response.Header().Get("Content-Type"))
+		assert.Empty(t, response.Body.String())
+	})
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/internal/tag"
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	web.RegisterError(tag.ErrorTagNotFound, http.StatusNotFound)
+	web.RegisterError(tag.ErrorInvalidTag, http.StatusBadRequest)
+}
+
+type TagHandler struct {
+	service tag.Service
+}
+
+func NewTagHandler(s tag.Service) *TagHandler {
+	return &TagHandler{
+		service: s,
+	}
+}
+
+// GetTags godoc
+// @Summary List customer tags
+// @Tags Tags
+// @Produce json,xml,application/msgpack,text/csv
+// @Param id path int true "Customer ID"
+// @Success 200 {object} web.Responses{data=[]domain.Tag} "Success"
+// @Success 204 "No Content"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/tags [get]
+func (h *TagHandler) GetAll(c *gin.Context) {
+	customerID, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	tags, err := h.service.GetAll(c.Request.Context(), customerID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if tags == nil {
+		web.Success(c, http.StatusNoContent, tags)
+		return
+	}
+
+	web.Success(c, http.StatusOK, tags)
+}
+
+// AddTags godoc
+// @Summary Tag customer
+// @Tags Tags
+// @Description Put tags on a customer, creating the tags that do not exist yet. Names are stored in lower case and may hold letters, digits, '-', '_' and ':'. Tags the customer already carries are left untouched.
+// @Accept json,xml,application/msgpack
+// @Produce json,xml,application/msgpack
+// @Param id path int true "Customer ID"
+// @Param tags body dto.TagRequest true "Tags to be added"
+// @Success 200 {object} web.Responses{data=[]domain.Tag} "Every tag of the customer"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 422 {object} web.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/tags [post]
+func (h *TagHandler) Store(c *gin.Context) {
+	customerID, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.TagRequest
+	if err := web.ShouldBind(c, &req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	tags, err := h.service.Add(c.Request.Context(), customerID, req.Tags)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusOK, tags)
+}
+
+// RemoveTag godoc
+// @Summary Untag customer
+// @Tags Tags
+// @Param id path int true "Customer ID"
+// @Param tag path string true "Tag name, regardless of case"
+// @Success 204
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/tags/{tag} [delete]
+func (h *TagHandler) Delete(c *gin.Context) {
+	customerID, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.Remove(c.Request.Context(), customerID, c.Param("tag")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusNoContent, nil)
+}
+
+// BulkTag godoc
+// @Summary Tag several customers
+// @Tags Tags
+// @Description Put the same tags on several customers at once. When one of the customers does not exist, none of them is tagged.
+// @Accept json,xml,application/msgpack
+// @Param tags body dto.BulkTagRequest true "Customers and the tags to put on them"
+// @Success 204
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 422 {object} web.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/tags [post]
+func (h *TagHandler) Bulk(c *gin.Context) {
+	var req dto.BulkTagRequest
+	if err := web.ShouldBind(c, &req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err := h.service.AddToCustomers(c.Request.Context(), req.CustomerIDs, req.Tags); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusNoContent, nil)
+}
+
+// CountTags godoc
+// @Summary Tag counts
+// @Tags Tags
+// @Description Get how many customers carry each tag, the most used tags first
+// @Produce json,xml,application/msgpack,text/csv
+// @Success 200 {object} web.Responses{data=[]domain.TagCount} "Success"
+// @Success 204 "No Content"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/tags [get]
+func (h *TagHandler) Counts(c *gin.Context) {
+	counts, err := h.service.Counts(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if counts == nil {
+		web.Success(c, http.StatusNoContent, counts)
+		return
+	}
+
+	web.Success(c, http.StatusOK, counts)
+}
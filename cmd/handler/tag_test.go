@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/tag"
+	"github.com/danilosano/web-golang-api/pkg/middleware"
+	mocks "github.com/danilosano/web-golang-api/pkg/tests/tags"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var mockedTags = []domain.Tag{
+	{Name: "churn-risk", TaggedAt: time.Date(2021, 10, 10, 0, 0, 0, 0, time.UTC)},
+	{Name: "vip", TaggedAt: time.Date(2021, 10, 10, 0, 0, 0, 0, time.UTC)},
+}
+
+func InitServerWithTagsRoute(t *testing.T) (*gin.Engine, *mocks.TagServiceMock, context.Context) {
+	t.Helper()
+	server := testutil.CreateServer()
+	server.Use(middleware.ErrorHandler())
+	mockService := new(mocks.TagServiceMock)
+	handler := NewTagHandler(mockService)
+	server.GET(pathCustomer+"tags", handler.Counts)
+	server.POST(pathCustomer+"tags", handler.Bulk)
+	server.GET(pathCustomer+":id/tags", handler.GetAll)
+	server.POST(pathCustomer+":id/tags", handler.Store)
+	server.DELETE(pathCustomer+":id/tags/:tag", handler.Delete)
+	return server, mockService, context.Background()
+}
+
+func TestStoreTags(t *testing.T) {
+	t.Run("Every tag of the customer is returned with a 200 code.", func(t *testing.T) {
+		var result struct {
+			Data []domain.Tag `json:"data"`
+		}
+		server, service, ctx := InitServerWithTagsRoute(t)
+		service.On("Add", ctx, 1, []string{"VIP", "churn-risk"}).Return(mockedTags, nil)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathCustomer+"1/tags", `{"tags": ["VIP", "churn-risk"]}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &result)
+		assert.Nil(t, err)
+		assert.Equal(t, mockedTags, result.Data)
+	})
+
+	t.Run("Without tags, a 400 code will be returned.", func(t *testing.T) {
+		server, _, _ := InitServerWithTagsRoute(t)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathCustomer+"1/tags", `{"tags": []}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("An invalid tag name is answered with a 400 code.", func(t *testing.T) {
+		server, service, ctx := InitServerWithTagsRoute(t)
+		service.On("Add", ctx, 1, []string{"high value"}).Return(nil, fmt.Errorf("%w: %q", tag.ErrorInvalidTag, "high value"))
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathCustomer+"1/tags", `{"tags": ["high value"]}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestGetTags(t *testing.T) {
+	t.Run("When the customer has no tags, a 204 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithTagsRoute(t)
+		service.On("GetAll", ctx, 1).Return(nil, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1/tags", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+
+	t.Run("When the customer does not exist, a 404 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithTagsRoute(t)
+		service.On("GetAll", ctx, 9).Return(nil, customer.ErrorCustomerNotFound)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"9/tags", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestDeleteTag(t *testing.T) {
+	t.Run("If the tag is removed, a 204 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithTagsRoute(t)
+		service.On("Remove", ctx, 1, "vip").Return(nil)
+
+		request, response := testutil.MakeRequest(http.MethodDelete, pathCustomer+"1/tags/vip", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+
+	t.Run("When the customer does not carry the tag, a 404 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithTagsRoute(t)
+		service.On("Remove", ctx, 1, "vip").Return(tag.ErrorTagNotFound)
+
+		request, response := testutil.MakeRequest(http.MethodDelete, pathCustomer+"1/tags/vip", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestBulkTag(t *testing.T) {
+	t.Run("If the customers are tagged, a 204 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithTagsRoute(t)
+		service.On("AddToCustomers", ctx, []int{1, 2}, []string{"vip"}).Return(nil)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathCustomer+"tags", `{"customer_ids": [1, 2], "tags": ["vip"]}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+
+	t.Run("When one of the customers does not exist, a 404 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithTagsRoute(t)
+		service.On("AddToCustomers", ctx, []int{1, 9}, []string{"vip"}).Return(fmt.Errorf("%w: %d", customer.ErrorCustomerNotFound, 9))
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathCustomer+"tags", `{"customer_ids": [1, 9], "tags": ["vip"]}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+		assert.Contains(t, response.Body.String(), "customer not found: 9")
+	})
+
+	t.Run("An invalid customer ID is answered with a 400 code.", func(t *testing.T) {
+		server, _, _ := InitServerWithTagsRoute(t)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathCustomer+"tags", `{"customer_ids": [0], "tags": ["vip"]}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestCountTags(t *testing.T) {
+	t.Run("The counts are returned with a 200 code.", func(t *testing.T) {
+		server, service, ctx := InitServerWithTagsRoute(t)
+		service.On("Counts", ctx).Return([]domain.TagCount{{Name: "vip", Count: 3}}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"tags", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"data": [{"name": "vip", "count": 3}]}`, response.Body.String())
+	})
+}
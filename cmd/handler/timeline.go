@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/internal/timeline"
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	web.RegisterError(timeline.ErrorInvalidCursor, http.StatusBadRequest)
+}
+
+type TimelineHandler struct {
+	service timeline.Service
+}
+
+func NewTimelineHandler(s timeline.Service) *TimelineHandler {
+	return &TimelineHandler{
+		service: s,
+	}
+}
+
+// GetCustomerTimeline godoc
+// @Summary Customer timeline
+// @Tags Customers
+// @Description Get the changes, notes and status changes of a customer merged oldest first. Pass the next_cursor of a page to get the following one; it is absent on the last page.
+// @Produce json
+// @Param id path int true "Customer ID"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param limit query int false "Events per page, up to 100 (default 20)"
+// @Success 200 {object} web.Responses{data=dto.TimelinePage} "Success"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/customers/{id}/timeline [get]
+func (h *TimelineHandler) Get(c *gin.Context) {
+	customerID, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var f dto.TimelineFilter
+	if err := c.ShouldBindQuery(&f); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	page, err := h.service.Get(c.Request.Context(), customerID, f)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusOK, page)
+}
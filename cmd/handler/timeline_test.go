@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/internal/timeline"
+	"github.com/danilosano/web-golang-api/pkg/middleware"
+	mocks "github.com/danilosano/web-golang-api/pkg/tests/timeline"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func InitServerWithTimelineRoute(t *testing.T) (*gin.Engine, *mocks.TimelineServiceMock, context.Context) {
+	t.Helper()
+	server := testutil.CreateServer()
+	server.Use(middleware.ErrorHandler())
+	mockService := new(mocks.TimelineServiceMock)
+	server.GET(pathCustomer+":id/timeline", NewTimelineHandler(mockService).Get)
+	return server, mockService, context.Background()
+}
+
+func TestTimeline(t *testing.T) {
+	t.Run("The page of events is returned with its next cursor and a 200 code.", func(t *testing.T) {
+		server, service, ctx := InitServerWithTimelineRoute(t)
+		at := time.Date(2021, 10, 10, 0, 0, 0, 0, time.UTC)
+		service.On("Get", ctx, 1, dto.TimelineFilter{Cursor: "abc", Limit: 1}).Return(dto.TimelinePage{
+			Events:     []domain.TimelineEvent{{Type: domain.TimelineEventNote, OccurredAt: at, Actor: "agent-1", Note: &mockedNote}},
+			NextCursor: "def",
+		}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1/timeline?cursor=abc&limit=1", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Contains(t, response.Body.String(), `"next_cursor":"def"`)
+	})
+
+	t.Run("An invalid cursor or limit is answered with a 400 code.", func(t *testing.T) {
+		server, service, ctx := InitServerWithTimelineRoute(t)
+		service.On("Get", ctx, 1, dto.TimelineFilter{Cursor: "abc"}).Return(nil, timeline.ErrorInvalidCursor)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"1/timeline?cursor=abc", "")
+		server.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+
+		request, response = testutil.MakeRequest(http.MethodGet, pathCustomer+"1/timeline?limit=500", "")
+		server.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
@@ -0,0 +1,208 @@
+// Package v2 holds the handlers of the v2 REST API. They share the service layer
+// with v1 and differ only in their contract: every successful response is wrapped
+// in web.Responses, and the customer listing is paginated and filterable.
+package v2
+
+import (
+	"net/http"
+
+	"github.com/danilosano/web-golang-api/cmd/handler"
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type CustomerHandler struct {
+	service  customer.Service
+	expander handler.Expander
+}
+
+func NewCustomerHandler(s customer.Service, expander handler.Expander) *CustomerHandler {
+	return &CustomerHandler{
+		service:  s,
+		expander: expander,
+	}
+}
+
+// CreateCustomers godoc
+// @Summary Create customer
+// @Tags Customers v2
+// @Description Create a customer. When customer_number is left out, the server allocates the next one if numbering is enabled.
+// @Accept json,xml,application/msgpack
+// @Produce json,xml,application/msgpack
+// @Param customer body dto.CreateCustomerRequest true "Customer to be created"
+// @Success 201 {object} web.Responses{data=dto.ResultCustomerRequest} "Success"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 409 {object} web.ErrorResponse "Conflict"
+// @Failure 422 {object} web.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v2/customers [post]
+func (h *CustomerHandler) Store(c *gin.Context) {
+	var req dto.CreateCustomerRequest
+	if err := web.ShouldBind(c, &req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	result, err := h.service.Save(c.Request.Context(), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusCreated, result)
+}
+
+// GetCustomers godoc
+// @Summary List customers
+// @Tags Customers v2
+// @Description Get a page of the customers, optionally filtered. An empty page is returned with a 200.
+// @Produce json,xml,application/msgpack
+// @Param If-None-Match header string false "ETag of the copy held by the client"
+// @Param If-Modified-Since header string false "Last-Modified date of the copy held by the client"
+// @Param customer_number query int false "Customer number"
+// @Param first_name query string false "Beginning of the first name"
+// @Param last_name query string false "Beginning of the last name"
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Customers per page, up to 100 (default 20)"
+// @Param email query string false "Email of the customer, in any case"
+// @Param status query string false "Status of the customer" Enums(prospect, active, suspended, closed)
+// @Param tag query []string false "Tags of the customer, regardless of case" collectionFormat(multi)
+// @Param tag_match query string false "Whether the customers carry any (default) or all of the tags" Enums(any, all)
+// @Param attr query []string false "Custom attribute values of the customer, each as name:value" collectionFormat(multi)
+// @Param expand query string false "Related resources to embed" Enums(addresses, contacts, tags)
+// @Success 200 {object} web.Responses{data=dto.CustomerPage} "Success"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v2/customers [get]
+func (h *CustomerHandler) GetAll(c *gin.Context) {
+	var f dto.CustomerFilter
+	if err := c.ShouldBindQuery(&f); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	page, err := h.service.List(c.Request.Context(), f)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err := h.expander.Expand(c, page.Customers); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if handler.CustomersNotModified(c, page.Customers...) {
+		return
+	}
+
+	web.Success(c, http.StatusOK, page)
+}
+
+// GetCustomer godoc
+// @Summary Get customer
+// @Tags Customers v2
+// @Description Get customer by ID
+// @Produce json,xml,application/msgpack
+// @Param If-None-Match header string false "ETag of the copy held by the client"
+// @Param If-Modified-Since header string false "Last-Modified date of the copy held by the client"
+// @Param id path int true "Customer ID"
+// @Param expand query string false "Related resources to embed" Enums(addresses, contacts, tags)
+// @Success 200 {object} web.Responses{data=dto.ResultCustomerRequest} "Success"
+// @Success 301 "Merged into the customer given by the Location header"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v2/customers/{id} [get]
+func (h *CustomerHandler) Get(c *gin.Context) {
+	id, ok := handler.IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	result, err := h.service.Get(c.Request.Context(), id)
+	if err != nil {
+		if handler.RedirectMerged(c, err) {
+			return
+		}
+		_ = c.Error(err)
+		return
+	}
+
+	expanded := []dto.ResultCustomerRequest{result}
+	if err := h.expander.Expand(c, expanded); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	result = expanded[0]
+
+	if handler.CustomersNotModified(c, result) {
+		return
+	}
+
+	web.Success(c, http.StatusOK, result)
+}
+
+// UpdateCustomer godoc
+// @Summary Update customer
+// @Tags Customers v2
+// @Description update customer
+// @Accept json,xml,application/msgpack
+// @Produce json,xml,application/msgpack
+// @Param id path int true "Customer ID"
+// @Param customer body dto.UpdateCustomerRequest true "Customer to be updated"
+// @Success 200 {object} web.Responses{data=dto.ResultCustomerRequest} "Success"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 409 {object} web.ErrorResponse "Conflict"
+// @Failure 422 {object} web.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v2/customers/{id} [put]
+func (h *CustomerHandler) Update(c *gin.Context) {
+	id, ok := handler.IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateCustomerRequest
+	if err := web.ShouldBind(c, &req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	result, err := h.service.Update(c.Request.Context(), req, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusOK, result)
+}
+
+// DeleteCustomer godoc
+// @Summary Delete customer
+// @Tags Customers v2
+// @Description delete customer
+// @Param id path int true "Customer ID"
+// @Success 204
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v2/customers/{id} [delete]
+func (h *CustomerHandler) Delete(c *gin.Context) {
+	id, ok := handler.IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,194 @@
+package v2
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/cmd/handler"
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/middleware"
+	mocks "github.com/danilosano/web-golang-api/pkg/tests/customers"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	pathCustomer = "/api/v2/customers/"
+)
+
+var (
+	customerNumber = 2
+	resultCustomer = dto.ResultCustomerRequest{
+		ID:             1,
+		CustomerNumber: &customerNumber,
+		FirstName:      "Danilo",
+		LastName:       "Sano",
+		CreatedAt:      time.Date(2021, 10, 10, 0, 0, 0, 0, time.UTC),
+	}
+)
+
+func initServer(t *testing.T) (*gin.Engine, *mocks.CustomersServiceMock) {
+	t.Helper()
+	server := testutil.CreateServer()
+	server.Use(middleware.ErrorHandler())
+	mockService := new(mocks.CustomersServiceMock)
+	handler := NewCustomerHandler(mockService, handler.Expander{})
+	server.GET(pathCustomer, handler.GetAll)
+	server.GET(pathCustomer+":id", handler.Get)
+	server.POST(pathCustomer, handler.Store)
+	server.PUT(pathCustomer+":id", handler.Update)
+	server.DELETE(pathCustomer+":id", handler.Delete)
+	return server, mockService
+}
+
+func TestStore(t *testing.T) {
+	t.Run("The created customer is wrapped in the data field with a 201 code.", func(t *testing.T) {
+		var result struct {
+			Data dto.ResultCustomerRequest `json:"data"`
+		}
+		server, mockService := initServer(t)
+		mockService.On("Save", mock.Anything, mock.Anything).Return(resultCustomer, nil)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathCustomer, `{"customer_number": 2, "first_name": "Danilo", "last_name": "Sano"}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &result)
+		assert.Nil(t, err)
+		assert.Equal(t, resultCustomer, result.Data)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	t.Run("The updated customer is wrapped in the data field with a 200 code.", func(t *testing.T) {
+		var result struct {
+			Data dto.ResultCustomerRequest `json:"data"`
+		}
+		server, mockService := initServer(t)
+		mockService.On("Update", mock.Anything, mock.Anything, 1).Return(resultCustomer, nil)
+
+		request, response := testutil.MakeRequest(http.MethodPut, pathCustomer+"1", `{"customer_number": 2, "first_name": "Danilo", "last_name": "Sano"}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &result)
+		assert.Nil(t, err)
+		assert.Equal(t, resultCustomer, result.Data)
+	})
+
+	t.Run("When the customer does not exist, a 404 code will be returned.", func(t *testing.T) {
+		server, mockService := initServer(t)
+		mockService.On("Update", mock.Anything, mock.Anything, 9).Return(nil, customer.ErrorCustomerNotFound)
+
+		request, response := testutil.MakeRequest(http.MethodPut, pathCustomer+"9", `{"customer_number": 2, "first_name": "Danilo", "last_name": "Sano"}`)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestGetAll(t *testing.T) {
+	t.Run("The filters and pagination in the query are passed to the service.", func(t *testing.T) {
+		var result struct {
+			Data dto.CustomerPage `json:"data"`
+		}
+		server, mockService := initServer(t)
+		page := dto.CustomerPage{Customers: []dto.ResultCustomerRequest{resultCustomer}, Page: 2, PageSize: 10, Total: 11}
+		mockService.On("List", mock.Anything, dto.CustomerFilter{LastName: "Sa", Page: 2, PageSize: 10}).Return(page, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"?last_name=Sa&page=2&page_size=10", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &result)
+		assert.Nil(t, err)
+		assert.Equal(t, page, result.Data)
+	})
+
+	t.Run("The customers can be filtered by status, an unknown status being answered with a 400 code.", func(t *testing.T) {
+		server, mockService := initServer(t)
+		mockService.On("List", mock.Anything, dto.CustomerFilter{Status: "suspended"}).Return(dto.CustomerPage{Customers: []dto.ResultCustomerRequest{}, Page: 1, PageSize: 20}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"?status=suspended", "")
+		server.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		request, response = testutil.MakeRequest(http.MethodGet, pathCustomer+"?status=archived", "")
+		server.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("The customers can be filtered by several tags, matching any or all of them.", func(t *testing.T) {
+		server, mockService := initServer(t)
+		filter := dto.CustomerFilter{Tags: []string{"vip", "churn-risk"}, TagMatch: "all"}
+		mockService.On("List", mock.Anything, filter).Return(dto.CustomerPage{Customers: []dto.ResultCustomerRequest{}, Page: 1, PageSize: 20}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"?tag=vip&tag=churn-risk&tag_match=all", "")
+		server.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		request, response = testutil.MakeRequest(http.MethodGet, pathCustomer+"?tag=vip&tag_match=some", "")
+		server.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("The customers can be filtered by custom attribute values, given as name:value.", func(t *testing.T) {
+		server, mockService := initServer(t)
+		filter := dto.CustomerFilter{Attributes: []string{"tier:gold", "renewal:2026-01-31"}}
+		mockService.On("List", mock.Anything, filter).Return(dto.CustomerPage{Customers: []dto.ResultCustomerRequest{}, Page: 1, PageSize: 20}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"?attr=tier:gold&attr=renewal:2026-01-31", "")
+		server.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		request, response = testutil.MakeRequest(http.MethodGet, pathCustomer+"?attr=gold", "")
+		server.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("An empty page is returned with a 200 code.", func(t *testing.T) {
+		server, mockService := initServer(t)
+		mockService.On("List", mock.Anything, mock.Anything).Return(dto.CustomerPage{Customers: []dto.ResultCustomerRequest{}, Page: 1, PageSize: 20}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer, "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"data": {"customers": [], "page": 1, "page_size": 20, "total": 0}}`, response.Body.String())
+	})
+
+	t.Run("If the page size is over the limit, a 400 code will be returned.", func(t *testing.T) {
+		server, _ := initServer(t)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathCustomer+"?page_size=500", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	t.Run("If the deletion is successful, a 204 code will be returned.", func(t *testing.T) {
+		server, mockService := initServer(t)
+		mockService.On("Delete", mock.Anything, 1).Return(nil)
+
+		request, response := testutil.MakeRequest(http.MethodDelete, pathCustomer+"1", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+
+	t.Run("If the ID is zero, a 400 code will be returned.", func(t *testing.T) {
+		server, _ := initServer(t)
+
+		request, response := testutil.MakeRequest(http.MethodDelete, pathCustomer+"0", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/internal/webhook"
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	web.RegisterError(webhook.ErrorWebhookNotFound, http.StatusNotFound)
+	web.RegisterError(webhook.ErrorDeliveryNotFound, http.StatusNotFound)
+	web.RegisterError(webhook.ErrorURLNotAllowed, http.StatusUnprocessableEntity)
+}
+
+type WebhookHandler struct {
+	service webhook.Service
+}
+
+func NewWebhookHandler(s webhook.Service) *WebhookHandler {
+	return &WebhookHandler{
+		service: s,
+	}
+}
+
+// CreateWebhook godoc
+// @Summary Create webhook subscription
+// @Tags Webhooks
+// @Description Subscribe an http or https URL to customer events. Deliveries are signed with the secret.
+// @Description URLs resolving to private, loopback, link-local, shared (CGNAT) or other non-public addresses are refused.
+// @Accept json
+// @Produce json
+// @Param webhook body dto.WebhookRequest true "Subscription to be created"
+// @Success 201 {object} web.Responses{data=domain.WebhookSubscription} "Success"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 422 {object} web.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/webhooks [post]
+func (w *WebhookHandler) Store(c *gin.Context) {
+	var req dto.WebhookRequest
+	if err := web.ShouldBind(c, &req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	sub, err := w.service.Save(c.Request.Context(), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusCreated, sub)
+}
+
+// GetWebhooks godoc
+// @Summary List webhook subscriptions
+// @Tags Webhooks
+// @Produce json
+// @Success 200 {object} web.Responses{data=[]domain.WebhookSubscription} "Success"
+// @Success 204 "No Content"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/webhooks [get]
+func (w *WebhookHandler) GetAll(c *gin.Context) {
+	subs, err := w.service.GetAll(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if subs == nil {
+		web.Success(c, http.StatusNoContent, subs)
+		return
+	}
+
+	web.Success(c, http.StatusOK, subs)
+}
+
+// GetWebhook godoc
+// @Summary Get webhook subscription
+// @Tags Webhooks
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} web.Responses{data=domain.WebhookSubscription} "Success"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/webhooks/{id} [get]
+func (w *WebhookHandler) Get(c *gin.Context) {
+	id, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	sub, err := w.service.Get(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusOK, sub)
+}
+
+// UpdateWebhook godoc
+// @Summary Update webhook subscription
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Param webhook body dto.WebhookRequest true "Subscription to be updated"
+// @Success 200 {object} web.Responses{data=domain.WebhookSubscription} "Success"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 422 {object} web.ErrorResponse "Unprocessable Entity"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/webhooks/{id} [put]
+func (w *WebhookHandler) Update(c *gin.Context) {
+	id, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.WebhookRequest
+	if err := web.ShouldBind(c, &req); err != nil {
+		_ = c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	sub, err := w.service.Update(c.Request.Context(), req, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusOK, sub)
+}
+
+// DeleteWebhook godoc
+// @Summary Delete webhook subscription
+// @Tags Webhooks
+// @Param id path int true "Subscription ID"
+// @Success 204
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/webhooks/{id} [delete]
+func (w *WebhookHandler) Delete(c *gin.Context) {
+	id, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := w.service.Delete(c.Request.Context(), id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusNoContent, nil)
+}
+
+// GetWebhookDeliveries godoc
+// @Summary List webhook deliveries
+// @Tags Webhooks
+// @Description Get the delivery log of a subscription, newest first
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} web.Responses{data=[]domain.WebhookDelivery} "Success"
+// @Success 204 "No Content"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/webhooks/{id}/deliveries [get]
+func (w *WebhookHandler) Deliveries(c *gin.Context) {
+	id, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	deliveries, err := w.service.GetDeliveries(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if deliveries == nil {
+		web.Success(c, http.StatusNoContent, deliveries)
+		return
+	}
+
+	web.Success(c, http.StatusOK, deliveries)
+}
+
+// RedeliverWebhook godoc
+// @Summary Redeliver webhook
+// @Tags Webhooks
+// @Description Schedule a new delivery of the event of a previous delivery
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Param delivery_id path int true "Delivery ID"
+// @Success 202 {object} web.Responses{data=domain.WebhookDelivery} "Accepted"
+// @Failure 400 {object} web.ErrorResponse "Bad Request"
+// @Failure 404 {object} web.ErrorResponse "Not Found"
+// @Failure 500 {object} web.ErrorResponse "Internal Server Error"
+// @Router /api/v1/webhooks/{id}/deliveries/{delivery_id}/redeliver [post]
+func (w *WebhookHandler) Redeliver(c *gin.Context) {
+	id, ok := IDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	deliveryID, ok := IDParam(c, "delivery_id")
+	if !ok {
+		return
+	}
+
+	delivery, err := w.service.Redeliver(c.Request.Context(), id, deliveryID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	web.Success(c, http.StatusAccepted, delivery)
+}
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/internal/webhook"
+	"github.com/danilosano/web-golang-api/pkg/middleware"
+	mocks "github.com/danilosano/web-golang-api/pkg/tests/webhook"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+const pathWebhook = "/api/v1/webhooks/"
+
+var (
+	mockedSubscription = domain.WebhookSubscription{
+		ID:         1,
+		URL:        "https://partner.example.com/hooks",
+		EventTypes: []string{domain.EventCustomerCreated},
+		Active:     true,
+		CreatedAt:  time.Date(2021, 10, 10, 0, 0, 0, 0, time.UTC),
+	}
+
+	webhookInput = dto.WebhookRequest{
+		URL:        "https://partner.example.com/hooks",
+		EventTypes: []string{domain.EventCustomerCreated},
+		Secret:     "0123456789abcdef",
+	}
+
+	jsonWebhookInput = `{
+		"url": "https://partner.example.com/hooks",
+		"event_types": ["customer.created"],
+		"secret": "0123456789abcdef"
+	}`
+)
+
+func InitServerWithWebhooksRoute(t *testing.T) (*gin.Engine, *mocks.WebhookServiceMock, context.Context) {
+	t.Helper()
+	server := testutil.CreateServer()
+	server.Use(middleware.ErrorHandler())
+	mockService := new(mocks.WebhookServiceMock)
+	handler := NewWebhookHandler(mockService)
+	server.POST(pathWebhook, handler.Store)
+	server.GET(pathWebhook, handler.GetAll)
+	server.GET(pathWebhook+":id", handler.Get)
+	server.PUT(pathWebhook+":id", handler.Update)
+	server.DELETE(pathWebhook+":id", handler.Delete)
+	server.GET(pathWebhook+":id/deliveries", handler.Deliveries)
+	server.POST(pathWebhook+":id/deliveries/:delivery_id/redeliver", handler.Redeliver)
+	return server, mockService, context.Background()
+}
+
+func TestStoreWebhook(t *testing.T) {
+	t.Run("When the subscription is valid, it is created and returned without its secret with a 201 code.", func(t *testing.T) {
+		var result struct {
+			Data domain.WebhookSubscription `json:"data"`
+		}
+		server, service, ctx := InitServerWithWebhooksRoute(t)
+		withSecret := mockedSubscription
+		withSecret.Secret = webhookInput.Secret
+		service.On("Save", ctx, webhookInput).Return(withSecret, nil)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathWebhook, jsonWebhookInput)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.NotContains(t, response.Body.String(), webhookInput.Secret)
+		err := json.Unmarshal(response.Body.Bytes(), &result)
+		assert.Nil(t, err)
+		assert.Equal(t, mockedSubscription, result.Data)
+	})
+
+	t.Run("When the URL is not http or https, a 400 code will be returned.", func(t *testing.T) {
+		var resp web.ErrorResponse
+		server, _, _ := InitServerWithWebhooksRoute(t)
+
+		body := `{"url": "file:///etc/passwd", "event_types": ["customer.created"], "secret": "0123456789abcdef"}`
+		request, response := testutil.MakeRequest(http.MethodPost, pathWebhook, body)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.Equal(t, []web.FieldError{{Field: "url", Message: "must be a valid http or https URL"}}, resp.Fields)
+	})
+
+	t.Run("When the event type is unknown and the secret too short, a 400 code will be returned.", func(t *testing.T) {
+		var resp web.ErrorResponse
+		server, _, _ := InitServerWithWebhooksRoute(t)
+
+		body := `{"url": "https://partner.example.com/hooks", "event_types": ["order.created"], "secret": "short"}`
+		request, response := testutil.MakeRequest(http.MethodPost, pathWebhook, body)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.Len(t, resp.Fields, 2)
+	})
+
+	t.Run("When the URL resolves to a private address, a 422 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithWebhooksRoute(t)
+		service.On("Save", ctx, webhookInput).Return(nil, webhook.ErrorURLNotAllowed)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathWebhook, jsonWebhookInput)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+}
+
+func TestGetAllWebhooks(t *testing.T) {
+	t.Run("The subscriptions are returned with a 200 code.", func(t *testing.T) {
+		server, service, ctx := InitServerWithWebhooksRoute(t)
+		service.On("GetAll", ctx).Return([]domain.WebhookSubscription{mockedSubscription}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathWebhook, "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("If there is no subscription, a 204 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithWebhooksRoute(t)
+		service.On("GetAll", ctx).Return(nil, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathWebhook, "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+}
+
+func TestGetWebhook(t *testing.T) {
+	t.Run("If the subscription does not exist, a 404 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithWebhooksRoute(t)
+		service.On("Get", ctx, 9).Return(nil, webhook.ErrorWebhookNotFound)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathWebhook+"9", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("If the ID is not a number, a 400 code will be returned.", func(t *testing.T) {
+		server, _, _ := InitServerWithWebhooksRoute(t)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathWebhook+"abc", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestUpdateWebhook(t *testing.T) {
+	t.Run("When the subscription is valid, it is updated and returned with a 200 code.", func(t *testing.T) {
+		server, service, ctx := InitServerWithWebhooksRoute(t)
+		service.On("Update", ctx, webhookInput, 1).Return(mockedSubscription, nil)
+
+		request, response := testutil.MakeRequest(http.MethodPut, pathWebhook+"1", jsonWebhookInput)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("If the subscription does not exist, a 404 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithWebhooksRoute(t)
+		service.On("Update", ctx, webhookInput, 9).Return(nil, webhook.ErrorWebhookNotFound)
+
+		request, response := testutil.MakeRequest(http.MethodPut, pathWebhook+"9", jsonWebhookInput)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	t.Run("The subscription is deleted with a 204 code.", func(t *testing.T) {
+		server, service, ctx := InitServerWithWebhooksRoute(t)
+		service.On("Delete", ctx, 1).Return(nil)
+
+		request, response := testutil.MakeRequest(http.MethodDelete, pathWebhook+"1", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	t.Run("The delivery log is returned with a 200 code.", func(t *testing.T) {
+		server, service, ctx := InitServerWithWebhooksRoute(t)
+		service.On("GetDeliveries", ctx, 1).Return([]domain.WebhookDelivery{{ID: 7, SubscriptionID: 1, EventID: "e1", Payload: json.RawMessage(`{}`)}}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodGet, pathWebhook+"1/deliveries", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("A redelivery is accepted with a 202 code.", func(t *testing.T) {
+		server, service, ctx := InitServerWithWebhooksRoute(t)
+		service.On("Redeliver", ctx, 1, 7).Return(domain.WebhookDelivery{ID: 8, SubscriptionID: 1, EventID: "e1", Payload: json.RawMessage(`{}`)}, nil)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathWebhook+"1/deliveries/7/redeliver", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusAccepted, response.Code)
+	})
+
+	t.Run("If the delivery does not exist, a 404 code will be returned.", func(t *testing.T) {
+		server, service, ctx := InitServerWithWebhooksRoute(t)
+		service.On("Redeliver", ctx, 1, 9).Return(nil, webhook.ErrorDeliveryNotFound)
+
+		request, response := testutil.MakeRequest(http.MethodPost, pathWebhook+"1/deliveries/9/redeliver", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
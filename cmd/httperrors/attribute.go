@@ -0,0 +1,15 @@
+package httperrors
+
+import (
+	"net/http"
+
+	"github.com/danilosano/web-golang-api/internal/attribute"
+	"github.com/danilosano/web-golang-api/pkg/web"
+)
+
+func init() {
+	web.RegisterError(attribute.ErrorAttributeNotFound, http.StatusNotFound)
+	web.RegisterError(attribute.ErrorAttributeAlreadyExist, http.StatusConflict)
+	web.RegisterError(attribute.ErrorInvalidDefinition, http.StatusBadRequest)
+	web.RegisterError(attribute.ErrorInvalidAttribute, http.StatusBadRequest)
+}
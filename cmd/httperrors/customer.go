@@ -0,0 +1,21 @@
+// Package httperrors registers the HTTP status of the service errors reported by
+// both the REST and the GraphQL APIs, so that each error has a single mapping.
+// Both import it for its side effects.
+package httperrors
+
+import (
+	"net/http"
+
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/pkg/web"
+)
+
+func init() {
+	web.RegisterError(customer.ErrorCustomerNotFound, http.StatusNotFound)
+	web.RegisterError(customer.ErrorCustomerNumberAlreadyExist, http.StatusConflict)
+	web.RegisterError(customer.ErrorCustomerNumberRequired, http.StatusBadRequest)
+	web.RegisterError(customer.ErrorIllegalStatusTransition, http.StatusConflict)
+	web.RegisterError(customer.ErrorParentNotFound, http.StatusUnprocessableEntity)
+	web.RegisterError(customer.ErrorHierarchyCycle, http.StatusUnprocessableEntity)
+	web.RegisterError(customer.ErrorMergeSameCustomer, http.StatusUnprocessableEntity)
+}
@@ -2,53 +2,342 @@ package routes
 
 import (
 	"database/sql"
+	"expvar"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/danilosano/web-golang-api/cmd/graph"
 	"github.com/danilosano/web-golang-api/cmd/handler"
+	handlerv2 "github.com/danilosano/web-golang-api/cmd/handler/v2"
+	"github.com/danilosano/web-golang-api/internal/address"
+	"github.com/danilosano/web-golang-api/internal/attachment"
+	"github.com/danilosano/web-golang-api/internal/attribute"
+	"github.com/danilosano/web-golang-api/internal/audit"
+	"github.com/danilosano/web-golang-api/internal/contact"
 	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/note"
+	"github.com/danilosano/web-golang-api/internal/outbox"
+	"github.com/danilosano/web-golang-api/internal/search"
+	"github.com/danilosano/web-golang-api/internal/stream"
+	"github.com/danilosano/web-golang-api/internal/tag"
+	"github.com/danilosano/web-golang-api/internal/timeline"
+	"github.com/danilosano/web-golang-api/internal/webhook"
+	"github.com/danilosano/web-golang-api/pkg/blob"
+	"github.com/danilosano/web-golang-api/pkg/cache"
+	"github.com/danilosano/web-golang-api/pkg/database"
+	"github.com/danilosano/web-golang-api/pkg/middleware"
+	"github.com/danilosano/web-golang-api/pkg/ratelimit"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+var (
+	// CustomerReadLimit applies to every customer route and gRPC call.
+	CustomerReadLimit = ratelimit.PerMinute(300)
+	// CustomerWriteLimit additionally applies to routes and gRPC calls changing
+	// customers.
+	CustomerWriteLimit = ratelimit.PerMinute(30)
+)
+
+var (
+	// v1DeprecatedAt and v1Sunset announce the retirement of the v1 customer routes,
+	// superseded by the v2 ones.
+	v1DeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	v1Sunset       = time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC)
+)
+
+var (
+	// apiPolicy forbids the API responses to load anything or to be framed.
+	apiPolicy = "default-src 'none'; frame-ancestors 'none'"
+	// docsPolicy lets the swagger UI run its inline scripts and styles.
+	docsPolicy = "default-src 'self'; script-src 'self' 'unsafe-inline'; style-src 'self' 'unsafe-inline'; img-src 'self' data:; frame-ancestors 'none'"
+	// graphiqlPolicy lets GraphiQL load its assets from unpkg and query the API.
+	graphiqlPolicy = "default-src 'self'; script-src 'self' 'unsafe-inline' https://unpkg.com; style-src 'self' 'unsafe-inline' https://unpkg.com; img-src 'self' data:; connect-src 'self'; frame-ancestors 'none'"
+
+	securityConfig = middleware.SecurityConfig{
+		HSTSMaxAge:            365 * 24 * time.Hour,
+		HSTSIncludeSubdomains: true,
+		FrameOptions:          "DENY",
+		ContentSecurityPolicy: apiPolicy,
+		ReferrerPolicy:        "no-referrer",
+	}
+)
+
+const (
+	// compressMinSize is the smallest response worth compressing.
+	compressMinSize = 1024
+
+	customerCacheSize = 10000
+	customerCacheTTL  = 5 * time.Minute
+)
+
 type Router interface {
 	MapRoutes()
 }
 
+// Config holds the components shared with the rest of the application.
+type Config struct {
+	// Customers is the customer service behind the customer routes, built by
+	// NewCustomerService when nil.
+	Customers customer.Service
+	// Events receives the customer events pushed to the stream clients.
+	Events *stream.Hub
+	// Searcher backs the customer search, defaulting to the MySQL FULLTEXT index.
+	Searcher search.Searcher
+	// GraphiQL serves the GraphiQL playground on GET /api/v1/graphql.
+	GraphiQL bool
+	// AllowedOrigins lists the origins of the browser applications calling the
+	// API, see middleware.CORSConfig.
+	AllowedOrigins []string
+	// Attachments stores the content of the customer attachments, defaulting
+	// to the attachments directory.
+	Attachments blob.Storage
+	// TrustIdentity takes the api_token and X-User-ID headers as authenticated,
+	// rate limiting the clients by token or user instead of IP address. Enable it
+	// only behind a gateway verifying them.
+	TrustIdentity bool
+	// TrustedProxies lists the addresses or CIDR ranges of the proxies whose
+	// X-Forwarded-For header gives the client IP address. None is trusted when
+	// empty, the clients being identified by the address connecting to the API.
+	TrustedProxies []string
+}
+
+// customerAPI is implemented by the customer handlers of every API version.
+type customerAPI interface {
+	Store(c *gin.Context)
+	GetAll(c *gin.Context)
+	Get(c *gin.Context)
+	Update(c *gin.Context)
+	Delete(c *gin.Context)
+}
+
 type router struct {
-	eng *gin.Engine
-	rg  *gin.RouterGroup
-	db  *sql.DB
+	eng     *gin.Engine
+	v1      *gin.RouterGroup
+	v2      *gin.RouterGroup
+	db      *sql.DB
+	limiter ratelimit.Store
+	cfg     Config
+	// related holds the services of the customer sub-resources.
+	related handler.Expander
 }
 
-func NewRouter(eng *gin.Engine, db *sql.DB) Router {
-	return &router{eng: eng, db: db}
+func NewRouter(eng *gin.Engine, db *sql.DB, cfg Config) Router {
+	if cfg.Customers == nil {
+		cfg.Customers = NewCustomerService(db)
+	}
+	if cfg.Events == nil {
+		cfg.Events = stream.NewHub(0)
+	}
+	if cfg.Searcher == nil {
+		cfg.Searcher = search.NewMySQLSearcher(db)
+	}
+	if cfg.Attachments == nil {
+		cfg.Attachments = blob.NewFileSystem("attachments")
+	}
+	return &router{eng: eng, db: db, limiter: ratelimit.NewMemoryStore(), cfg: cfg, related: newRelatedServices(db)}
+}
+
+// newRelatedServices builds the services of the records owned by the customers.
+func newRelatedServices(db *sql.DB) handler.Expander {
+	customers := customer.NewRepository(db)
+	transactor := database.NewTransactor(db)
+	return handler.Expander{
+		Addresses: address.NewService(address.NewRepository(db), customers, address.WithTransactor(transactor)),
+		Contacts:  contact.NewService(contact.NewRepository(db), customers, contact.WithTransactor(transactor)),
+		Tags:      tag.NewService(tag.NewRepository(db), customers, tag.WithTransactor(transactor)),
+	}
 }
 
 func (r *router) MapRoutes() {
-	r.setGroup()
+	// Rate limiting keys anonymous clients by ClientIP, which must not be taken
+	// from a header any client can send.
+	if err := r.eng.SetTrustedProxies(r.cfg.TrustedProxies); err != nil {
+		log.Fatalf("error setting the trusted proxies: %s\n", err.Error())
+	}
+	r.eng.Use(
+		middleware.RequestID(),
+		middleware.SecurityHeaders(securityConfig),
+		middleware.CORS(r.corsConfig()),
+		middleware.Compress(compressMinSize),
+		middleware.ErrorHandler(),
+		middleware.Identity(),
+	)
+	if r.cfg.TrustIdentity {
+		r.eng.Use(middleware.TrustIdentity())
+	}
+	r.setGroups()
 
 	r.buildSwaggerRoutes()
-	r.buildCustomerRoutes()
+	r.buildCustomerRoutes(r.v1, handler.NewCustomerHandler(r.cfg.Customers, r.related),
+		middleware.Deprecation(v1DeprecatedAt, v1Sunset, r.v2.BasePath()+"/customers"))
+	r.buildCustomerRoutes(r.v2, handlerv2.NewCustomerHandler(r.cfg.Customers, r.related))
+	r.buildWebhookRoutes()
+	r.buildAttributeRoutes()
+	r.buildGraphQLRoutes()
+}
+
+// corsConfig lets the configured origins send every request the API serves and
+// read the headers it returns.
+func (r *router) corsConfig() middleware.CORSConfig {
+	return middleware.CORSConfig{
+		AllowedOrigins: r.cfg.AllowedOrigins,
+		AllowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete},
+		AllowedHeaders: []string{
+			"Accept", "Content-Type", "Authorization", "If-None-Match", "If-Modified-Since", "Last-Event-ID",
+			middleware.HeaderUserID, middleware.HeaderRequestID, middleware.HeaderAPIToken,
+		},
+		ExposedHeaders: []string{
+			middleware.HeaderRequestID, "ETag", "Last-Modified", "Deprecation", "Sunset", "Link", "Content-Disposition", "Digest",
+			"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "Retry-After",
+		},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}
 }
 
-func (r *router) setGroup() {
-	r.rg = r.eng.Group("/api/v1")
+// setGroups creates a group per API version. Each version registers its own
+// handlers and DTOs on top of the shared service layer.
+func (r *router) setGroups() {
+	r.v1 = r.eng.Group("/api/v1")
+	r.v2 = r.eng.Group("/api/v2")
 }
 
 func (r *router) buildSwaggerRoutes() {
-	r.rg.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	r.v1.GET("/docs/*any", middleware.ContentSecurityPolicy(docsPolicy), ginSwagger.WrapHandler(swaggerFiles.Handler))
+}
+
+var (
+	// customerCache is the cache of the customer service built last, whose stats
+	// are published once as the "customer_cache" expvar.
+	customerCache        atomic.Pointer[customer.CachedRepository]
+	publishCustomerCache sync.Once
+)
+
+// NewCustomerService builds the customer service shared by the REST and gRPC APIs:
+// a cached repository whose stats are published as the "customer_cache" expvar,
+// with every change audited and emitted through the outbox in a transaction, and
+// the custom attributes checked against their definitions, along with opts.
+func NewCustomerService(db *sql.DB, opts ...customer.Option) customer.Service {
+	repo := customer.NewCachedRepository(customer.NewRepository(db), cache.NewLRU(customerCacheSize), customerCacheTTL)
+	customerCache.Store(repo)
+	publishCustomerCache.Do(func() {
+		expvar.Publish("customer_cache", expvar.Func(func() any { return customerCache.Load().Stats() }))
+	})
+	opts = append([]customer.Option{
+		customer.WithTransactor(database.NewTransactor(db)),
+		customer.WithAuditLog(audit.NewRepository(db)),
+		customer.WithOutbox(outbox.NewRepository(db)),
+		customer.WithAttributeSchema(attribute.NewService(attribute.NewRepository(db))),
+	}, opts...)
+	return customer.NewService(repo, opts...)
+}
+
+// buildCustomerRoutes maps the customer routes of a version group, served by the
+// version's customer handler and preceded by middlewares, along with their
+// sub-resources.
+func (r *router) buildCustomerRoutes(rg *gin.RouterGroup, customerHandler customerAPI, middlewares ...gin.HandlerFunc) {
+	auditHandler := handler.NewAuditHandler(audit.NewService(audit.NewRepository(r.db)))
+	noteHandler := handler.NewNoteHandler(note.NewService(note.NewRepository(r.db), customer.NewRepository(r.db), note.WithTransactor(database.NewTransactor(r.db))))
+	attachmentHandler := handler.NewAttachmentHandler(attachment.NewService(attachment.NewRepository(r.db), customer.NewRepository(r.db), r.cfg.Attachments), attachment.DefaultMaxSize)
+	timelineHandler := handler.NewTimelineHandler(timeline.NewService(note.NewRepository(r.db), audit.NewRepository(r.db), customer.NewRepository(r.db)))
+	addressHandler := handler.NewAddressHandler(r.related.Addresses)
+	contactHandler := handler.NewContactHandler(r.related.Contacts)
+	statusHandler := handler.NewStatusHandler(r.cfg.Customers)
+	mergeHandler := handler.NewMergeHandler(r.cfg.Customers)
+	hierarchyHandler := handler.NewHierarchyHandler(r.cfg.Customers)
+	tagHandler := handler.NewTagHandler(r.related.Tags)
+	streamHandler := handler.NewStreamHandler(r.cfg.Events)
+	searchHandler := handler.NewSearchHandler(r.cfg.Searcher)
+	writeLimit := middleware.RateLimit(r.limiter, CustomerWriteLimit, middleware.KeyByClient)
+	middlewares = append(middlewares, middleware.RateLimit(r.limiter, CustomerReadLimit, middleware.KeyByClient))
+	customers := rg.Group("/customers", middlewares...)
+	{
+		customers.POST("/", writeLimit, customerHandler.Store)
+		customers.GET("/", customerHandler.GetAll)
+		customers.GET("/stream", streamHandler.Customers)
+		customers.GET("/search", searchHandler.Customers)
+		customers.GET("/tags", tagHandler.Counts)
+		customers.POST("/tags", writeLimit, tagHandler.Bulk)
+		customers.POST("/merge", writeLimit, mergeHandler.Merge)
+		customers.GET("/:id", customerHandler.Get)
+		customers.GET("/:id/history", auditHandler.History)
+		customers.GET("/:id/timeline", timelineHandler.Get)
+		customers.PUT("/:id", writeLimit, customerHandler.Update)
+		customers.DELETE("/:id", writeLimit, customerHandler.Delete)
+		customers.POST("/:id/status", writeLimit, statusHandler.Change)
+		customers.GET("/:id/duplicates", mergeHandler.Duplicates)
+		customers.GET("/:id/children", hierarchyHandler.Children)
+		customers.GET("/:id/ancestors", hierarchyHandler.Ancestors)
+		customers.GET("/:id/tree", hierarchyHandler.Tree)
+		customers.POST("/:id/addresses", writeLimit, addressHandler.Store)
+		customers.GET("/:id/addresses", addressHandler.GetAll)
+		customers.GET("/:id/addresses/:address_id", addressHandler.Get)
+		customers.PUT("/:id/addresses/:address_id", writeLimit, addressHandler.Update)
+		customers.DELETE("/:id/addresses/:address_id", writeLimit, addressHandler.Delete)
+		customers.POST("/:id/contacts", writeLimit, contactHandler.Store)
+		customers.GET("/:id/contacts", contactHandler.GetAll)
+		customers.GET("/:id/contacts/:contact_id", contactHandler.Get)
+		customers.PUT("/:id/contacts/:contact_id", writeLimit, contactHandler.Update)
+		customers.DELETE("/:id/contacts/:contact_id", writeLimit, contactHandler.Delete)
+		customers.GET("/:id/tags", tagHandler.GetAll)
+		customers.POST("/:id/tags", writeLimit, tagHandler.Store)
+		customers.DELETE("/:id/tags/:tag", writeLimit, tagHandler.Delete)
+		customers.POST("/:id/notes", writeLimit, noteHandler.Store)
+		customers.GET("/:id/notes", noteHandler.GetAll)
+		customers.GET("/:id/notes/:note_id", noteHandler.Get)
+		customers.PUT("/:id/notes/:note_id", writeLimit, noteHandler.Update)
+		customers.DELETE("/:id/notes/:note_id", writeLimit, noteHandler.Delete)
+		customers.GET("/:id/notes/:note_id/revisions", noteHandler.Revisions)
+		customers.POST("/:id/attachments", writeLimit, attachmentHandler.Store)
+		customers.GET("/:id/attachments", attachmentHandler.GetAll)
+		customers.GET("/:id/attachments/:attachment_id", attachmentHandler.Download)
+		customers.DELETE("/:id/attachments/:attachment_id", writeLimit, attachmentHandler.Delete)
+	}
+}
+
+func (r *router) buildGraphQLRoutes() {
+	schema, err := graph.NewSchema(r.cfg.Customers)
+	if err != nil {
+		log.Fatalf("error building the GraphQL schema: %s\n", err.Error())
+	}
+
+	graphqlHandler := handler.NewGraphQLHandler(schema, graph.DefaultLimits)
+	gql := r.v1.Group("/graphql", middleware.RateLimit(r.limiter, CustomerReadLimit, middleware.KeyByClient))
+	{
+		gql.POST("", graphqlHandler.Mutations(middleware.RateLimit(r.limiter, CustomerWriteLimit, middleware.KeyByClient)), graphqlHandler.Query)
+		if r.cfg.GraphiQL {
+			gql.GET("", middleware.ContentSecurityPolicy(graphiqlPolicy), handler.Playground(r.v1.BasePath()+"/graphql"))
+		}
+	}
+}
+
+func (r *router) buildWebhookRoutes() {
+	handler := handler.NewWebhookHandler(webhook.NewService(webhook.NewRepository(r.db)))
+	webhooks := r.v1.Group("/webhooks", middleware.RateLimit(r.limiter, CustomerWriteLimit, middleware.KeyByClient))
+	{
+		webhooks.POST("/", handler.Store)
+		webhooks.GET("/", handler.GetAll)
+		webhooks.GET("/:id", handler.Get)
+		webhooks.PUT("/:id", handler.Update)
+		webhooks.DELETE("/:id", handler.Delete)
+		webhooks.GET("/:id/deliveries", handler.Deliveries)
+		webhooks.POST("/:id/deliveries/:delivery_id/redeliver", handler.Redeliver)
+	}
 }
 
-func (r *router) buildCustomerRoutes() {
-	repo := customer.NewRepository(r.db)
-	service := customer.NewService(repo)
-	handler := handler.NewCustomerHandler(service)
-	customers := r.rg.Group("/customers")
+func (r *router) buildAttributeRoutes() {
+	handler := handler.NewAttributeHandler(attribute.NewService(attribute.NewRepository(r.db)))
+	attributes := r.v1.Group("/attributes", middleware.RateLimit(r.limiter, CustomerWriteLimit, middleware.KeyByClient))
 	{
-		customers.POST("/", handler.Store)
-		customers.GET("/", handler.GetAll)
-		customers.GET("/:id", handler.Get)
-		customers.PUT("/:id", handler.Update)
-		customers.DELETE("/:id", handler.Delete)
+		attributes.POST("/", handler.Store)
+		attributes.GET("/", handler.GetAll)
+		attributes.GET("/:id", handler.Get)
+		attributes.PUT("/:id", handler.Update)
+		attributes.DELETE("/:id", handler.Delete)
 	}
 }
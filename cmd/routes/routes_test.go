@@ -0,0 +1,65 @@
+package routes
+
+import (
+	"database/sql"
+	"net/http"
+	"testing"
+
+	"github.com/danilosano/web-golang-api/pkg/blob"
+	"github.com/danilosano/web-golang-api/pkg/middleware"
+	"github.com/danilosano/web-golang-api/pkg/ratelimit"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	"github.com/gin-gonic/gin"
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter(t *testing.T) {
+	db, err := sql.Open("mysql", "user:password@tcp(localhost:3306)/web_golang_api")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	t.Run("The customer service can be built more than once.", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			NewCustomerService(db)
+			NewCustomerService(db)
+		})
+	})
+
+	t.Run("The debug variables are not served by the API.", func(t *testing.T) {
+		server := testutil.CreateServer()
+		NewRouter(server, db, Config{Attachments: blob.NewFileSystem(t.TempDir())}).MapRoutes()
+
+		request, response := testutil.MakeRequest(http.MethodGet, "/debug/vars", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Unless the proxy is trusted, clients cannot get a new rate limit bucket by sending another X-Forwarded-For.", func(t *testing.T) {
+		limited := func(cfg Config) *gin.Engine {
+			server := testutil.CreateServer()
+			NewRouter(server, db, cfg).MapRoutes()
+			server.GET("/limited", middleware.RateLimit(ratelimit.NewMemoryStore(), ratelimit.PerMinute(1), middleware.KeyByClient), func(c *gin.Context) {
+				c.Status(http.StatusNoContent)
+			})
+			return server
+		}
+		send := func(server *gin.Engine, forwardedFor string) int {
+			request, response := testutil.MakeRequest(http.MethodGet, "/limited", "")
+			request.RemoteAddr = "192.0.2.1:41000"
+			request.Header.Set("X-Forwarded-For", forwardedFor)
+			server.ServeHTTP(response, request)
+			return response.Code
+		}
+
+		server := limited(Config{Attachments: blob.NewFileSystem(t.TempDir())})
+		assert.Equal(t, http.StatusNoContent, send(server, "203.0.113.1"))
+		assert.Equal(t, http.StatusTooManyRequests, send(server, "203.0.113.2"))
+		assert.Equal(t, http.StatusTooManyRequests, send(server, "203.0.113.3"))
+
+		server = limited(Config{Attachments: blob.NewFileSystem(t.TempDir()), TrustedProxies: []string{"192.0.2.1"}})
+		assert.Equal(t, http.StatusNoContent, send(server, "203.0.113.1"))
+		assert.Equal(t, http.StatusNoContent, send(server, "203.0.113.2"))
+	})
+}
@@ -0,0 +1,178 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	customerv1 "github.com/danilosano/web-golang-api/api/proto/customer/v1"
+	"github.com/danilosano/web-golang-api/internal/attribute"
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/gin-gonic/gin/binding"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var errorInvalidID = errors.New("invalid id provided: id must be a positive non-zero number")
+
+func init() {
+	RegisterError(customer.ErrorCustomerNotFound, codes.NotFound)
+	RegisterError(customer.ErrorCustomerNumberAlreadyExist, codes.AlreadyExists)
+	RegisterError(customer.ErrorCustomerNumberRequired, codes.InvalidArgument)
+	RegisterError(customer.ErrorParentNotFound, codes.FailedPrecondition)
+	RegisterError(customer.ErrorHierarchyCycle, codes.FailedPrecondition)
+	RegisterError(attribute.ErrorInvalidAttribute, codes.InvalidArgument)
+	RegisterError(errorInvalidID, codes.InvalidArgument)
+}
+
+// CustomerServer implements customerv1.CustomerServiceServer on top of customer.Service.
+type CustomerServer struct {
+	customerv1.UnimplementedCustomerServiceServer
+	service customer.Service
+}
+
+func NewCustomerServer(s customer.Service) *CustomerServer {
+	return &CustomerServer{
+		service: s,
+	}
+}
+
+func (s *CustomerServer) Save(ctx context.Context, req *customerv1.SaveCustomerRequest) (*customerv1.Customer, error) {
+	input := dto.CreateCustomerRequest{
+		CustomerNumber: optionalInt(req.CustomerNumber),
+		FirstName:      req.GetFirstName(),
+		LastName:       req.GetLastName(),
+		Attributes:     attributes(req.GetAttributes()),
+		ParentID:       optionalInt(req.ParentId),
+	}
+	if err := binding.Validator.ValidateStruct(&input); err != nil {
+		return nil, err
+	}
+
+	result, err := s.service.Save(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return toCustomer(result), nil
+}
+
+func (s *CustomerServer) Get(ctx context.Context, req *customerv1.GetCustomerRequest) (*customerv1.Customer, error) {
+	if req.GetId() <= 0 {
+		return nil, errorInvalidID
+	}
+
+	result, err := s.service.Get(ctx, int(req.GetId()))
+	if err != nil {
+		return nil, err
+	}
+	return toCustomer(result), nil
+}
+
+func (s *CustomerServer) GetAll(ctx context.Context, _ *customerv1.GetAllCustomersRequest) (*customerv1.GetAllCustomersResponse, error) {
+	results, err := s.service.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &customerv1.GetAllCustomersResponse{Customers: make([]*customerv1.Customer, 0, len(results))}
+	for _, r := range results {
+		resp.Customers = append(resp.Customers, toCustomer(r))
+	}
+	return resp, nil
+}
+
+func (s *CustomerServer) Update(ctx context.Context, req *customerv1.UpdateCustomerRequest) (*customerv1.Customer, error) {
+	if req.GetId() <= 0 {
+		return nil, errorInvalidID
+	}
+
+	input := dto.UpdateCustomerRequest{
+		CustomerNumber: optionalInt(req.CustomerNumber),
+		FirstName:      req.GetFirstName(),
+		LastName:       req.GetLastName(),
+		Attributes:     attributes(req.GetAttributes()),
+		ParentID:       optionalInt(req.ParentId),
+	}
+	if err := binding.Validator.ValidateStruct(&input); err != nil {
+		return nil, err
+	}
+
+	result, err := s.service.Update(ctx, input, int(req.GetId()))
+	if err != nil {
+		return nil, err
+	}
+	return toCustomer(result), nil
+}
+
+func (s *CustomerServer) Delete(ctx context.Context, req *customerv1.DeleteCustomerRequest) (*emptypb.Empty, error) {
+	if req.GetId() <= 0 {
+		return nil, errorInvalidID
+	}
+
+	if err := s.service.Delete(ctx, int(req.GetId())); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// List sends the customers one message at a time, stopping when the client goes away.
+func (s *CustomerServer) List(_ *customerv1.ListCustomersRequest, stream customerv1.CustomerService_ListServer) error {
+	ctx := stream.Context()
+	results, err := s.service.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range results {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := stream.Send(toCustomer(r)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toCustomer(r dto.ResultCustomerRequest) *customerv1.Customer {
+	c := &customerv1.Customer{
+		Id:        int64(r.ID),
+		FirstName: r.FirstName,
+		LastName:  r.LastName,
+		Status:    r.Status,
+		CreatedAt: timestamppb.New(r.CreatedAt),
+	}
+	if r.CustomerNumber != nil {
+		c.CustomerNumber = int64(*r.CustomerNumber)
+	}
+	if r.UpdatedAt != nil {
+		c.UpdatedAt = timestamppb.New(*r.UpdatedAt)
+	}
+	if r.ParentID != nil {
+		parentID := int64(*r.ParentID)
+		c.ParentId = &parentID
+	}
+	if len(r.Attributes) > 0 {
+		// Attributes are decoded from JSON, which structpb always represents.
+		c.Attributes, _ = structpb.NewStruct(r.Attributes)
+	}
+	return c
+}
+
+func attributes(s *structpb.Struct) domain.Attributes {
+	if len(s.GetFields()) == 0 {
+		return nil
+	}
+	return s.AsMap()
+}
+
+func optionalInt(v *int64) *int {
+	if v == nil {
+		return nil
+	}
+	i := int(*v)
+	return &i
+}
@@ -0,0 +1,225 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	customerv1 "github.com/danilosano/web-golang-api/api/proto/customer/v1"
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/reqctx"
+	mocks "github.com/danilosano/web-golang-api/pkg/tests/customers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+var (
+	customerNumber = 2
+	resultCustomer = dto.ResultCustomerRequest{
+		ID:             1,
+		CustomerNumber: &customerNumber,
+		FirstName:      "Danilo",
+		LastName:       "Sano",
+		CreatedAt:      time.Date(2021, 10, 10, 0, 0, 0, 0, time.UTC),
+	}
+)
+
+func initClient(t *testing.T, opts ...grpc.ServerOption) (customerv1.CustomerServiceClient, *mocks.CustomersServiceMock) {
+	t.Helper()
+	mockService := new(mocks.CustomersServiceMock)
+	lis := bufconn.Listen(1 << 20)
+	srv := NewServer(mockService, opts...)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return customerv1.NewCustomerServiceClient(conn), mockService
+}
+
+func TestSave(t *testing.T) {
+	t.Run("When the customer is created, it is returned with the caller in the service context.", func(t *testing.T) {
+		client, mockService := initClient(t)
+		input := dto.CreateCustomerRequest{CustomerNumber: &customerNumber, FirstName: "Danilo", LastName: "Sano"}
+		mockService.On("Save", mock.MatchedBy(func(ctx context.Context) bool {
+			return reqctx.UserID(ctx) == "agent-7" && reqctx.RequestID(ctx) != ""
+		}), input).Return(resultCustomer, nil)
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), MetadataUserID, "agent-7")
+		number := int64(customerNumber)
+		var header metadata.MD
+		got, err := client.Save(ctx, &customerv1.SaveCustomerRequest{CustomerNumber: &number, FirstName: "Danilo", LastName: "Sano"}, grpc.Header(&header))
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), got.GetId())
+		assert.Equal(t, int64(2), got.GetCustomerNumber())
+		assert.Equal(t, resultCustomer.CreatedAt, got.GetCreatedAt().AsTime())
+		assert.Nil(t, got.GetUpdatedAt())
+		assert.NotEmpty(t, header.Get(MetadataRequestID))
+	})
+
+	t.Run("When several fields are invalid, InvalidArgument is returned listing all of them.", func(t *testing.T) {
+		client, mockService := initClient(t)
+
+		number := int64(-1)
+		_, err := client.Save(context.Background(), &customerv1.SaveCustomerRequest{CustomerNumber: &number, LastName: "Sano"})
+
+		st := status.Convert(err)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		require.Len(t, st.Details(), 1)
+		br := st.Details()[0].(*errdetails.BadRequest)
+		fields := map[string]string{}
+		for _, v := range br.GetFieldViolations() {
+			fields[v.GetField()] = v.GetDescription()
+		}
+		assert.Equal(t, map[string]string{"customer_number": "must be greater than 0", "first_name": "is required"}, fields)
+		mockService.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("When the user ID is too long to be recorded, InvalidArgument is returned.", func(t *testing.T) {
+		client, mockService := initClient(t)
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), MetadataUserID, strings.Repeat("a", 101))
+		number := int64(customerNumber)
+		_, err := client.Save(ctx, &customerv1.SaveCustomerRequest{CustomerNumber: &number, FirstName: "Danilo", LastName: "Sano"})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		mockService.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("If the customer_number already exists, AlreadyExists is returned.", func(t *testing.T) {
+		client, mockService := initClient(t)
+		mockService.On("Save", mock.Anything, mock.Anything).Return(nil, customer.ErrorCustomerNumberAlreadyExist)
+
+		number := int64(customerNumber)
+		_, err := client.Save(context.Background(), &customerv1.SaveCustomerRequest{CustomerNumber: &number, FirstName: "Danilo", LastName: "Sano"})
+
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	t.Run("The attributes and the parent are kept through an update.", func(t *testing.T) {
+		client, mockService := initClient(t)
+		parentID := 5
+		input := dto.UpdateCustomerRequest{
+			CustomerNumber: &customerNumber,
+			FirstName:      "Danilo",
+			LastName:       "Sano",
+			Attributes:     domain.Attributes{"tier": "gold", "seats": float64(12)},
+			ParentID:       &parentID,
+		}
+		updated := resultCustomer
+		updated.Attributes = input.Attributes
+		updated.ParentID = &parentID
+		mockService.On("Update", mock.Anything, input, 1).Return(updated, nil)
+
+		attributes, err := structpb.NewStruct(map[string]any{"tier": "gold", "seats": 12})
+		require.NoError(t, err)
+		number, parent := int64(customerNumber), int64(parentID)
+		got, err := client.Update(context.Background(), &customerv1.UpdateCustomerRequest{
+			Id: 1, CustomerNumber: &number, FirstName: "Danilo", LastName: "Sano", Attributes: attributes, ParentId: &parent,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"tier": "gold", "seats": float64(12)}, got.GetAttributes().AsMap())
+		assert.Equal(t, int64(5), got.GetParentId())
+	})
+
+	t.Run("When the parent would become a descendant, FailedPrecondition is returned.", func(t *testing.T) {
+		client, mockService := initClient(t)
+		mockService.On("Update", mock.Anything, mock.Anything, 1).Return(nil, customer.ErrorHierarchyCycle)
+
+		number, parent := int64(customerNumber), int64(3)
+		_, err := client.Update(context.Background(), &customerv1.UpdateCustomerRequest{Id: 1, CustomerNumber: &number, FirstName: "Danilo", LastName: "Sano", ParentId: &parent})
+
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+}
+
+func TestGet(t *testing.T) {
+	t.Run("When the customer does not exist, NotFound is returned.", func(t *testing.T) {
+		client, mockService := initClient(t)
+		mockService.On("Get", mock.Anything, 9).Return(nil, customer.ErrorCustomerNotFound)
+
+		_, err := client.Get(context.Background(), &customerv1.GetCustomerRequest{Id: 9})
+
+		st := status.Convert(err)
+		assert.Equal(t, codes.NotFound, st.Code())
+		assert.Equal(t, customer.ErrorCustomerNotFound.Error(), st.Message())
+	})
+
+	t.Run("When the ID is not positive, InvalidArgument is returned.", func(t *testing.T) {
+		client, mockService := initClient(t)
+
+		_, err := client.Get(context.Background(), &customerv1.GetCustomerRequest{Id: 0})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		mockService.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+	})
+
+	t.Run("When an unexpected error occurs, Internal is returned without its details.", func(t *testing.T) {
+		client, mockService := initClient(t)
+		mockService.On("Get", mock.Anything, 1).Return(nil, errors.New("connection refused"))
+
+		_, err := client.Get(context.Background(), &customerv1.GetCustomerRequest{Id: 1})
+
+		st := status.Convert(err)
+		assert.Equal(t, codes.Internal, st.Code())
+		assert.Equal(t, "internal server error", st.Message())
+	})
+}
+
+func TestDelete(t *testing.T) {
+	t.Run("When the customer is deleted, an empty response is returned.", func(t *testing.T) {
+		client, mockService := initClient(t)
+		mockService.On("Delete", mock.Anything, 1).Return(nil)
+
+		_, err := client.Delete(context.Background(), &customerv1.DeleteCustomerRequest{Id: 1})
+
+		assert.NoError(t, err)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestList(t *testing.T) {
+	t.Run("Every customer is streamed as its own message.", func(t *testing.T) {
+		client, mockService := initClient(t)
+		second := resultCustomer
+		second.ID = 2
+		mockService.On("GetAll", mock.Anything).Return([]dto.ResultCustomerRequest{resultCustomer, second}, nil)
+
+		stream, err := client.List(context.Background(), &customerv1.ListCustomersRequest{})
+		require.NoError(t, err)
+
+		var ids []int64
+		for {
+			c, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			require.NoError(t, err)
+			ids = append(ids, c.GetId())
+		}
+		assert.Equal(t, []int64{1, 2}, ids)
+	})
+}
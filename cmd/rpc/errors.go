@@ -0,0 +1,86 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type registeredError struct {
+	target error
+	code   codes.Code
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []registeredError
+)
+
+// RegisterError maps every error matching target (via errors.Is) to the given code.
+func RegisterError(target error, code codes.Code) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry = append(registry, registeredError{target: target, code: code})
+}
+
+// Status converts err into a gRPC status error: validation errors become
+// InvalidArgument with the invalid fields as BadRequest details, registered errors
+// get their code and anything else is reported as Internal without its message.
+func Status(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		return validationStatus(verrs)
+	}
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	for _, r := range registry {
+		if errors.Is(err, r.target) {
+			return status.Error(r.code, err.Error())
+		}
+	}
+	return status.Error(codes.Internal, "internal server error")
+}
+
+func validationStatus(errs validator.ValidationErrors) error {
+	br := &errdetails.BadRequest{}
+	for _, fe := range web.FieldErrors(errs) {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       fe.Field,
+			Description: fe.Message,
+		})
+	}
+
+	st, err := status.New(codes.InvalidArgument, "invalid input").WithDetails(br)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "invalid input")
+	}
+	return st.Err()
+}
+
+func unaryErrors(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	return resp, Status(err)
+}
+
+func streamErrors(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return Status(handler(srv, ss))
+}
@@ -0,0 +1,79 @@
+package rpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/danilosano/web-golang-api/pkg/middleware"
+	"github.com/danilosano/web-golang-api/pkg/reqctx"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// MetadataUserID carries the user recorded as the actor of the changes, like
+	// the X-User-ID header. It is only trustworthy when a gateway in front of the
+	// server authenticates the user and sets it.
+	MetadataUserID = strings.ToLower(middleware.HeaderUserID)
+	// MetadataRequestID carries the request ID, like the X-Request-ID header.
+	MetadataRequestID = strings.ToLower(middleware.HeaderRequestID)
+)
+
+// withIdentity stores the calling user and the request ID from the incoming
+// metadata in ctx, generating a request ID when the client did not send one
+// and echoing it in the response header. Invalid IDs are rejected.
+func withIdentity(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	userID := first(md, MetadataUserID)
+	requestID := first(md, MetadataRequestID)
+	for key, id := range map[string]string{MetadataUserID: userID, MetadataRequestID: requestID} {
+		if !middleware.ValidIdentifier(id) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid %s metadata: at most %d printable ASCII characters", key, middleware.MaxIdentifierLength)
+		}
+	}
+
+	if userID != "" {
+		ctx = reqctx.WithUserID(ctx, userID)
+	}
+
+	if requestID == "" {
+		requestID = uuid.NewString()
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(MetadataRequestID, requestID))
+	return reqctx.WithRequestID(ctx, requestID), nil
+}
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func unaryIdentity(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := withIdentity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func streamIdentity(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := withIdentity(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &identityStream{ServerStream: ss, ctx: ctx})
+}
+
+type identityStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *identityStream) Context() context.Context {
+	return s.ctx
+}
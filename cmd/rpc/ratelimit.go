@@ -0,0 +1,112 @@
+package rpc
+
+import (
+	"context"
+	"log"
+	"math"
+	"net"
+	"strconv"
+	"time"
+
+	customerv1 "github.com/danilosano/web-golang-api/api/proto/customer/v1"
+	"github.com/danilosano/web-golang-api/pkg/middleware"
+	"github.com/danilosano/web-golang-api/pkg/ratelimit"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Limits are the per-client rate limits of the gRPC API, matching those of the
+// REST API: Read applies to every call and Write additionally to the calls
+// changing customers.
+type Limits struct {
+	Store ratelimit.Store
+	Read  ratelimit.Limit
+	Write ratelimit.Limit
+	// TrustIdentity identifies clients by their x-user-id metadata rather than
+	// their address. Only set it when a gateway in front of the server
+	// authenticates the users, as clients could otherwise get a new bucket by
+	// sending another user on each call.
+	TrustIdentity bool
+}
+
+var writeMethods = map[string]bool{
+	customerv1.CustomerService_Save_FullMethodName:   true,
+	customerv1.CustomerService_Update_FullMethodName: true,
+	customerv1.CustomerService_Delete_FullMethodName: true,
+}
+
+// RateLimit returns the server options rejecting the calls over limits with
+// ResourceExhausted, telling the client when to retry. If the store fails the
+// call is let through.
+func RateLimit(limits Limits) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			if err := limits.take(ctx, info.FullMethod); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := limits.take(ss.Context(), info.FullMethod); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	}
+}
+
+func (l Limits) take(ctx context.Context, method string) error {
+	limits := []ratelimit.Limit{l.Read}
+	if writeMethods[method] {
+		limits = append(limits, l.Write)
+	}
+
+	client := l.client(ctx)
+	for _, limit := range limits {
+		res, err := l.Store.Take(ctx, limit.String()+"|"+method+"|"+client, limit)
+		if err != nil {
+			log.Printf("rate limit store error: %s\n", err.Error())
+			return nil
+		}
+
+		if !res.Allowed {
+			return exhausted(res.RetryAfter)
+		}
+	}
+	return nil
+}
+
+// client identifies the caller like middleware.KeyByClient: by user once
+// trusted, by address otherwise.
+func (l Limits) client(ctx context.Context) string {
+	if l.TrustIdentity {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if userID := first(md, MetadataUserID); userID != "" && middleware.ValidIdentifier(userID) {
+			return "user:" + userID
+		}
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+		return "ip:" + host
+	}
+	return "ip:" + p.Addr.String()
+}
+
+func exhausted(retryAfter time.Duration) error {
+	seconds := strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))
+	st := status.New(codes.ResourceExhausted, "rate limit exceeded, retry in "+seconds+" seconds")
+	detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -0,0 +1,68 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	customerv1 "github.com/danilosano/web-golang-api/api/proto/customer/v1"
+	"github.com/danilosano/web-golang-api/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestRateLimit(t *testing.T) {
+	limits := func(trustIdentity bool) Limits {
+		return Limits{Store: ratelimit.NewMemoryStore(), Read: ratelimit.PerMinute(3), Write: ratelimit.PerMinute(1), TrustIdentity: trustIdentity}
+	}
+
+	t.Run("Once the write limit is reached, ResourceExhausted is returned with the retry delay.", func(t *testing.T) {
+		client, mockService := initClient(t, RateLimit(limits(false))...)
+		mockService.On("Delete", mock.Anything, 1).Return(nil)
+
+		_, err := client.Delete(context.Background(), &customerv1.DeleteCustomerRequest{Id: 1})
+		assert.NoError(t, err)
+		_, err = client.Delete(context.Background(), &customerv1.DeleteCustomerRequest{Id: 1})
+
+		st := status.Convert(err)
+		assert.Equal(t, codes.ResourceExhausted, st.Code())
+		assert.Len(t, st.Details(), 1)
+		assert.IsType(t, &errdetails.RetryInfo{}, st.Details()[0])
+		mockService.AssertNumberOfCalls(t, "Delete", 1)
+	})
+
+	t.Run("Reads are only held to the read limit.", func(t *testing.T) {
+		client, mockService := initClient(t, RateLimit(limits(false))...)
+		mockService.On("Get", mock.Anything, 1).Return(resultCustomer, nil)
+
+		for i := 0; i < 3; i++ {
+			_, err := client.Get(context.Background(), &customerv1.GetCustomerRequest{Id: 1})
+			assert.NoError(t, err)
+		}
+		_, err := client.Get(context.Background(), &customerv1.GetCustomerRequest{Id: 1})
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+
+	t.Run("Untrusted clients share the bucket of their address whatever user they send.", func(t *testing.T) {
+		client, mockService := initClient(t, RateLimit(limits(false))...)
+		mockService.On("Delete", mock.Anything, 1).Return(nil)
+
+		_, err := client.Delete(metadata.AppendToOutgoingContext(context.Background(), MetadataUserID, "alice"), &customerv1.DeleteCustomerRequest{Id: 1})
+		assert.NoError(t, err)
+		_, err = client.Delete(metadata.AppendToOutgoingContext(context.Background(), MetadataUserID, "bob"), &customerv1.DeleteCustomerRequest{Id: 1})
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+
+	t.Run("With trusted identities, each user has its own bucket.", func(t *testing.T) {
+		client, mockService := initClient(t, RateLimit(limits(true))...)
+		mockService.On("Delete", mock.Anything, 1).Return(nil)
+
+		_, err := client.Delete(metadata.AppendToOutgoingContext(context.Background(), MetadataUserID, "alice"), &customerv1.DeleteCustomerRequest{Id: 1})
+		assert.NoError(t, err)
+		_, err = client.Delete(metadata.AppendToOutgoingContext(context.Background(), MetadataUserID, "bob"), &customerv1.DeleteCustomerRequest{Id: 1})
+		assert.NoError(t, err)
+	})
+}
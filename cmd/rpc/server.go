@@ -0,0 +1,22 @@
+// Package rpc serves the gRPC API on top of the same service layer as the REST handlers.
+package rpc
+
+import (
+	customerv1 "github.com/danilosano/web-golang-api/api/proto/customer/v1"
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+// NewServer returns a gRPC server exposing the customer service, with reflection
+// enabled so that tools such as grpcurl can discover it.
+func NewServer(customers customer.Service, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(unaryIdentity, unaryErrors),
+		grpc.ChainStreamInterceptor(streamIdentity, streamErrors),
+	)
+	srv := grpc.NewServer(opts...)
+	customerv1.RegisterCustomerServiceServer(srv, NewCustomerServer(customers))
+	reflection.Register(srv)
+	return srv
+}
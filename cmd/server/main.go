@@ -1,17 +1,46 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"expvar"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/joho/godotenv"
 
 	"github.com/danilosano/web-golang-api/cmd/routes"
+	"github.com/danilosano/web-golang-api/cmd/rpc"
 	"github.com/danilosano/web-golang-api/docs"
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/outbox"
+	"github.com/danilosano/web-golang-api/internal/search"
+	"github.com/danilosano/web-golang-api/internal/sequence"
+	"github.com/danilosano/web-golang-api/internal/stream"
+	"github.com/danilosano/web-golang-api/internal/webhook"
+	"github.com/danilosano/web-golang-api/pkg/blob"
+	"github.com/danilosano/web-golang-api/pkg/database"
+	"github.com/danilosano/web-golang-api/pkg/ratelimit"
+)
+
+const (
+	outboxInterval   = 2 * time.Second
+	outboxBatchSize  = 100
+	webhookInterval  = 5 * time.Second
+	webhookBatchSize = 50
+	streamBufferSize = 1000
+	shutdownTimeout  = 30 * time.Second
 )
 
 // @title Golang Web API
@@ -33,8 +62,216 @@ func main() {
 	}
 	docs.SwaggerInfo.Host = os.Getenv("HOST")
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	transactor := database.NewTransactor(db)
+	webhooks := webhook.NewRepository(db)
+	events := stream.NewHub(streamBufferSize)
+	searcher, searchPublisher := customerSearcher(db)
+	publisher := eventPublisher(webhooks, events, searchPublisher)
+	dispatcher := outbox.NewDispatcher(outbox.NewRepository(db), publisher, transactor, outboxInterval, outboxBatchSize)
+	go dispatcher.Run(ctx)
+	go webhook.NewWorker(webhooks, transactor, nil, webhookInterval, webhookBatchSize).Run(ctx)
+
+	trustIdentity := os.Getenv("TRUST_IDENTITY_HEADERS") == "true"
+	customers := routes.NewCustomerService(db, customerNumbering(db)...)
+	grpcStopped := make(chan struct{})
+	go func() {
+		defer close(grpcStopped)
+		serveGRPC(ctx, customers, rpc.Limits{
+			Store:         ratelimit.NewMemoryStore(),
+			Read:          routes.CustomerReadLimit,
+			Write:         routes.CustomerWriteLimit,
+			TrustIdentity: trustIdentity,
+		})
+	}()
+	go serveDebug()
+
 	r := gin.Default()
-	router := routes.NewRouter(r, db)
+	router := routes.NewRouter(r, db, routes.Config{
+		Customers:      customers,
+		Events:         events,
+		Searcher:       searcher,
+		GraphiQL:       os.Getenv("GRAPHIQL_ENABLED") == "true",
+		AllowedOrigins: commaSeparated("CORS_ALLOWED_ORIGINS"),
+		TrustedProxies: commaSeparated("TRUSTED_PROXIES"),
+		Attachments:    attachmentStorage(),
+		TrustIdentity:  trustIdentity,
+	})
 	router.MapRoutes()
-	r.Run()
+	serveHTTP(ctx, r)
+	<-grpcStopped
+}
+
+// serveHTTP serves the REST API on PORT, 8080 by default, until ctx is
+// cancelled. The requests in flight are then given shutdownTimeout to complete.
+func serveHTTP(ctx context.Context, handler http.Handler) {
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+
+	server := &http.Server{Addr: ":" + port, Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("error serving HTTP: %s\n", err.Error())
+		}
+	}()
+
+	<-ctx.Done()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("error shutting down HTTP: %s\n", err.Error())
+	}
+}
+
+// commaSeparated returns the comma-separated values of the environment variable
+// name, such as the origins of CORS_ALLOWED_ORIGINS or the proxies of
+// TRUSTED_PROXIES.
+func commaSeparated(name string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(name), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// customerNumbering returns, when CUSTOMER_NUMBERING is "sequence", the option
+// allocating the numbers of the customers created without one, formatted as
+// described by the CUSTOMER_NUMBER_* variables.
+func customerNumbering(db *sql.DB) []customer.Option {
+	if os.Getenv("CUSTOMER_NUMBERING") != "sequence" {
+		return nil
+	}
+
+	numbering := customer.Numbering{
+		CheckDigit: os.Getenv("CUSTOMER_NUMBER_CHECK_DIGIT") == "true",
+		GapFree:    os.Getenv("CUSTOMER_NUMBER_GAP_FREE") == "true",
+	}
+	var err error
+	if prefix := os.Getenv("CUSTOMER_NUMBER_PREFIX"); prefix != "" {
+		if numbering.Prefix, err = strconv.Atoi(prefix); err != nil {
+			log.Fatalf("error parsing CUSTOMER_NUMBER_PREFIX: %s\n", err.Error())
+		}
+	}
+	if width := os.Getenv("CUSTOMER_NUMBER_WIDTH"); width != "" {
+		if numbering.Width, err = strconv.Atoi(width); err != nil {
+			log.Fatalf("error parsing CUSTOMER_NUMBER_WIDTH: %s\n", err.Error())
+		}
+	}
+	if err := numbering.Validate(); err != nil {
+		log.Fatalf("error configuring the customer numbers: %s\n", err.Error())
+	}
+
+	return []customer.Option{customer.WithNumbering(sequence.NewRepository(db), numbering)}
+}
+
+// attachmentStorage returns the storage selected by ATTACHMENT_STORAGE: the
+// ATTACHMENT_DIR directory by default, or "s3" for the bucket described by the
+// S3_* variables, on AWS or any S3-compatible server.
+func attachmentStorage() blob.Storage {
+	if os.Getenv("ATTACHMENT_STORAGE") != "s3" {
+		dir := os.Getenv("ATTACHMENT_DIR")
+		if dir == "" {
+			dir = "attachments"
+		}
+		return blob.NewFileSystem(dir)
+	}
+
+	return blob.NewS3(blob.S3Config{
+		Endpoint:        os.Getenv("S3_ENDPOINT"),
+		Region:          os.Getenv("S3_REGION"),
+		Bucket:          os.Getenv("S3_BUCKET"),
+		AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+	}, nil)
+}
+
+// serveGRPC serves the gRPC API on GRPC_PORT, 9090 by default, with the same
+// per-client rate limits as the REST API, until ctx is cancelled. The calls in
+// flight are then given shutdownTimeout to complete. Like the X-User-ID header,
+// the x-user-id metadata is recorded as the actor of the changes without being
+// authenticated, so it is only trustworthy behind a gateway setting it.
+func serveGRPC(ctx context.Context, customers customer.Service, limits rpc.Limits) {
+	port := os.Getenv("GRPC_PORT")
+	if port == "" {
+		port = "9090"
+	}
+
+	lis, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		log.Fatalf("error listening for gRPC: %s\n", err.Error())
+	}
+	srv := rpc.NewServer(customers, rpc.RateLimit(limits)...)
+	go func() {
+		if err := srv.Serve(lis); err != nil {
+			log.Fatalf("error serving gRPC: %s\n", err.Error())
+		}
+	}()
+
+	<-ctx.Done()
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(shutdownTimeout):
+		srv.Stop()
+	}
+}
+
+// serveDebug serves the expvar variables, such as the customer cache stats, on
+// DEBUG_ADDR. They are left out of the public API, so DEBUG_ADDR should only be
+// reachable from the internal network, e.g. "localhost:6060". Nothing is served
+// when it is empty.
+func serveDebug() {
+	addr := os.Getenv("DEBUG_ADDR")
+	if addr == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	if err := server.ListenAndServe(); err != nil {
+		log.Fatalf("error serving the debug variables: %s\n", err.Error())
+	}
+}
+
+// customerSearcher returns the searcher selected by SEARCH_BACKEND: the MySQL
+// FULLTEXT index by default, or "memory" for an in-process index loaded at startup
+// and kept current by the returned publisher.
+func customerSearcher(db *sql.DB) (search.Searcher, outbox.Publisher) {
+	if os.Getenv("SEARCH_BACKEND") != "memory" {
+		return search.NewMySQLSearcher(db), nil
+	}
+
+	customers, err := customer.NewRepository(db).GetAllWithContext(context.Background())
+	if err != nil {
+		log.Fatalf("error loading the search index: %s\n", err.Error())
+	}
+
+	index := search.NewMemoryIndex()
+	index.Index(customers...)
+	return index, index
+}
+
+// eventPublisher logs every customer event, schedules its delivery to the webhook
+// subscriptions, pushes it to the stream clients, applies it to the search index
+// when given and, when OUTBOX_WEBHOOK_URL is set, also posts it to that URL.
+func eventPublisher(webhooks webhook.Repository, events *stream.Hub, searchIndex outbox.Publisher) outbox.Publisher {
+	publishers := []outbox.Publisher{outbox.LogPublisher(nil), webhook.NewPublisher(webhooks), events}
+	if searchIndex != nil {
+		publishers = append(publishers, searchIndex)
+	}
+	if url := os.Getenv("OUTBOX_WEBHOOK_URL"); url != "" {
+		publishers = append(publishers, outbox.WebhookPublisher(url, nil))
+	}
+	return outbox.MultiPublisher(publishers...)
 }
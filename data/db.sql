@@ -6,9 +6,20 @@ CREATE TABLE IF NOT EXISTS customers(
     customer_number INT NOT NULL,
     first_name VARCHAR(100) NOT NULL,
     last_name VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'prospect',
+    attributes JSON NULL,
     created_at TIMESTAMP NOT NULL,
     updated_at TIMESTAMP,
-    deleted_at TIMESTAMP
+    deleted_at TIMESTAMP,
+    merged_into INT NULL,
+    parent_id INT NULL,
+    -- Deleted customers keep their number, which can be given to a new one.
+    live_customer_number INT AS (IF(deleted_at IS NULL, customer_number, NULL)),
+    UNIQUE INDEX uq_customers_live_number (live_customer_number),
+    INDEX idx_customers_status (status, deleted_at),
+    INDEX idx_customers_merged_into (merged_into),
+    INDEX idx_customers_parent (parent_id),
+    FULLTEXT INDEX ft_customers_name (first_name, last_name) WITH PARSER ngram
 );
 
 CREATE TABLE IF NOT EXISTS users (
@@ -20,6 +31,178 @@ CREATE TABLE IF NOT EXISTS users (
     deleted_at TIMESTAMP
 );
 
+CREATE TABLE IF NOT EXISTS audit_log(
+    audit_id INT NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    actor VARCHAR(100) NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    customer_id INT NOT NULL,
+    before_data JSON,
+    after_data JSON,
+    request_id VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    INDEX idx_audit_log_customer (customer_id, created_at)
+);
+
+CREATE TABLE IF NOT EXISTS outbox(
+    outbox_id INT NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    event_id VARCHAR(36) NOT NULL UNIQUE,
+    event_type VARCHAR(50) NOT NULL,
+    customer_id INT NOT NULL,
+    payload JSON NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    dispatched_at TIMESTAMP NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NULL,
+    locked_until TIMESTAMP NULL,
+    dead_lettered_at TIMESTAMP NULL,
+    INDEX idx_outbox_pending (dispatched_at, dead_lettered_at, occurred_at)
+);
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions(
+    subscription_id INT NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    url VARCHAR(255) NOT NULL,
+    event_types VARCHAR(255) NOT NULL,
+    secret VARCHAR(100) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries(
+    delivery_id INT NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    subscription_id INT NOT NULL,
+    event_id VARCHAR(36) NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSON NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    response_status INT NULL,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NULL,
+    delivered_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL,
+    redelivery INT NOT NULL DEFAULT 0,
+    UNIQUE INDEX uq_webhook_deliveries_event (subscription_id, event_id, redelivery),
+    INDEX idx_webhook_deliveries_due (status, next_attempt_at),
+    INDEX idx_webhook_deliveries_subscription (subscription_id, created_at),
+    FOREIGN KEY (subscription_id) REFERENCES webhook_subscriptions(subscription_id)
+);
+
+CREATE TABLE IF NOT EXISTS addresses(
+    address_id INT NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    customer_id INT NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    line1 VARCHAR(255) NOT NULL,
+    line2 VARCHAR(255) NOT NULL DEFAULT '',
+    city VARCHAR(100) NOT NULL,
+    region VARCHAR(100) NOT NULL DEFAULT '',
+    postal_code VARCHAR(20) NOT NULL,
+    country CHAR(2) NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL,
+    INDEX idx_addresses_customer (customer_id, deleted_at)
+);
+
+CREATE TABLE IF NOT EXISTS contacts(
+    contact_id INT NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    customer_id INT NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    label VARCHAR(20) NOT NULL DEFAULT '',
+    value VARCHAR(255) NOT NULL,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    verified BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL,
+    primary_email VARCHAR(255) AS (IF(type = 'email' AND is_primary AND deleted_at IS NULL, value, NULL)) STORED,
+    UNIQUE INDEX uq_contacts_primary_email (primary_email),
+    INDEX idx_contacts_customer (customer_id, deleted_at),
+    INDEX idx_contacts_value (type, value)
+);
+
+CREATE TABLE IF NOT EXISTS customer_status_changes(
+    status_change_id INT NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    customer_id INT NOT NULL,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    reason VARCHAR(255) NOT NULL,
+    actor VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    INDEX idx_customer_status_changes_customer (customer_id, created_at)
+);
+
+CREATE TABLE IF NOT EXISTS tags(
+    tag_id INT NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    name VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    UNIQUE INDEX uq_tags_name (name)
+);
+
+CREATE TABLE IF NOT EXISTS customer_tags(
+    customer_id INT NOT NULL,
+    tag_id INT NOT NULL,
+    tagged_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (customer_id, tag_id),
+    INDEX idx_customer_tags_tag (tag_id, customer_id),
+    FOREIGN KEY (tag_id) REFERENCES tags(tag_id)
+);
+
+CREATE TABLE IF NOT EXISTS attribute_definitions(
+    attribute_id INT NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    name VARCHAR(50) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    options JSON NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NULL,
+    UNIQUE INDEX uq_attribute_definitions_name (name)
+);
+
+CREATE TABLE IF NOT EXISTS notes(
+    note_id INT NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    customer_id INT NOT NULL,
+    author VARCHAR(100) NOT NULL,
+    body TEXT NOT NULL,
+    pinned BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL,
+    INDEX idx_notes_customer (customer_id, created_at)
+);
+
+CREATE TABLE IF NOT EXISTS note_revisions(
+    revision_id INT NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    note_id INT NOT NULL,
+    body TEXT NOT NULL,
+    editor VARCHAR(100) NOT NULL,
+    edited_at TIMESTAMP NOT NULL,
+    INDEX idx_note_revisions_note (note_id),
+    FOREIGN KEY (note_id) REFERENCES notes(note_id)
+);
+
+CREATE TABLE IF NOT EXISTS attachments(
+    attachment_id INT NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    customer_id INT NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    uploaded_by VARCHAR(100) NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    deleted_at TIMESTAMP NULL,
+    INDEX idx_attachments_customer (customer_id, created_at)
+);
+
+CREATE TABLE IF NOT EXISTS sequences(
+    name VARCHAR(50) NOT NULL PRIMARY KEY,
+    value BIGINT NOT NULL
+);
+
 INSERT INTO `web_golang_api`.`customers` (`customer_number`, `first_name`, `last_name`, `created_at`) VALUES (1, 'Danilo', 'Sano', '2024-05-29 00:00:00');
 INSERT INTO `web_golang_api`.`customers` (`customer_number`, `first_name`, `last_name`, `created_at`) VALUES (2, 'Cliente', 'Teste', '2024-05-04 00:00:00');
 
@@ -0,0 +1,151 @@
+package address
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/pkg/database"
+)
+
+type Repository interface {
+	GetByCustomerIDWithContext(ctx context.Context, customerID int) ([]domain.Address, error)
+	GetByCustomerIDsWithContext(ctx context.Context, customerIDs []int) ([]domain.Address, error)
+	GetWithContext(ctx context.Context, customerID, id int) (domain.Address, error)
+	SaveWithContext(ctx context.Context, a domain.Address) (int, error)
+	UpdateWithContext(ctx context.Context, a domain.Address) error
+	DeleteWithContext(ctx context.Context, customerID, id int) error
+	ClearDefaultWithContext(ctx context.Context, customerID int, addressType string) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+const addressColumns = "address_id, customer_id, type, line1, line2, city, region, postal_code, country, is_default, created_at, updated_at"
+
+func (r *repository) GetByCustomerIDWithContext(ctx context.Context, customerID int) ([]domain.Address, error) {
+	query := "SELECT " + addressColumns + " FROM addresses WHERE deleted_at IS NULL and customer_id=? ORDER BY address_id;"
+	return r.queryAddresses(ctx, query, customerID)
+}
+
+// GetByCustomerIDsWithContext returns the addresses of several customers at once,
+// ordered by customer.
+func (r *repository) GetByCustomerIDsWithContext(ctx context.Context, customerIDs []int) ([]domain.Address, error) {
+	if len(customerIDs) == 0 {
+		return nil, nil
+	}
+
+	args := make([]any, len(customerIDs))
+	for i, id := range customerIDs {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(customerIDs)), ",")
+	query := "SELECT " + addressColumns + " FROM addresses WHERE deleted_at IS NULL and customer_id IN (" + placeholders + ") ORDER BY customer_id, address_id;"
+	return r.queryAddresses(ctx, query, args...)
+}
+
+func (r *repository) GetWithContext(ctx context.Context, customerID, id int) (domain.Address, error) {
+	query := "SELECT " + addressColumns + " FROM addresses WHERE deleted_at IS NULL and customer_id=? and address_id=?;"
+	row := database.Conn(ctx, r.db).QueryRowContext(ctx, query, customerID, id)
+	a, err := scanAddress(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Address{}, ErrorAddressNotFound
+		}
+		return domain.Address{}, err
+	}
+
+	return a, nil
+}
+
+func (r *repository) SaveWithContext(ctx context.Context, a domain.Address) (int, error) {
+	query := "INSERT INTO addresses (customer_id, type, line1, line2, city, region, postal_code, country, is_default, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);"
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, query, a.CustomerID, a.Type, a.Line1, a.Line2, a.City, a.Region, a.PostalCode, a.Country, a.Default, a.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+func (r *repository) UpdateWithContext(ctx context.Context, a domain.Address) error {
+	query := "UPDATE addresses SET type=?, line1=?, line2=?, city=?, region=?, postal_code=?, country=?, is_default=?, updated_at=? WHERE customer_id=? and address_id=? and deleted_at IS NULL;"
+	_, err := database.Conn(ctx, r.db).ExecContext(ctx, query, a.Type, a.Line1, a.Line2, a.City, a.Region, a.PostalCode, a.Country, a.Default, a.UpdatedAt, a.CustomerID, a.ID)
+	return err
+}
+
+func (r *repository) DeleteWithContext(ctx context.Context, customerID, id int) error {
+	query := "UPDATE addresses SET deleted_at=? WHERE customer_id=? and address_id=? and deleted_at IS NULL;"
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, query, time.Now(), customerID, id)
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affect < 1 {
+		return ErrorAddressNotFound
+	}
+
+	return nil
+}
+
+// ClearDefaultWithContext unsets the default flag of every address of the given
+// type of a customer.
+func (r *repository) ClearDefaultWithContext(ctx context.Context, customerID int, addressType string) error {
+	query := "UPDATE addresses SET is_default=FALSE WHERE customer_id=? and type=? and is_default=TRUE and deleted_at IS NULL;"
+	_, err := database.Conn(ctx, r.db).ExecContext(ctx, query, customerID, addressType)
+	return err
+}
+
+func (r *repository) queryAddresses(ctx context.Context, query string, args ...any) ([]domain.Address, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var addresses []domain.Address
+
+	for rows.Next() {
+		a, err := scanAddress(rows)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, a)
+	}
+
+	return addresses, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAddress(row scanner) (domain.Address, error) {
+	a := domain.Address{}
+	err := row.Scan(&a.ID, &a.CustomerID, &a.Type, &a.Line1, &a.Line2, &a.City, &a.Region, &a.PostalCode, &a.Country,
+		&a.Default, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		return domain.Address{}, err
+	}
+	return a, nil
+}
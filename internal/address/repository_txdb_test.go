@@ -0,0 +1,93 @@
+package address
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuite_AddressRepository(t *testing.T) {
+	db, err := testutil.InitTxdbDatabase(t)
+	assert.NoError(t, err)
+	repository := NewRepository(db)
+	customers := customer.NewRepository(db)
+
+	testSaveAndClearDefaultWithContext(t, repository, customers)
+	testDeletedWithCustomer(t, repository, customers)
+
+	db.Close()
+}
+
+func saveCustomer(ctx context.Context, t *testing.T, customers customer.Repository, number int) int {
+	t.Helper()
+	id, err := customers.SaveWithContext(ctx, domain.Customer{
+		CustomerNumber: number,
+		FirstName:      "Danilo",
+		LastName:       "Sano",
+		CreatedAt:      time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	assert.NoError(t, err)
+	return id
+}
+
+func testSaveAndClearDefaultWithContext(t *testing.T, repository Repository, customers customer.Repository) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	customerID := saveCustomer(ctx, t, customers, 999991)
+	a := domain.Address{
+		CustomerID: customerID,
+		Type:       domain.AddressTypeShipping,
+		Line1:      "Avenida Paulista, 1000",
+		City:       "São Paulo",
+		PostalCode: "01310-100",
+		Country:    "BR",
+		Default:    true,
+		CreatedAt:  time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	id, err := repository.SaveWithContext(ctx, a)
+	assert.NoError(t, err)
+	a.ID = id
+
+	result, err := repository.GetWithContext(ctx, customerID, id)
+	assert.NoError(t, err)
+	assert.Equal(t, a, result)
+
+	assert.NoError(t, repository.ClearDefaultWithContext(ctx, customerID, domain.AddressTypeShipping))
+	result, err = repository.GetWithContext(ctx, customerID, id)
+	assert.NoError(t, err)
+	assert.False(t, result.Default)
+
+	_, err = repository.GetWithContext(ctx, customerID+1, id)
+	assert.Equal(t, ErrorAddressNotFound, err)
+}
+
+func testDeletedWithCustomer(t *testing.T, repository Repository, customers customer.Repository) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	customerID := saveCustomer(ctx, t, customers, 999992)
+	_, err := repository.SaveWithContext(ctx, domain.Address{
+		CustomerID: customerID,
+		Type:       domain.AddressTypeBilling,
+		Line1:      "Rua Augusta, 10",
+		City:       "São Paulo",
+		PostalCode: "01305-000",
+		Country:    "BR",
+		CreatedAt:  time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, customers.DeleteWithContext(ctx, customerID))
+
+	result, err := repository.GetByCustomerIDsWithContext(ctx, []int{customerID})
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+}
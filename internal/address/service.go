@@ -0,0 +1,183 @@
+package address
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/database"
+)
+
+var (
+	ErrorAddressNotFound = errors.New("address not found")
+)
+
+type Service interface {
+	Save(ctx context.Context, input dto.AddressRequest, customerID int) (domain.Address, error)
+	GetAll(ctx context.Context, customerID int) ([]domain.Address, error)
+	Get(ctx context.Context, customerID, id int) (domain.Address, error)
+	Update(ctx context.Context, input dto.AddressRequest, customerID, id int) (domain.Address, error)
+	Delete(ctx context.Context, customerID, id int) error
+	GetByCustomerIDs(ctx context.Context, customerIDs []int) (map[int][]domain.Address, error)
+}
+
+type service struct {
+	repository Repository
+	customers  customer.Repository
+	transactor database.Transactor
+}
+
+type Option func(*service)
+
+// WithTransactor moves the default flag of a customer's addresses in a transaction.
+func WithTransactor(t database.Transactor) Option {
+	return func(s *service) {
+		s.transactor = t
+	}
+}
+
+// NewService manages the addresses of the customers found in customers. The
+// addresses of a deleted customer are deleted along with it by the customer
+// repository.
+func NewService(r Repository, customers customer.Repository, opts ...Option) Service {
+	s := &service{
+		repository: r,
+		customers:  customers,
+		transactor: database.NoopTransactor(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *service) Save(ctx context.Context, input dto.AddressRequest, customerID int) (domain.Address, error) {
+	if customerExist := s.customers.ExistsByIDWithContext(ctx, customerID); !customerExist {
+		return domain.Address{}, customer.ErrorCustomerNotFound
+	}
+
+	a := newAddress(input)
+	a.CustomerID = customerID
+	a.CreatedAt = time.Now().Truncate(time.Second)
+
+	var result domain.Address
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.clearDefault(ctx, a); err != nil {
+			return err
+		}
+
+		id, err := s.repository.SaveWithContext(ctx, a)
+		if err != nil {
+			return err
+		}
+
+		result, err = s.repository.GetWithContext(ctx, customerID, id)
+		return err
+	})
+	if err != nil {
+		return domain.Address{}, err
+	}
+
+	return result, nil
+}
+
+func (s *service) GetAll(ctx context.Context, customerID int) ([]domain.Address, error) {
+	if customerExist := s.customers.ExistsByIDWithContext(ctx, customerID); !customerExist {
+		return nil, customer.ErrorCustomerNotFound
+	}
+
+	return s.repository.GetByCustomerIDWithContext(ctx, customerID)
+}
+
+func (s *service) Get(ctx context.Context, customerID, id int) (domain.Address, error) {
+	if customerExist := s.customers.ExistsByIDWithContext(ctx, customerID); !customerExist {
+		return domain.Address{}, customer.ErrorCustomerNotFound
+	}
+
+	return s.repository.GetWithContext(ctx, customerID, id)
+}
+
+func (s *service) Update(ctx context.Context, input dto.AddressRequest, customerID, id int) (domain.Address, error) {
+	if customerExist := s.customers.ExistsByIDWithContext(ctx, customerID); !customerExist {
+		return domain.Address{}, customer.ErrorCustomerNotFound
+	}
+
+	var result domain.Address
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		current, err := s.repository.GetWithContext(ctx, customerID, id)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().Truncate(time.Second)
+		a := newAddress(input)
+		a.ID = current.ID
+		a.CustomerID = customerID
+		a.CreatedAt = current.CreatedAt
+		a.UpdatedAt = &now
+
+		if err := s.clearDefault(ctx, a); err != nil {
+			return err
+		}
+
+		if err := s.repository.UpdateWithContext(ctx, a); err != nil {
+			return err
+		}
+
+		result, err = s.repository.GetWithContext(ctx, customerID, id)
+		return err
+	})
+	if err != nil {
+		return domain.Address{}, err
+	}
+
+	return result, nil
+}
+
+func (s *service) Delete(ctx context.Context, customerID, id int) error {
+	if customerExist := s.customers.ExistsByIDWithContext(ctx, customerID); !customerExist {
+		return customer.ErrorCustomerNotFound
+	}
+
+	return s.repository.DeleteWithContext(ctx, customerID, id)
+}
+
+// GetByCustomerIDs returns the addresses of several customers, keyed by customer
+// ID. Customers without addresses are left out.
+func (s *service) GetByCustomerIDs(ctx context.Context, customerIDs []int) (map[int][]domain.Address, error) {
+	addresses, err := s.repository.GetByCustomerIDsWithContext(ctx, customerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byCustomer := make(map[int][]domain.Address)
+	for _, a := range addresses {
+		byCustomer[a.CustomerID] = append(byCustomer[a.CustomerID], a)
+	}
+	return byCustomer, nil
+}
+
+// clearDefault unsets the previous default address of the type of a when a
+// becomes the default one.
+func (s *service) clearDefault(ctx context.Context, a domain.Address) error {
+	if !a.Default {
+		return nil
+	}
+	return s.repository.ClearDefaultWithContext(ctx, a.CustomerID, a.Type)
+}
+
+func newAddress(input dto.AddressRequest) domain.Address {
+	return domain.Address{
+		Type:       input.Type,
+		Line1:      input.Line1,
+		Line2:      input.Line2,
+		City:       input.City,
+		Region:     input.Region,
+		PostalCode: input.PostalCode,
+		Country:    input.Country,
+		Default:    input.Default,
+	}
+}
@@ -0,0 +1,131 @@
+package address
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	mocks "github.com/danilosano/web-golang-api/pkg/tests/addresses"
+	customerMocks "github.com/danilosano/web-golang-api/pkg/tests/customers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func createService(t *testing.T) (Service, *mocks.AddressRepositoryMock, *customerMocks.CustomersRepositoryMock, context.Context) {
+	t.Helper()
+	repoMock := new(mocks.AddressRepositoryMock)
+	customersMock := new(customerMocks.CustomersRepositoryMock)
+	return NewService(repoMock, customersMock), repoMock, customersMock, context.Background()
+}
+
+var (
+	input = dto.AddressRequest{
+		Type:       domain.AddressTypeBilling,
+		Line1:      "Avenida Paulista, 1000",
+		City:       "São Paulo",
+		PostalCode: "01310-100",
+		Country:    "BR",
+		Default:    true,
+	}
+
+	mockedAddress = domain.Address{
+		ID:         3,
+		CustomerID: 1,
+		Type:       domain.AddressTypeBilling,
+		Line1:      "Avenida Paulista, 1000",
+		City:       "São Paulo",
+		PostalCode: "01310-100",
+		Country:    "BR",
+		Default:    true,
+		CreatedAt:  time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+)
+
+func TestSave(t *testing.T) {
+	t.Run("A default address replaces the previous default of its type.", func(t *testing.T) {
+		service, repoMock, customersMock, ctx := createService(t)
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("ClearDefaultWithContext", ctx, 1, domain.AddressTypeBilling).Return(nil)
+		repoMock.On("SaveWithContext", ctx, mock.MatchedBy(func(a domain.Address) bool {
+			return a.CustomerID == 1 && a.Default && !a.CreatedAt.IsZero()
+		})).Return(3, nil)
+		repoMock.On("GetWithContext", ctx, 1, 3).Return(mockedAddress, nil)
+
+		result, err := service.Save(ctx, input, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, mockedAddress, result)
+		repoMock.AssertCalled(t, "ClearDefaultWithContext", ctx, 1, domain.AddressTypeBilling)
+	})
+
+	t.Run("An address that is not the default leaves the others untouched.", func(t *testing.T) {
+		service, repoMock, customersMock, ctx := createService(t)
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("SaveWithContext", ctx, mock.Anything).Return(3, nil)
+		repoMock.On("GetWithContext", ctx, 1, 3).Return(mockedAddress, nil)
+
+		notDefault := input
+		notDefault.Default = false
+		_, err := service.Save(ctx, notDefault, 1)
+		assert.Nil(t, err)
+		repoMock.AssertNotCalled(t, "ClearDefaultWithContext", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("When the customer does not exist, return the not found error.", func(t *testing.T) {
+		service, _, customersMock, ctx := createService(t)
+		customersMock.On("ExistsByIDWithContext", ctx, 9).Return(false)
+
+		_, err := service.Save(ctx, input, 9)
+		assert.Equal(t, customer.ErrorCustomerNotFound, err)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	t.Run("The address keeps its creation date and gets an update date.", func(t *testing.T) {
+		service, repoMock, customersMock, ctx := createService(t)
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("GetWithContext", ctx, 1, 3).Return(mockedAddress, nil)
+		repoMock.On("ClearDefaultWithContext", ctx, 1, domain.AddressTypeBilling).Return(nil)
+		repoMock.On("UpdateWithContext", ctx, mock.MatchedBy(func(a domain.Address) bool {
+			return a.ID == 3 && a.CreatedAt.Equal(mockedAddress.CreatedAt) && a.UpdatedAt != nil
+		})).Return(nil)
+
+		_, err := service.Update(ctx, input, 1, 3)
+		assert.Nil(t, err)
+		repoMock.AssertNumberOfCalls(t, "UpdateWithContext", 1)
+	})
+
+	t.Run("When the address does not exist, return the not found error.", func(t *testing.T) {
+		service, repoMock, customersMock, ctx := createService(t)
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("GetWithContext", ctx, 1, 4).Return(nil, ErrorAddressNotFound)
+
+		_, err := service.Update(ctx, input, 1, 4)
+		assert.Equal(t, ErrorAddressNotFound, err)
+		repoMock.AssertNotCalled(t, "UpdateWithContext", mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetByCustomerIDs(t *testing.T) {
+	t.Run("The addresses are grouped by customer.", func(t *testing.T) {
+		service, repoMock, _, ctx := createService(t)
+		other := mockedAddress
+		other.ID, other.CustomerID = 4, 2
+		repoMock.On("GetByCustomerIDsWithContext", ctx, []int{1, 2, 5}).Return([]domain.Address{mockedAddress, other}, nil)
+
+		result, err := service.GetByCustomerIDs(ctx, []int{1, 2, 5})
+		assert.Nil(t, err)
+		assert.Equal(t, map[int][]domain.Address{1: {mockedAddress}, 2: {other}}, result)
+	})
+
+	t.Run("When the backend returns an unexpected error, return the error.", func(t *testing.T) {
+		service, repoMock, _, ctx := createService(t)
+		repoMock.On("GetByCustomerIDsWithContext", ctx, []int{1}).Return(nil, errors.New("generic error"))
+
+		_, err := service.GetByCustomerIDs(ctx, []int{1})
+		assert.Equal(t, errors.New("generic error"), err)
+	})
+}
@@ -0,0 +1,110 @@
+package attachment
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/pkg/database"
+)
+
+type Repository interface {
+	GetByCustomerIDWithContext(ctx context.Context, customerID int) ([]domain.Attachment, error)
+	GetWithContext(ctx context.Context, customerID, id int) (domain.Attachment, error)
+	SaveWithContext(ctx context.Context, a domain.Attachment) (int, error)
+	DeleteWithContext(ctx context.Context, customerID, id int) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+const attachmentColumns = "attachment_id, customer_id, file_name, content_type, size, checksum, uploaded_by, storage_key, created_at"
+
+// GetByCustomerIDWithContext returns the attachments of a customer, newest first.
+func (r *repository) GetByCustomerIDWithContext(ctx context.Context, customerID int) ([]domain.Attachment, error) {
+	query := "SELECT " + attachmentColumns + " FROM attachments WHERE deleted_at IS NULL and customer_id=? ORDER BY created_at DESC, attachment_id DESC;"
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []domain.Attachment
+
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, a)
+	}
+
+	return attachments, rows.Err()
+}
+
+func (r *repository) GetWithContext(ctx context.Context, customerID, id int) (domain.Attachment, error) {
+	query := "SELECT " + attachmentColumns + " FROM attachments WHERE deleted_at IS NULL and customer_id=? and attachment_id=?;"
+	row := database.Conn(ctx, r.db).QueryRowContext(ctx, query, customerID, id)
+	a, err := scanAttachment(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Attachment{}, ErrorAttachmentNotFound
+		}
+		return domain.Attachment{}, err
+	}
+
+	return a, nil
+}
+
+func (r *repository) SaveWithContext(ctx context.Context, a domain.Attachment) (int, error) {
+	query := "INSERT INTO attachments (customer_id, file_name, content_type, size, checksum, uploaded_by, storage_key, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?);"
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, query, a.CustomerID, a.FileName, a.ContentType, a.Size, a.Checksum, a.UploadedBy, a.StorageKey, a.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+func (r *repository) DeleteWithContext(ctx context.Context, customerID, id int) error {
+	query := "UPDATE attachments SET deleted_at=? WHERE customer_id=? and attachment_id=? and deleted_at IS NULL;"
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, query, time.Now(), customerID, id)
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affect < 1 {
+		return ErrorAttachmentNotFound
+	}
+
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAttachment(row scanner) (domain.Attachment, error) {
+	a := domain.Attachment{}
+	err := row.Scan(&a.ID, &a.CustomerID, &a.FileName, &a.ContentType, &a.Size, &a.Checksum, &a.UploadedBy, &a.StorageKey, &a.CreatedAt)
+	return a, err
+}
@@ -0,0 +1,76 @@
+package attachment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuite_AttachmentRepository(t *testing.T) {
+	db, err := testutil.InitTxdbDatabase(t)
+	assert.NoError(t, err)
+	repository := NewRepository(db)
+	customers := customer.NewRepository(db)
+
+	testAttachmentsWithContext(t, repository, customers)
+
+	db.Close()
+}
+
+func testAttachmentsWithContext(t *testing.T, repository Repository, customers customer.Repository) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	day1 := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	customerID, err := customers.SaveWithContext(ctx, domain.Customer{
+		CustomerNumber: 929292,
+		FirstName:      "Danilo",
+		LastName:       "Sano",
+		Status:         domain.CustomerStatusProspect,
+		CreatedAt:      day1,
+	})
+	assert.NoError(t, err)
+
+	contract := domain.Attachment{
+		CustomerID:  customerID,
+		FileName:    "contract.pdf",
+		ContentType: "application/pdf",
+		Size:        1024,
+		Checksum:    "a0f3a0d2bfa1a8c5bc0f1c0f2f0a8c1d4c3a3e1b8f2c9e0d7b6a5f4e3d2c1b0a",
+		UploadedBy:  "agent-1",
+		StorageKey:  "customers/1/contract",
+		CreatedAt:   day1,
+	}
+	first, err := repository.SaveWithContext(ctx, contract)
+	assert.NoError(t, err)
+	scan := contract
+	scan.FileName, scan.ContentType, scan.StorageKey, scan.CreatedAt = "id.png", "image/png", "customers/1/id", day2
+	second, err := repository.SaveWithContext(ctx, scan)
+	assert.NoError(t, err)
+
+	attachments, err := repository.GetByCustomerIDWithContext(ctx, customerID)
+	assert.NoError(t, err)
+	if assert.Len(t, attachments, 2) {
+		assert.Equal(t, []int{second, first}, []int{attachments[0].ID, attachments[1].ID})
+	}
+
+	saved, err := repository.GetWithContext(ctx, customerID, first)
+	assert.NoError(t, err)
+	contract.ID = first
+	assert.Equal(t, contract, saved)
+
+	_, err = repository.GetWithContext(ctx, customerID+1, first)
+	assert.Equal(t, ErrorAttachmentNotFound, err)
+
+	assert.NoError(t, repository.DeleteWithContext(ctx, customerID, first))
+	_, err = repository.GetWithContext(ctx, customerID, first)
+	assert.Equal(t, ErrorAttachmentNotFound, err)
+	assert.Equal(t, ErrorAttachmentNotFound, repository.DeleteWithContext(ctx, customerID, first))
+}
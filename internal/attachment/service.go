@@ -0,0 +1,252 @@
+package attachment
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/danilosano/web-golang-api/internal/audit"
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/blob"
+	"github.com/danilosano/web-golang-api/pkg/reqctx"
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrorAttachmentNotFound   = errors.New("attachment not found")
+	ErrorAttachmentTooLarge   = errors.New("attachment too large")
+	ErrorUnsupportedMediaType = errors.New("unsupported media type")
+	ErrorChecksumMismatch     = errors.New("checksum mismatch")
+)
+
+const (
+	// DefaultMaxSize is the size limit of an attachment, 10 MiB.
+	DefaultMaxSize = 10 << 20
+	// maxFileNameLength fits the file names in their VARCHAR(255) column.
+	maxFileNameLength = 255
+)
+
+// DefaultAllowedTypes are the media types accepted by default: documents and
+// scanned images.
+var DefaultAllowedTypes = []string{"application/pdf", "image/png", "image/jpeg", "image/tiff", "image/webp"}
+
+type Service interface {
+	Upload(ctx context.Context, customerID int, file dto.AttachmentFile) (domain.Attachment, error)
+	GetAll(ctx context.Context, customerID int) ([]domain.Attachment, error)
+	Download(ctx context.Context, customerID, id int) (domain.Attachment, io.ReadCloser, error)
+	Delete(ctx context.Context, customerID, id int) error
+}
+
+type service struct {
+	repository   Repository
+	customers    customer.Repository
+	storage      blob.Storage
+	maxSize      int64
+	allowedTypes []string
+}
+
+type Option func(*service)
+
+// WithMaxSize limits the size of the attachments to maxSize bytes instead of
+// DefaultMaxSize.
+func WithMaxSize(maxSize int64) Option {
+	return func(s *service) {
+		s.maxSize = maxSize
+	}
+}
+
+// WithAllowedTypes accepts the attachments of the given media types instead of
+// DefaultAllowedTypes.
+func WithAllowedTypes(types ...string) Option {
+	return func(s *service) {
+		s.allowedTypes = types
+	}
+}
+
+// NewService manages the attachments of the customers found in customers,
+// keeping their content in storage. The attachments of a deleted customer are
+// deleted along with it by the customer repository, their content being kept.
+func NewService(r Repository, customers customer.Repository, storage blob.Storage, opts ...Option) Service {
+	s := &service{
+		repository:   r,
+		customers:    customers,
+		storage:      storage,
+		maxSize:      DefaultMaxSize,
+		allowedTypes: DefaultAllowedTypes,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Upload stores a file attached by the user of ctx. Its media type is sniffed
+// from the content rather than trusted from the client, and its checksum, when
+// given, verified.
+func (s *service) Upload(ctx context.Context, customerID int, file dto.AttachmentFile) (domain.Attachment, error) {
+	if customerExist := s.customers.ExistsByIDWithContext(ctx, customerID); !customerExist {
+		return domain.Attachment{}, customer.ErrorCustomerNotFound
+	}
+
+	if file.Size > s.maxSize {
+		return domain.Attachment{}, ErrorAttachmentTooLarge
+	}
+
+	contentType, err := s.detect(file.Content)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+
+	size, checksum, err := digest(file.Content)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	if size > s.maxSize {
+		return domain.Attachment{}, ErrorAttachmentTooLarge
+	}
+	if file.Checksum != "" && !strings.EqualFold(file.Checksum, checksum) {
+		return domain.Attachment{}, ErrorChecksumMismatch
+	}
+
+	a := domain.Attachment{
+		CustomerID:  customerID,
+		FileName:    fileName(file.Name),
+		ContentType: contentType,
+		Size:        size,
+		Checksum:    checksum,
+		UploadedBy:  actor(ctx),
+		StorageKey:  fmt.Sprintf("customers/%d/%s", customerID, uuid.NewString()),
+		CreatedAt:   time.Now().Truncate(time.Second),
+	}
+
+	if err := s.storage.Put(ctx, a.StorageKey, file.Content, size, checksum); err != nil {
+		if errors.Is(err, blob.ErrorChecksumMismatch) {
+			return domain.Attachment{}, ErrorChecksumMismatch
+		}
+		return domain.Attachment{}, err
+	}
+
+	id, err := s.repository.SaveWithContext(ctx, a)
+	if err != nil {
+		_ = s.storage.Delete(ctx, a.StorageKey)
+		return domain.Attachment{}, err
+	}
+
+	return s.repository.GetWithContext(ctx, customerID, id)
+}
+
+func (s *service) GetAll(ctx context.Context, customerID int) ([]domain.Attachment, error) {
+	if customerExist := s.customers.ExistsByIDWithContext(ctx, customerID); !customerExist {
+		return nil, customer.ErrorCustomerNotFound
+	}
+
+	return s.repository.GetByCustomerIDWithContext(ctx, customerID)
+}
+
+// Download returns an attachment along with its content, which the caller
+// must close.
+func (s *service) Download(ctx context.Context, customerID, id int) (domain.Attachment, io.ReadCloser, error) {
+	if customerExist := s.customers.ExistsByIDWithContext(ctx, customerID); !customerExist {
+		return domain.Attachment{}, nil, customer.ErrorCustomerNotFound
+	}
+
+	a, err := s.repository.GetWithContext(ctx, customerID, id)
+	if err != nil {
+		return domain.Attachment{}, nil, err
+	}
+
+	content, err := s.storage.Get(ctx, a.StorageKey)
+	if err != nil {
+		return domain.Attachment{}, nil, err
+	}
+
+	return a, content, nil
+}
+
+// Delete removes an attachment and then its content.
+func (s *service) Delete(ctx context.Context, customerID, id int) error {
+	if customerExist := s.customers.ExistsByIDWithContext(ctx, customerID); !customerExist {
+		return customer.ErrorCustomerNotFound
+	}
+
+	a, err := s.repository.GetWithContext(ctx, customerID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repository.DeleteWithContext(ctx, customerID, id); err != nil {
+		return err
+	}
+
+	return s.storage.Delete(ctx, a.StorageKey)
+}
+
+// detect sniffs the media type of content, rejecting the types not allowed, and
+// rewinds it.
+func (s *service) detect(content io.ReadSeeker) (string, error) {
+	mtype, err := mimetype.DetectReader(content)
+	if err != nil {
+		return "", err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	for _, allowed := range s.allowedTypes {
+		if mtype.Is(allowed) {
+			return mtype.String(), nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrorUnsupportedMediaType, mtype.String())
+}
+
+// digest returns the size and the hex-encoded SHA-256 of content, and rewinds it.
+func digest(content io.ReadSeeker) (int64, string, error) {
+	h := sha256.New()
+	size, err := io.Copy(h, content)
+	if err != nil {
+		return 0, "", err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// fileName keeps the base name of a client file name, without control
+// characters and cut to fit its column.
+func fileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+
+	if name == "." || name == "/" || name == "" {
+		return "attachment"
+	}
+	if runes := []rune(name); len(runes) > maxFileNameLength {
+		name = string(runes[:maxFileNameLength])
+	}
+	return name
+}
+
+// actor returns the user of ctx, or audit.AnonymousActor without one.
+func actor(ctx context.Context) string {
+	if user := reqctx.UserID(ctx); user != "" {
+		return user
+	}
+	return audit.AnonymousActor
+}
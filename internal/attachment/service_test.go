@@ -0,0 +1,153 @@
+package attachment
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/blob"
+	"github.com/danilosano/web-golang-api/pkg/reqctx"
+	mocks "github.com/danilosano/web-golang-api/pkg/tests/attachments"
+	customerMocks "github.com/danilosano/web-golang-api/pkg/tests/customers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// pdf is the beginning of a PDF document, enough for its type to be sniffed.
+var pdf = []byte("%PDF-1.7\n1 0 obj\n<< /Type /Catalog >>\nendobj\n")
+
+func createService(t *testing.T, opts ...Option) (Service, *mocks.AttachmentRepositoryMock, *customerMocks.CustomersRepositoryMock, *blob.FileSystem, context.Context) {
+	t.Helper()
+	repoMock := new(mocks.AttachmentRepositoryMock)
+	customersMock := new(customerMocks.CustomersRepositoryMock)
+	storage := blob.NewFileSystem(t.TempDir())
+	ctx := reqctx.WithUserID(context.Background(), "agent-1")
+	return NewService(repoMock, customersMock, storage, opts...), repoMock, customersMock, storage, ctx
+}
+
+func checksumOf(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestUpload(t *testing.T) {
+	t.Run("The content is stored with its sniffed type and checksum, ignoring the path of the file name.", func(t *testing.T) {
+		service, repoMock, customersMock, storage, ctx := createService(t)
+		var saved domain.Attachment
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("SaveWithContext", ctx, mock.MatchedBy(func(a domain.Attachment) bool {
+			saved = a
+			return true
+		})).Return(1, nil)
+		repoMock.On("GetWithContext", ctx, 1, 1).Return(domain.Attachment{ID: 1}, nil)
+
+		_, err := service.Upload(ctx, 1, dto.AttachmentFile{
+			Name:     `C:\scans\contract.pdf`,
+			Size:     int64(len(pdf)),
+			Content:  bytes.NewReader(pdf),
+			Checksum: strings.ToUpper(checksumOf(pdf)),
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, "contract.pdf", saved.FileName)
+		assert.Equal(t, "application/pdf", saved.ContentType)
+		assert.Equal(t, int64(len(pdf)), saved.Size)
+		assert.Equal(t, checksumOf(pdf), saved.Checksum)
+		assert.Equal(t, "agent-1", saved.UploadedBy)
+		assert.True(t, strings.HasPrefix(saved.StorageKey, "customers/1/"))
+
+		content, err := storage.Get(ctx, saved.StorageKey)
+		assert.NoError(t, err)
+		data, _ := io.ReadAll(content)
+		content.Close()
+		assert.Equal(t, pdf, data)
+	})
+
+	t.Run("A type not allowed is rejected whatever the name of the file.", func(t *testing.T) {
+		service, repoMock, customersMock, _, ctx := createService(t)
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		script := []byte("#!/bin/sh\necho hello\n")
+
+		_, err := service.Upload(ctx, 1, dto.AttachmentFile{Name: "contract.pdf", Size: int64(len(script)), Content: bytes.NewReader(script)})
+		assert.ErrorIs(t, err, ErrorUnsupportedMediaType)
+		repoMock.AssertNotCalled(t, "SaveWithContext", mock.Anything, mock.Anything)
+	})
+
+	t.Run("A file larger than the limit is rejected.", func(t *testing.T) {
+		service, _, customersMock, _, ctx := createService(t, WithMaxSize(16))
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+
+		_, err := service.Upload(ctx, 1, dto.AttachmentFile{Name: "contract.pdf", Size: 10, Content: bytes.NewReader(pdf)})
+		assert.Equal(t, ErrorAttachmentTooLarge, err)
+	})
+
+	t.Run("A file that does not match the given checksum is rejected.", func(t *testing.T) {
+		service, repoMock, customersMock, _, ctx := createService(t)
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+
+		_, err := service.Upload(ctx, 1, dto.AttachmentFile{Name: "contract.pdf", Size: int64(len(pdf)), Content: bytes.NewReader(pdf), Checksum: checksumOf([]byte("other"))})
+		assert.Equal(t, ErrorChecksumMismatch, err)
+		repoMock.AssertNotCalled(t, "SaveWithContext", mock.Anything, mock.Anything)
+	})
+
+	t.Run("When the metadata cannot be saved, the content is removed.", func(t *testing.T) {
+		service, repoMock, customersMock, storage, ctx := createService(t)
+		var key string
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("SaveWithContext", ctx, mock.MatchedBy(func(a domain.Attachment) bool {
+			key = a.StorageKey
+			return true
+		})).Return(0, errors.New("generic error"))
+
+		_, err := service.Upload(ctx, 1, dto.AttachmentFile{Name: "contract.pdf", Size: int64(len(pdf)), Content: bytes.NewReader(pdf)})
+		assert.EqualError(t, err, "generic error")
+		_, err = storage.Get(ctx, key)
+		assert.Equal(t, blob.ErrorBlobNotFound, err)
+	})
+
+	t.Run("When the customer does not exist, an error will be returned.", func(t *testing.T) {
+		service, _, customersMock, _, ctx := createService(t)
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(false)
+
+		_, err := service.Upload(ctx, 1, dto.AttachmentFile{Name: "contract.pdf", Size: int64(len(pdf)), Content: bytes.NewReader(pdf)})
+		assert.Equal(t, customer.ErrorCustomerNotFound, err)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	t.Run("The attachment and its content are removed.", func(t *testing.T) {
+		service, repoMock, customersMock, storage, ctx := createService(t)
+		key := "customers/1/contract"
+		assert.NoError(t, storage.Put(ctx, key, bytes.NewReader(pdf), int64(len(pdf)), checksumOf(pdf)))
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("GetWithContext", ctx, 1, 1).Return(domain.Attachment{ID: 1, CustomerID: 1, StorageKey: key}, nil)
+		repoMock.On("DeleteWithContext", ctx, 1, 1).Return(nil)
+
+		assert.NoError(t, service.Delete(ctx, 1, 1))
+		_, err := storage.Get(ctx, key)
+		assert.Equal(t, blob.ErrorBlobNotFound, err)
+	})
+}
+
+func TestFileName(t *testing.T) {
+	t.Run("Paths and control characters are removed from the names.", func(t *testing.T) {
+		for name, expected := range map[string]string{
+			"contract.pdf":           "contract.pdf",
+			"../../etc/passwd":       "passwd",
+			`C:\scans\id card.png`:   "id card.png",
+			"con\x00tract\n.pdf":     "contract.pdf",
+			"":                       "attachment",
+			"/":                      "attachment",
+			strings.Repeat("é", 300): strings.Repeat("é", 255),
+		} {
+			assert.Equal(t, expected, fileName(name))
+		}
+	})
+}
@@ -0,0 +1,166 @@
+package attribute
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/pkg/database"
+	"github.com/go-sql-driver/mysql"
+)
+
+// mysqlDuplicateEntry is the MySQL error raised when a unique index is violated.
+const mysqlDuplicateEntry = 1062
+
+type Repository interface {
+	GetAllWithContext(ctx context.Context) ([]domain.AttributeDefinition, error)
+	GetWithContext(ctx context.Context, id int) (domain.AttributeDefinition, error)
+	ExistsByNameWithContext(ctx context.Context, name string, id int) bool
+	SaveWithContext(ctx context.Context, d domain.AttributeDefinition) (int, error)
+	UpdateWithContext(ctx context.Context, d domain.AttributeDefinition) error
+	DeleteWithContext(ctx context.Context, id int) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+const definitionColumns = "attribute_id, name, type, required, options, created_at, updated_at"
+
+// GetAllWithContext returns every attribute definition, ordered by name.
+func (r *repository) GetAllWithContext(ctx context.Context) ([]domain.AttributeDefinition, error) {
+	query := "SELECT " + definitionColumns + " FROM attribute_definitions ORDER BY name;"
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var definitions []domain.AttributeDefinition
+
+	for rows.Next() {
+		d, err := scanDefinition(rows)
+		if err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, d)
+	}
+
+	return definitions, rows.Err()
+}
+
+func (r *repository) GetWithContext(ctx context.Context, id int) (domain.AttributeDefinition, error) {
+	query := "SELECT " + definitionColumns + " FROM attribute_definitions WHERE attribute_id=?;"
+	row := database.Conn(ctx, r.db).QueryRowContext(ctx, query, id)
+	d, err := scanDefinition(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.AttributeDefinition{}, ErrorAttributeNotFound
+		}
+		return domain.AttributeDefinition{}, err
+	}
+
+	return d, nil
+}
+
+// ExistsByNameWithContext reports whether an attribute other than id is named name.
+func (r *repository) ExistsByNameWithContext(ctx context.Context, name string, id int) bool {
+	query := "SELECT attribute_id FROM attribute_definitions WHERE name=? and attribute_id<>?;"
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, query, name, id).Scan(&id)
+	return errors.Is(err, nil)
+}
+
+func (r *repository) SaveWithContext(ctx context.Context, d domain.AttributeDefinition) (int, error) {
+	options, err := marshalOptions(d.Options)
+	if err != nil {
+		return 0, err
+	}
+
+	query := "INSERT INTO attribute_definitions (name, type, required, options, created_at) VALUES (?, ?, ?, ?, ?);"
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, query, d.Name, d.Type, d.Required, options, d.CreatedAt)
+	if err != nil {
+		return 0, duplicateError(err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+func (r *repository) UpdateWithContext(ctx context.Context, d domain.AttributeDefinition) error {
+	options, err := marshalOptions(d.Options)
+	if err != nil {
+		return err
+	}
+
+	query := "UPDATE attribute_definitions SET name=?, type=?, required=?, options=?, updated_at=? WHERE attribute_id=?;"
+	_, err = database.Conn(ctx, r.db).ExecContext(ctx, query, d.Name, d.Type, d.Required, options, d.UpdatedAt, d.ID)
+	return duplicateError(err)
+}
+
+// DeleteWithContext removes a definition. The values customers hold for it are
+// kept, and rejected the next time the customer is written.
+func (r *repository) DeleteWithContext(ctx context.Context, id int) error {
+	query := "DELETE FROM attribute_definitions WHERE attribute_id=?;"
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affect < 1 {
+		return ErrorAttributeNotFound
+	}
+
+	return nil
+}
+
+func marshalOptions(options []string) (any, error) {
+	if len(options) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(options)
+}
+
+// duplicateError reports a violation of the unique index on the names as
+// ErrorAttributeAlreadyExist.
+func duplicateError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+		return ErrorAttributeAlreadyExist
+	}
+	return err
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanDefinition(row scanner) (domain.AttributeDefinition, error) {
+	var options []byte
+	d := domain.AttributeDefinition{}
+	if err := row.Scan(&d.ID, &d.Name, &d.Type, &d.Required, &options, &d.CreatedAt, &d.UpdatedAt); err != nil {
+		return domain.AttributeDefinition{}, err
+	}
+	if options != nil {
+		if err := json.Unmarshal(options, &d.Options); err != nil {
+			return domain.AttributeDefinition{}, err
+		}
+	}
+	return d, nil
+}
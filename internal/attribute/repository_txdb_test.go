@@ -0,0 +1,82 @@
+package attribute
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuite_AttributeRepository(t *testing.T) {
+	db, err := testutil.InitTxdbDatabase(t)
+	assert.NoError(t, err)
+	repository := NewRepository(db)
+	customers := customer.NewRepository(db)
+
+	testDefinitionsWithContext(t, repository)
+	testAttributeFilterWithContext(t, customers)
+
+	db.Close()
+}
+
+func testDefinitionsWithContext(t *testing.T, repository Repository) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	id, err := repository.SaveWithContext(ctx, domain.AttributeDefinition{
+		Name:      "txdb_tier",
+		Type:      domain.AttributeTypeEnum,
+		Required:  true,
+		Options:   []string{"silver", "gold"},
+		CreatedAt: now,
+	})
+	assert.NoError(t, err)
+
+	d, err := repository.GetWithContext(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"silver", "gold"}, d.Options)
+	assert.True(t, d.Required)
+
+	assert.True(t, repository.ExistsByNameWithContext(ctx, "txdb_tier", 0))
+	assert.False(t, repository.ExistsByNameWithContext(ctx, "txdb_tier", id))
+
+	_, err = repository.SaveWithContext(ctx, domain.AttributeDefinition{Name: "txdb_tier", Type: domain.AttributeTypeString, CreatedAt: now})
+	assert.ErrorIs(t, err, ErrorAttributeAlreadyExist)
+
+	assert.NoError(t, repository.DeleteWithContext(ctx, id))
+	assert.ErrorIs(t, repository.DeleteWithContext(ctx, id), ErrorAttributeNotFound)
+	_, err = repository.GetWithContext(ctx, id)
+	assert.ErrorIs(t, err, ErrorAttributeNotFound)
+}
+
+func testAttributeFilterWithContext(t *testing.T, customers customer.Repository) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	for number, tier := range map[int]string{818181: "gold", 828282: "silver"} {
+		_, err := customers.SaveWithContext(ctx, domain.Customer{
+			CustomerNumber: number,
+			FirstName:      "Danilo",
+			LastName:       "Sano",
+			Status:         domain.CustomerStatusProspect,
+			Attributes:     domain.Attributes{"txdb_tier": tier, "employees": 250.0},
+			CreatedAt:      time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		})
+		assert.NoError(t, err)
+	}
+
+	page, total, err := customers.ListWithContext(ctx, dto.CustomerFilter{Attributes: []string{"txdb_tier:gold", "employees:250"}, Page: 1, PageSize: 20})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	if assert.Len(t, page, 1) {
+		assert.Equal(t, 818181, page[0].CustomerNumber)
+		assert.Equal(t, domain.Attributes{"txdb_tier": "gold", "employees": 250.0}, page[0].Attributes)
+	}
+}
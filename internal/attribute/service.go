@@ -0,0 +1,251 @@
+package attribute
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+)
+
+var (
+	ErrorAttributeNotFound     = errors.New("attribute not found")
+	ErrorAttributeAlreadyExist = errors.New("attribute already exist")
+	ErrorInvalidDefinition     = errors.New("invalid attribute definition")
+	ErrorInvalidAttribute      = errors.New("invalid attribute")
+)
+
+// namePattern is the shape of an attribute name, which is also its key in the
+// attributes of a customer and in the attr filter.
+var namePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+const (
+	// maxStringLength caps the values of the string attributes.
+	maxStringLength = 255
+	dateLayout      = "2006-01-02"
+)
+
+type Service interface {
+	Save(ctx context.Context, input dto.AttributeDefinitionRequest) (domain.AttributeDefinition, error)
+	GetAll(ctx context.Context) ([]domain.AttributeDefinition, error)
+	Get(ctx context.Context, id int) (domain.AttributeDefinition, error)
+	Update(ctx context.Context, input dto.AttributeDefinitionRequest, id int) (domain.AttributeDefinition, error)
+	Delete(ctx context.Context, id int) error
+	Validate(ctx context.Context, attributes domain.Attributes) (domain.Attributes, error)
+}
+
+type service struct {
+	repository Repository
+}
+
+// NewService manages the definitions of the custom attributes and validates the
+// attributes of the customers against them.
+func NewService(r Repository) Service {
+	return &service{
+		repository: r,
+	}
+}
+
+func (s *service) Save(ctx context.Context, input dto.AttributeDefinitionRequest) (domain.AttributeDefinition, error) {
+	d, err := newDefinition(input)
+	if err != nil {
+		return domain.AttributeDefinition{}, err
+	}
+
+	if exists := s.repository.ExistsByNameWithContext(ctx, d.Name, 0); exists {
+		return domain.AttributeDefinition{}, ErrorAttributeAlreadyExist
+	}
+
+	d.CreatedAt = time.Now().Truncate(time.Second)
+	id, err := s.repository.SaveWithContext(ctx, d)
+	if err != nil {
+		return domain.AttributeDefinition{}, err
+	}
+
+	return s.repository.GetWithContext(ctx, id)
+}
+
+func (s *service) GetAll(ctx context.Context) ([]domain.AttributeDefinition, error) {
+	return s.repository.GetAllWithContext(ctx)
+}
+
+func (s *service) Get(ctx context.Context, id int) (domain.AttributeDefinition, error) {
+	return s.repository.GetWithContext(ctx, id)
+}
+
+// Update replaces a definition. The values customers already hold are not
+// revalidated; they are checked the next time the customer is written.
+func (s *service) Update(ctx context.Context, input dto.AttributeDefinitionRequest, id int) (domain.AttributeDefinition, error) {
+	d, err := newDefinition(input)
+	if err != nil {
+		return domain.AttributeDefinition{}, err
+	}
+
+	current, err := s.repository.GetWithContext(ctx, id)
+	if err != nil {
+		return domain.AttributeDefinition{}, err
+	}
+
+	if exists := s.repository.ExistsByNameWithContext(ctx, d.Name, id); exists {
+		return domain.AttributeDefinition{}, ErrorAttributeAlreadyExist
+	}
+
+	now := time.Now().Truncate(time.Second)
+	d.ID = id
+	d.CreatedAt = current.CreatedAt
+	d.UpdatedAt = &now
+
+	if err := s.repository.UpdateWithContext(ctx, d); err != nil {
+		return domain.AttributeDefinition{}, err
+	}
+
+	return s.repository.GetWithContext(ctx, id)
+}
+
+func (s *service) Delete(ctx context.Context, id int) error {
+	return s.repository.DeleteWithContext(ctx, id)
+}
+
+// Validate checks the attributes of a customer against the definitions: every
+// name must be defined, every required attribute present and every value of the
+// type of its definition. The values are returned normalized, numbers as float64
+// and dates as YYYY-MM-DD, or nil when there are none.
+func (s *service) Validate(ctx context.Context, attributes domain.Attributes) (domain.Attributes, error) {
+	definitions, err := s.repository.GetAllWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]domain.AttributeDefinition, len(definitions))
+	for _, d := range definitions {
+		byName[d.Name] = d
+	}
+
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make(domain.Attributes, len(attributes))
+	for _, name := range names {
+		d, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is not defined", ErrorInvalidAttribute, name)
+		}
+
+		if attributes[name] == nil {
+			continue
+		}
+
+		value, err := normalize(d, attributes[name])
+		if err != nil {
+			return nil, err
+		}
+		result[name] = value
+	}
+
+	for _, d := range definitions {
+		if _, ok := result[d.Name]; d.Required && !ok {
+			return nil, fmt.Errorf("%w: %s is required", ErrorInvalidAttribute, d.Name)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+// normalize converts a value to the type of its definition.
+func normalize(d domain.AttributeDefinition, value any) (any, error) {
+	switch d.Type {
+	case domain.AttributeTypeNumber:
+		if n, ok := toNumber(value); ok {
+			return n, nil
+		}
+		return nil, fmt.Errorf("%w: %s must be a number", ErrorInvalidAttribute, d.Name)
+	case domain.AttributeTypeDate:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s must be a date", ErrorInvalidAttribute, d.Name)
+		}
+		date, err := time.Parse(dateLayout, s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s must be a date formatted as YYYY-MM-DD", ErrorInvalidAttribute, d.Name)
+		}
+		return date.Format(dateLayout), nil
+	case domain.AttributeTypeEnum:
+		s, ok := value.(string)
+		if !ok || !slices.Contains(d.Options, s) {
+			return nil, fmt.Errorf("%w: %s must be one of: %s", ErrorInvalidAttribute, d.Name, strings.Join(d.Options, ", "))
+		}
+		return s, nil
+	default:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s must be a string", ErrorInvalidAttribute, d.Name)
+		}
+		if utf8.RuneCountInString(s) > maxStringLength {
+			return nil, fmt.Errorf("%w: %s must be at most %d characters long", ErrorInvalidAttribute, d.Name, maxStringLength)
+		}
+		return s, nil
+	}
+}
+
+// toNumber accepts the numbers decoded from JSON, any Go number and, since XML
+// bodies only carry text, numeric strings.
+func toNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case json.Number:
+		n, err := v.Float64()
+		return n, err == nil
+	case string:
+		n, err := strconv.ParseFloat(v, 64)
+		return n, err == nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch {
+	case rv.CanInt():
+		return float64(rv.Int()), true
+	case rv.CanUint():
+		return float64(rv.Uint()), true
+	case rv.CanFloat():
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+func newDefinition(input dto.AttributeDefinitionRequest) (domain.AttributeDefinition, error) {
+	if !namePattern.MatchString(input.Name) {
+		return domain.AttributeDefinition{}, fmt.Errorf("%w: the name must start with a letter and contain only lowercase letters, digits and underscores", ErrorInvalidDefinition)
+	}
+
+	isEnum := input.Type == domain.AttributeTypeEnum
+	if isEnum && len(input.Options) == 0 {
+		return domain.AttributeDefinition{}, fmt.Errorf("%w: an enum needs options", ErrorInvalidDefinition)
+	}
+	if !isEnum && len(input.Options) > 0 {
+		return domain.AttributeDefinition{}, fmt.Errorf("%w: only an enum has options", ErrorInvalidDefinition)
+	}
+
+	return domain.AttributeDefinition{
+		Name:     input.Name,
+		Type:     input.Type,
+		Required: input.Required,
+		Options:  input.Options,
+	}, nil
+}
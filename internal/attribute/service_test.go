@@ -0,0 +1,145 @@
+package attribute
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	mocks "github.com/danilosano/web-golang-api/pkg/tests/attributes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func createService(t *testing.T) (Service, *mocks.AttributeRepositoryMock, context.Context) {
+	t.Helper()
+	repoMock := new(mocks.AttributeRepositoryMock)
+	return NewService(repoMock), repoMock, context.Background()
+}
+
+var mockedDefinitions = []domain.AttributeDefinition{
+	{ID: 1, Name: "employees", Type: domain.AttributeTypeNumber},
+	{ID: 2, Name: "nickname", Type: domain.AttributeTypeString},
+	{ID: 3, Name: "renewal", Type: domain.AttributeTypeDate},
+	{ID: 4, Name: "tier", Type: domain.AttributeTypeEnum, Required: true, Options: []string{"silver", "gold"}},
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("The values are normalized to the type of their definition.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("GetAllWithContext", ctx).Return(mockedDefinitions, nil)
+
+		result, err := service.Validate(ctx, domain.Attributes{
+			"employees": json.Number("250"),
+			"nickname":  "ACME",
+			"renewal":   "2026-01-31",
+			"tier":      "gold",
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, domain.Attributes{"employees": 250.0, "nickname": "ACME", "renewal": "2026-01-31", "tier": "gold"}, result)
+	})
+
+	t.Run("Numbers sent as text, as XML bodies do, are accepted.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("GetAllWithContext", ctx).Return(mockedDefinitions, nil)
+
+		result, err := service.Validate(ctx, domain.Attributes{"employees": "12.5", "tier": "silver"})
+		assert.Nil(t, err)
+		assert.Equal(t, domain.Attributes{"employees": 12.5, "tier": "silver"}, result)
+	})
+
+	t.Run("A value that does not match its definition is rejected.", func(t *testing.T) {
+		for attributes, message := range map[string]string{
+			`{"tier": "platinum"}`:                   "invalid attribute: tier must be one of: silver, gold",
+			`{"tier": "gold", "employees": "many"}`:  "invalid attribute: employees must be a number",
+			`{"tier": "gold", "renewal": "31/01"}`:   "invalid attribute: renewal must be a date formatted as YYYY-MM-DD",
+			`{"tier": "gold", "nickname": 1}`:        "invalid attribute: nickname must be a string",
+			`{"tier": "gold", "industry": "retail"}`: "invalid attribute: industry is not defined",
+			`{"nickname": "ACME"}`:                   "invalid attribute: tier is required",
+			`{"tier": null}`:                         "invalid attribute: tier is required",
+		} {
+			var input domain.Attributes
+			assert.Nil(t, json.Unmarshal([]byte(attributes), &input))
+			service, repoMock, ctx := createService(t)
+			repoMock.On("GetAllWithContext", ctx).Return(mockedDefinitions, nil)
+
+			_, err := service.Validate(ctx, input)
+			assert.ErrorIs(t, err, ErrorInvalidAttribute)
+			assert.EqualError(t, err, message)
+		}
+	})
+
+	t.Run("Without definitions nor values, nil is returned.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("GetAllWithContext", ctx).Return(nil, nil)
+
+		result, err := service.Validate(ctx, nil)
+		assert.Nil(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestSave(t *testing.T) {
+	t.Run("When the definition is valid, it is created and returned.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		input := dto.AttributeDefinitionRequest{Name: "tier", Type: domain.AttributeTypeEnum, Required: true, Options: []string{"silver", "gold"}}
+		repoMock.On("ExistsByNameWithContext", ctx, "tier", 0).Return(false)
+		repoMock.On("SaveWithContext", ctx, mock.MatchedBy(func(d domain.AttributeDefinition) bool {
+			return d.Name == "tier" && d.Required && len(d.Options) == 2 && !d.CreatedAt.IsZero()
+		})).Return(4, nil)
+		repoMock.On("GetWithContext", ctx, 4).Return(mockedDefinitions[3], nil)
+
+		result, err := service.Save(ctx, input)
+		assert.Nil(t, err)
+		assert.Equal(t, mockedDefinitions[3], result)
+	})
+
+	t.Run("An invalid name, an enum without options or options of another type are rejected.", func(t *testing.T) {
+		for _, input := range []dto.AttributeDefinitionRequest{
+			{Name: "Tier", Type: domain.AttributeTypeString},
+			{Name: "1st_order", Type: domain.AttributeTypeDate},
+			{Name: "tier", Type: domain.AttributeTypeEnum},
+			{Name: "nickname", Type: domain.AttributeTypeString, Options: []string{"a"}},
+		} {
+			service, repoMock, ctx := createService(t)
+
+			_, err := service.Save(ctx, input)
+			assert.ErrorIs(t, err, ErrorInvalidDefinition)
+			repoMock.AssertNotCalled(t, "SaveWithContext", mock.Anything, mock.Anything)
+		}
+	})
+
+	t.Run("When the name is taken, an error will be returned.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("ExistsByNameWithContext", ctx, "nickname", 0).Return(true)
+
+		_, err := service.Save(ctx, dto.AttributeDefinitionRequest{Name: "nickname", Type: domain.AttributeTypeString})
+		assert.Equal(t, ErrorAttributeAlreadyExist, err)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	t.Run("The creation date is kept and the update date set.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		input := dto.AttributeDefinitionRequest{Name: "nickname", Type: domain.AttributeTypeString, Required: true}
+		repoMock.On("GetWithContext", ctx, 2).Return(mockedDefinitions[1], nil)
+		repoMock.On("ExistsByNameWithContext", ctx, "nickname", 2).Return(false)
+		repoMock.On("UpdateWithContext", ctx, mock.MatchedBy(func(d domain.AttributeDefinition) bool {
+			return d.ID == 2 && d.Required && d.CreatedAt.Equal(mockedDefinitions[1].CreatedAt) && d.UpdatedAt != nil
+		})).Return(nil)
+
+		_, err := service.Update(ctx, input, 2)
+		assert.Nil(t, err)
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("When the definition does not exist, an error will be returned.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("GetWithContext", ctx, 9).Return(nil, ErrorAttributeNotFound)
+
+		_, err := service.Update(ctx, dto.AttributeDefinitionRequest{Name: "nickname", Type: domain.AttributeTypeString}, 9)
+		assert.True(t, errors.Is(err, ErrorAttributeNotFound))
+	})
+}
@@ -0,0 +1,86 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/pkg/database"
+)
+
+type Repository interface {
+	SaveWithContext(ctx context.Context, e domain.AuditEntry) (int, error)
+	GetByCustomerIDWithContext(ctx context.Context, customerID int) ([]domain.AuditEntry, error)
+	GetByCustomerIDAfterWithContext(ctx context.Context, customerID int, after time.Time, afterID, limit int) ([]domain.AuditEntry, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) SaveWithContext(ctx context.Context, e domain.AuditEntry) (int, error) {
+	query := "INSERT INTO audit_log (actor, action, customer_id, before_data, after_data, request_id, created_at) VALUES (?, ?, ?, ?, ?, ?, ?);"
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, query, e.Actor, e.Action, e.CustomerID, nullJSON(e.Before), nullJSON(e.After), e.RequestID, e.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+const auditColumns = "audit_id, actor, action, customer_id, before_data, after_data, request_id, created_at"
+
+func (r *repository) GetByCustomerIDWithContext(ctx context.Context, customerID int) ([]domain.AuditEntry, error) {
+	query := "SELECT " + auditColumns + " FROM audit_log WHERE customer_id=? ORDER BY created_at, audit_id;"
+	return r.queryEntries(ctx, query, customerID)
+}
+
+// GetByCustomerIDAfterWithContext returns up to limit entries of a customer
+// recorded after the given date or, on that date, with an ID greater than
+// afterID, oldest first.
+func (r *repository) GetByCustomerIDAfterWithContext(ctx context.Context, customerID int, after time.Time, afterID, limit int) ([]domain.AuditEntry, error) {
+	query := "SELECT " + auditColumns + " FROM audit_log WHERE customer_id=? and (created_at>? or (created_at=? and audit_id>?)) ORDER BY created_at, audit_id LIMIT ?;"
+	return r.queryEntries(ctx, query, customerID, after, after, afterID, limit)
+}
+
+func (r *repository) queryEntries(ctx context.Context, query string, args ...any) ([]domain.AuditEntry, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []domain.AuditEntry
+
+	for rows.Next() {
+		var before, after []byte
+		e := domain.AuditEntry{}
+		if err := rows.Scan(&e.ID, &e.Actor, &e.Action, &e.CustomerID, &before, &after, &e.RequestID, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		e.Before, e.After = before, after
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// nullJSON stores absent snapshots as NULL rather than an empty document.
+func nullJSON(data []byte) any {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
@@ -0,0 +1,55 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuite_AuditRepository(t *testing.T) {
+	db, err := testutil.InitTxdbDatabase(t)
+	assert.NoError(t, err)
+	repository := NewRepository(db)
+
+	testSaveAndGetByCustomerIDWithContext(t, repository)
+
+	db.Close()
+}
+
+func testSaveAndGetByCustomerIDWithContext(t *testing.T, repository Repository) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	created := domain.AuditEntry{
+		Actor:      "agent-1",
+		Action:     domain.AuditActionCreate,
+		CustomerID: 999999,
+		After:      json.RawMessage(`{"id": 999999}`),
+		RequestID:  "req-1",
+		CreatedAt:  time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	deleted := domain.AuditEntry{
+		Actor:      "agent-2",
+		Action:     domain.AuditActionDelete,
+		CustomerID: 999999,
+		Before:     json.RawMessage(`{"id": 999999}`),
+		RequestID:  "req-2",
+		CreatedAt:  time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+
+	createdID, err := repository.SaveWithContext(ctx, created)
+	assert.NoError(t, err)
+	deletedID, err := repository.SaveWithContext(ctx, deleted)
+	assert.NoError(t, err)
+
+	created.ID, deleted.ID = createdID, deletedID
+	result, err := repository.GetByCustomerIDWithContext(ctx, 999999)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.AuditEntry{created, deleted}, result)
+}
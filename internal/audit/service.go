@@ -0,0 +1,67 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/pkg/reqctx"
+)
+
+// AnonymousActor is recorded when a change is made without an authenticated user.
+const AnonymousActor = "anonymous"
+
+type Service interface {
+	GetByCustomerID(ctx context.Context, customerID int) ([]domain.AuditEntry, error)
+}
+
+type service struct {
+	repository Repository
+}
+
+func NewService(r Repository) Service {
+	return &service{
+		repository: r,
+	}
+}
+
+func (s *service) GetByCustomerID(ctx context.Context, customerID int) ([]domain.AuditEntry, error) {
+	return s.repository.GetByCustomerIDWithContext(ctx, customerID)
+}
+
+// NewEntry builds the audit entry of an action on a customer, taking the actor and
+// request ID from ctx. before and after are snapshots of the customer, nil when absent.
+func NewEntry(ctx context.Context, action string, customerID int, before, after any) (domain.AuditEntry, error) {
+	beforeJSON, err := snapshot(before)
+	if err != nil {
+		return domain.AuditEntry{}, err
+	}
+
+	afterJSON, err := snapshot(after)
+	if err != nil {
+		return domain.AuditEntry{}, err
+	}
+
+	actor := reqctx.UserID(ctx)
+	if actor == "" {
+		actor = AnonymousActor
+	}
+
+	return domain.AuditEntry{
+		Actor:      actor,
+		Action:     action,
+		CustomerID: customerID,
+		Before:     beforeJSON,
+		After:      afterJSON,
+		RequestID:  reqctx.RequestID(ctx),
+		CreatedAt:  time.Now().Truncate(time.Second),
+	}, nil
+}
+
+func snapshot(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
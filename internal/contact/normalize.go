@@ -0,0 +1,54 @@
+package contact
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/nyaruka/phonenumbers"
+)
+
+var (
+	ErrorInvalidEmail       = errors.New("invalid email address")
+	ErrorInvalidPhoneNumber = errors.New("invalid phone number")
+)
+
+// Normalize returns the canonical form of a contact value: a lower-cased email
+// address, or a phone number in E.164 format. region is the ISO 3166-1 alpha-2
+// code used to read phone numbers given without their country code.
+func Normalize(contactType, value, region string) (string, error) {
+	value = strings.TrimSpace(value)
+	switch contactType {
+	case domain.ContactTypeEmail:
+		return normalizeEmail(value)
+	case domain.ContactTypePhone:
+		return normalizePhone(value, region)
+	}
+	return value, nil
+}
+
+// normalizeEmail accepts a bare address, without a display name, whose domain
+// has at least two labels.
+func normalizeEmail(value string) (string, error) {
+	addr, err := mail.ParseAddress(value)
+	if err != nil || addr.Address != value {
+		return "", ErrorInvalidEmail
+	}
+
+	_, host, _ := strings.Cut(addr.Address, "@")
+	if !strings.Contains(host, ".") || strings.HasPrefix(host, ".") || strings.HasSuffix(host, ".") {
+		return "", ErrorInvalidEmail
+	}
+
+	return strings.ToLower(addr.Address), nil
+}
+
+func normalizePhone(value, region string) (string, error) {
+	number, err := phonenumbers.Parse(value, strings.ToUpper(region))
+	if err != nil || !phonenumbers.IsValidNumber(number) {
+		return "", ErrorInvalidPhoneNumber
+	}
+
+	return phonenumbers.Format(number, phonenumbers.E164), nil
+}
@@ -0,0 +1,43 @@
+package contact
+
+import (
+	"testing"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	t.Run("An email is trimmed and lower-cased.", func(t *testing.T) {
+		value, err := Normalize(domain.ContactTypeEmail, " Danilo.Sano@Example.COM ", "")
+		assert.Nil(t, err)
+		assert.Equal(t, "danilo.sano@example.com", value)
+	})
+
+	t.Run("An email with a display name, without a domain or without an at sign is rejected.", func(t *testing.T) {
+		for _, value := range []string{"Danilo <danilo@example.com>", "danilo@localhost", "danilo.example.com", "danilo@example.com."} {
+			_, err := Normalize(domain.ContactTypeEmail, value, "")
+			assert.Equal(t, ErrorInvalidEmail, err, value)
+		}
+	})
+
+	t.Run("A national phone number is read in its region and formatted in E.164.", func(t *testing.T) {
+		value, err := Normalize(domain.ContactTypePhone, "(11) 98765-4321", "BR")
+		assert.Nil(t, err)
+		assert.Equal(t, "+5511987654321", value)
+	})
+
+	t.Run("An international phone number needs no region.", func(t *testing.T) {
+		value, err := Normalize(domain.ContactTypePhone, "+1 650-253-0000", "")
+		assert.Nil(t, err)
+		assert.Equal(t, "+16502530000", value)
+	})
+
+	t.Run("A national phone number without region, or an impossible one, is rejected.", func(t *testing.T) {
+		_, err := Normalize(domain.ContactTypePhone, "(11) 98765-4321", "")
+		assert.Equal(t, ErrorInvalidPhoneNumber, err)
+
+		_, err = Normalize(domain.ContactTypePhone, "123", "BR")
+		assert.Equal(t, ErrorInvalidPhoneNumber, err)
+	})
+}
@@ -0,0 +1,175 @@
+package contact
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/pkg/database"
+	"github.com/go-sql-driver/mysql"
+)
+
+// mysqlDuplicateEntry is the MySQL error raised when a unique index is violated.
+const mysqlDuplicateEntry = 1062
+
+type Repository interface {
+	GetByCustomerIDWithContext(ctx context.Context, customerID int) ([]domain.Contact, error)
+	GetByCustomerIDsWithContext(ctx context.Context, customerIDs []int) ([]domain.Contact, error)
+	GetWithContext(ctx context.Context, customerID, id int) (domain.Contact, error)
+	ExistsPrimaryEmailWithContext(ctx context.Context, email string, customerID int) bool
+	SaveWithContext(ctx context.Context, c domain.Contact) (int, error)
+	UpdateWithContext(ctx context.Context, c domain.Contact) error
+	DeleteWithContext(ctx context.Context, customerID, id int) error
+	ClearPrimaryWithContext(ctx context.Context, customerID int, contactType string) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+const contactColumns = "contact_id, customer_id, type, label, value, is_primary, verified, created_at, updated_at"
+
+func (r *repository) GetByCustomerIDWithContext(ctx context.Context, customerID int) ([]domain.Contact, error) {
+	query := "SELECT " + contactColumns + " FROM contacts WHERE deleted_at IS NULL and customer_id=? ORDER BY contact_id;"
+	return r.queryContacts(ctx, query, customerID)
+}
+
+// GetByCustomerIDsWithContext returns the contacts of several customers at once,
+// ordered by customer.
+func (r *repository) GetByCustomerIDsWithContext(ctx context.Context, customerIDs []int) ([]domain.Contact, error) {
+	if len(customerIDs) == 0 {
+		return nil, nil
+	}
+
+	args := make([]any, len(customerIDs))
+	for i, id := range customerIDs {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(customerIDs)), ",")
+	query := "SELECT " + contactColumns + " FROM contacts WHERE deleted_at IS NULL and customer_id IN (" + placeholders + ") ORDER BY customer_id, contact_id;"
+	return r.queryContacts(ctx, query, args...)
+}
+
+func (r *repository) GetWithContext(ctx context.Context, customerID, id int) (domain.Contact, error) {
+	query := "SELECT " + contactColumns + " FROM contacts WHERE deleted_at IS NULL and customer_id=? and contact_id=?;"
+	row := database.Conn(ctx, r.db).QueryRowContext(ctx, query, customerID, id)
+	c, err := scanContact(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Contact{}, ErrorContactNotFound
+		}
+		return domain.Contact{}, err
+	}
+
+	return c, nil
+}
+
+// ExistsPrimaryEmailWithContext reports whether email is the primary email of a
+// customer other than customerID.
+func (r *repository) ExistsPrimaryEmailWithContext(ctx context.Context, email string, customerID int) bool {
+	query := "SELECT contact_id FROM contacts WHERE deleted_at IS NULL and type=? and is_primary=TRUE and value=? and customer_id<>?;"
+	var id int
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, query, domain.ContactTypeEmail, email, customerID).Scan(&id)
+	return errors.Is(err, nil)
+}
+
+func (r *repository) SaveWithContext(ctx context.Context, c domain.Contact) (int, error) {
+	query := "INSERT INTO contacts (customer_id, type, label, value, is_primary, verified, created_at) VALUES (?, ?, ?, ?, ?, ?, ?);"
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, query, c.CustomerID, c.Type, c.Label, c.Value, c.Primary, c.Verified, c.CreatedAt)
+	if err != nil {
+		return 0, duplicateError(err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+func (r *repository) UpdateWithContext(ctx context.Context, c domain.Contact) error {
+	query := "UPDATE contacts SET type=?, label=?, value=?, is_primary=?, verified=?, updated_at=? WHERE customer_id=? and contact_id=? and deleted_at IS NULL;"
+	_, err := database.Conn(ctx, r.db).ExecContext(ctx, query, c.Type, c.Label, c.Value, c.Primary, c.Verified, c.UpdatedAt, c.CustomerID, c.ID)
+	return duplicateError(err)
+}
+
+func (r *repository) DeleteWithContext(ctx context.Context, customerID, id int) error {
+	query := "UPDATE contacts SET deleted_at=? WHERE customer_id=? and contact_id=? and deleted_at IS NULL;"
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, query, time.Now(), customerID, id)
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affect < 1 {
+		return ErrorContactNotFound
+	}
+
+	return nil
+}
+
+// ClearPrimaryWithContext unsets the primary flag of every contact of the given
+// type of a customer.
+func (r *repository) ClearPrimaryWithContext(ctx context.Context, customerID int, contactType string) error {
+	query := "UPDATE contacts SET is_primary=FALSE WHERE customer_id=? and type=? and is_primary=TRUE and deleted_at IS NULL;"
+	_, err := database.Conn(ctx, r.db).ExecContext(ctx, query, customerID, contactType)
+	return err
+}
+
+func (r *repository) queryContacts(ctx context.Context, query string, args ...any) ([]domain.Contact, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var contacts []domain.Contact
+
+	for rows.Next() {
+		c, err := scanContact(rows)
+		if err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, c)
+	}
+
+	return contacts, rows.Err()
+}
+
+// duplicateError reports a violation of the unique index on the primary emails,
+// the only one of the table, as ErrorPrimaryEmailAlreadyExist. It catches the
+// concurrent changes that ExistsPrimaryEmailWithContext cannot see.
+func duplicateError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+		return ErrorPrimaryEmailAlreadyExist
+	}
+	return err
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanContact(row scanner) (domain.Contact, error) {
+	c := domain.Contact{}
+	err := row.Scan(&c.ID, &c.CustomerID, &c.Type, &c.Label, &c.Value, &c.Primary, &c.Verified, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return domain.Contact{}, err
+	}
+	return c, nil
+}
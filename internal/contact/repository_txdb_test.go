@@ -0,0 +1,88 @@
+package contact
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuite_ContactRepository(t *testing.T) {
+	db, err := testutil.InitTxdbDatabase(t)
+	assert.NoError(t, err)
+	repository := NewRepository(db)
+	customers := customer.NewRepository(db)
+
+	testPrimaryEmailWithContext(t, repository, customers)
+	testListByEmailWithContext(t, repository, customers)
+
+	db.Close()
+}
+
+func saveCustomer(ctx context.Context, t *testing.T, customers customer.Repository, number int) int {
+	t.Helper()
+	id, err := customers.SaveWithContext(ctx, domain.Customer{
+		CustomerNumber: number,
+		FirstName:      "Danilo",
+		LastName:       "Sano",
+		CreatedAt:      time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	assert.NoError(t, err)
+	return id
+}
+
+func primaryEmail(customerID int, email string) domain.Contact {
+	return domain.Contact{
+		CustomerID: customerID,
+		Type:       domain.ContactTypeEmail,
+		Value:      email,
+		Primary:    true,
+		CreatedAt:  time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func testPrimaryEmailWithContext(t *testing.T, repository Repository, customers customer.Repository) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	first := saveCustomer(ctx, t, customers, 999981)
+	second := saveCustomer(ctx, t, customers, 999982)
+
+	id, err := repository.SaveWithContext(ctx, primaryEmail(first, "unique@example.com"))
+	assert.NoError(t, err)
+
+	assert.True(t, repository.ExistsPrimaryEmailWithContext(ctx, "unique@example.com", second))
+	assert.False(t, repository.ExistsPrimaryEmailWithContext(ctx, "unique@example.com", first))
+
+	_, err = repository.SaveWithContext(ctx, primaryEmail(second, "unique@example.com"))
+	assert.Equal(t, ErrorPrimaryEmailAlreadyExist, err)
+
+	assert.NoError(t, repository.DeleteWithContext(ctx, first, id))
+	_, err = repository.SaveWithContext(ctx, primaryEmail(second, "unique@example.com"))
+	assert.NoError(t, err)
+}
+
+func testListByEmailWithContext(t *testing.T, repository Repository, customers customer.Repository) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	customerID := saveCustomer(ctx, t, customers, 999983)
+	_, err := repository.SaveWithContext(ctx, primaryEmail(customerID, "lookup@example.com"))
+	assert.NoError(t, err)
+
+	result, total, err := customers.ListWithContext(ctx, dto.CustomerFilter{Email: "LookUp@Example.com", Page: 1, PageSize: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, customerID, result[0].ID)
+
+	assert.NoError(t, customers.DeleteWithContext(ctx, customerID))
+	contacts, err := repository.GetByCustomerIDWithContext(ctx, customerID)
+	assert.NoError(t, err)
+	assert.Empty(t, contacts)
+}
@@ -0,0 +1,214 @@
+package contact
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/database"
+)
+
+var (
+	ErrorContactNotFound          = errors.New("contact not found")
+	ErrorPrimaryEmailAlreadyExist = errors.New("primary email already belongs to another customer")
+)
+
+type Service interface {
+	Save(ctx context.Context, input dto.ContactRequest, customerID int) (domain.Contact, error)
+	GetAll(ctx context.Context, customerID int) ([]domain.Contact, error)
+	Get(ctx context.Context, customerID, id int) (domain.Contact, error)
+	Update(ctx context.Context, input dto.ContactRequest, customerID, id int) (domain.Contact, error)
+	Delete(ctx context.Context, customerID, id int) error
+	GetByCustomerIDs(ctx context.Context, customerIDs []int) (map[int][]domain.Contact, error)
+}
+
+type service struct {
+	repository Repository
+	customers  customer.Repository
+	transactor database.Transactor
+}
+
+type Option func(*service)
+
+// WithTransactor moves the primary flag of a customer's contacts in a transaction.
+func WithTransactor(t database.Transactor) Option {
+	return func(s *service) {
+		s.transactor = t
+	}
+}
+
+// NewService manages the contacts of the customers found in customers. The
+// contacts of a deleted customer are deleted along with it by the customer
+// repository.
+func NewService(r Repository, customers customer.Repository, opts ...Option) Service {
+	s := &service{
+		repository: r,
+		customers:  customers,
+		transactor: database.NoopTransactor(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *service) Save(ctx context.Context, input dto.ContactRequest, customerID int) (domain.Contact, error) {
+	if customerExist := s.customers.ExistsByIDWithContext(ctx, customerID); !customerExist {
+		return domain.Contact{}, customer.ErrorCustomerNotFound
+	}
+
+	c, err := newContact(input)
+	if err != nil {
+		return domain.Contact{}, err
+	}
+	c.CustomerID = customerID
+	c.CreatedAt = time.Now().Truncate(time.Second)
+
+	if err := s.checkPrimaryEmail(ctx, c); err != nil {
+		return domain.Contact{}, err
+	}
+
+	var result domain.Contact
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.clearPrimary(ctx, c); err != nil {
+			return err
+		}
+
+		id, err := s.repository.SaveWithContext(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		result, err = s.repository.GetWithContext(ctx, customerID, id)
+		return err
+	})
+	if err != nil {
+		return domain.Contact{}, err
+	}
+
+	return result, nil
+}
+
+func (s *service) GetAll(ctx context.Context, customerID int) ([]domain.Contact, error) {
+	if customerExist := s.customers.ExistsByIDWithContext(ctx, customerID); !customerExist {
+		return nil, customer.ErrorCustomerNotFound
+	}
+
+	return s.repository.GetByCustomerIDWithContext(ctx, customerID)
+}
+
+func (s *service) Get(ctx context.Context, customerID, id int) (domain.Contact, error) {
+	if customerExist := s.customers.ExistsByIDWithContext(ctx, customerID); !customerExist {
+		return domain.Contact{}, customer.ErrorCustomerNotFound
+	}
+
+	return s.repository.GetWithContext(ctx, customerID, id)
+}
+
+func (s *service) Update(ctx context.Context, input dto.ContactRequest, customerID, id int) (domain.Contact, error) {
+	if customerExist := s.customers.ExistsByIDWithContext(ctx, customerID); !customerExist {
+		return domain.Contact{}, customer.ErrorCustomerNotFound
+	}
+
+	c, err := newContact(input)
+	if err != nil {
+		return domain.Contact{}, err
+	}
+	c.ID = id
+	c.CustomerID = customerID
+
+	if err := s.checkPrimaryEmail(ctx, c); err != nil {
+		return domain.Contact{}, err
+	}
+
+	var result domain.Contact
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		current, err := s.repository.GetWithContext(ctx, customerID, id)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().Truncate(time.Second)
+		c.CreatedAt = current.CreatedAt
+		c.UpdatedAt = &now
+
+		if err := s.clearPrimary(ctx, c); err != nil {
+			return err
+		}
+
+		if err := s.repository.UpdateWithContext(ctx, c); err != nil {
+			return err
+		}
+
+		result, err = s.repository.GetWithContext(ctx, customerID, id)
+		return err
+	})
+	if err != nil {
+		return domain.Contact{}, err
+	}
+
+	return result, nil
+}
+
+func (s *service) Delete(ctx context.Context, customerID, id int) error {
+	if customerExist := s.customers.ExistsByIDWithContext(ctx, customerID); !customerExist {
+		return customer.ErrorCustomerNotFound
+	}
+
+	return s.repository.DeleteWithContext(ctx, customerID, id)
+}
+
+// GetByCustomerIDs returns the contacts of several customers, keyed by customer
+// ID. Customers without contacts are left out.
+func (s *service) GetByCustomerIDs(ctx context.Context, customerIDs []int) (map[int][]domain.Contact, error) {
+	contacts, err := s.repository.GetByCustomerIDsWithContext(ctx, customerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byCustomer := make(map[int][]domain.Contact)
+	for _, c := range contacts {
+		byCustomer[c.CustomerID] = append(byCustomer[c.CustomerID], c)
+	}
+	return byCustomer, nil
+}
+
+// checkPrimaryEmail rejects a primary email that is already the primary email of
+// another customer.
+func (s *service) checkPrimaryEmail(ctx context.Context, c domain.Contact) error {
+	if c.Type != domain.ContactTypeEmail || !c.Primary {
+		return nil
+	}
+
+	if exists := s.repository.ExistsPrimaryEmailWithContext(ctx, c.Value, c.CustomerID); exists {
+		return ErrorPrimaryEmailAlreadyExist
+	}
+	return nil
+}
+
+// clearPrimary unsets the previous primary contact of the type of c when c
+// becomes the primary one.
+func (s *service) clearPrimary(ctx context.Context, c domain.Contact) error {
+	if !c.Primary {
+		return nil
+	}
+	return s.repository.ClearPrimaryWithContext(ctx, c.CustomerID, c.Type)
+}
+
+func newContact(input dto.ContactRequest) (domain.Contact, error) {
+	value, err := Normalize(input.Type, input.Value, input.Region)
+	if err != nil {
+		return domain.Contact{}, err
+	}
+
+	return domain.Contact{
+		Type:     input.Type,
+		Label:    input.Label,
+		Value:    value,
+		Primary:  input.Primary,
+		Verified: input.Verified,
+	}, nil
+}
@@ -0,0 +1,124 @@
+package contact
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	mocks "github.com/danilosano/web-golang-api/pkg/tests/contacts"
+	customerMocks "github.com/danilosano/web-golang-api/pkg/tests/customers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func createService(t *testing.T) (Service, *mocks.ContactRepositoryMock, *customerMocks.CustomersRepositoryMock, context.Context) {
+	t.Helper()
+	repoMock := new(mocks.ContactRepositoryMock)
+	customersMock := new(customerMocks.CustomersRepositoryMock)
+	return NewService(repoMock, customersMock), repoMock, customersMock, context.Background()
+}
+
+var (
+	input = dto.ContactRequest{
+		Type:    domain.ContactTypeEmail,
+		Label:   "work",
+		Value:   "Danilo@Example.com",
+		Primary: true,
+	}
+
+	mockedContact = domain.Contact{
+		ID:         3,
+		CustomerID: 1,
+		Type:       domain.ContactTypeEmail,
+		Label:      "work",
+		Value:      "danilo@example.com",
+		Primary:    true,
+		CreatedAt:  time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+)
+
+func TestSave(t *testing.T) {
+	t.Run("A primary email is normalized and replaces the previous primary email.", func(t *testing.T) {
+		service, repoMock, customersMock, ctx := createService(t)
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("ExistsPrimaryEmailWithContext", ctx, "danilo@example.com", 1).Return(false)
+		repoMock.On("ClearPrimaryWithContext", ctx, 1, domain.ContactTypeEmail).Return(nil)
+		repoMock.On("SaveWithContext", ctx, mock.MatchedBy(func(c domain.Contact) bool {
+			return c.Value == "danilo@example.com" && c.CustomerID == 1 && c.Primary
+		})).Return(3, nil)
+		repoMock.On("GetWithContext", ctx, 1, 3).Return(mockedContact, nil)
+
+		result, err := service.Save(ctx, input, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, mockedContact, result)
+	})
+
+	t.Run("A phone is stored in E.164 format.", func(t *testing.T) {
+		service, repoMock, customersMock, ctx := createService(t)
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("SaveWithContext", ctx, mock.MatchedBy(func(c domain.Contact) bool {
+			return c.Value == "+5511987654321"
+		})).Return(4, nil)
+		repoMock.On("GetWithContext", ctx, 1, 4).Return(domain.Contact{ID: 4}, nil)
+
+		_, err := service.Save(ctx, dto.ContactRequest{Type: domain.ContactTypePhone, Value: "11 98765 4321", Region: "BR"}, 1)
+		assert.Nil(t, err)
+		repoMock.AssertNotCalled(t, "ExistsPrimaryEmailWithContext", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("When the primary email belongs to another customer, return the conflict error.", func(t *testing.T) {
+		service, repoMock, customersMock, ctx := createService(t)
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("ExistsPrimaryEmailWithContext", ctx, "danilo@example.com", 1).Return(true)
+
+		_, err := service.Save(ctx, input, 1)
+		assert.Equal(t, ErrorPrimaryEmailAlreadyExist, err)
+		repoMock.AssertNotCalled(t, "SaveWithContext", mock.Anything, mock.Anything)
+	})
+
+	t.Run("When the email is invalid, return the validation error.", func(t *testing.T) {
+		service, _, customersMock, ctx := createService(t)
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+
+		_, err := service.Save(ctx, dto.ContactRequest{Type: domain.ContactTypeEmail, Value: "danilo"}, 1)
+		assert.Equal(t, ErrorInvalidEmail, err)
+	})
+
+	t.Run("When the customer does not exist, return the not found error.", func(t *testing.T) {
+		service, _, customersMock, ctx := createService(t)
+		customersMock.On("ExistsByIDWithContext", ctx, 9).Return(false)
+
+		_, err := service.Save(ctx, input, 9)
+		assert.Equal(t, customer.ErrorCustomerNotFound, err)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	t.Run("The contact keeps its creation date and gets an update date.", func(t *testing.T) {
+		service, repoMock, customersMock, ctx := createService(t)
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("ExistsPrimaryEmailWithContext", ctx, "danilo@example.com", 1).Return(false)
+		repoMock.On("GetWithContext", ctx, 1, 3).Return(mockedContact, nil)
+		repoMock.On("ClearPrimaryWithContext", ctx, 1, domain.ContactTypeEmail).Return(nil)
+		repoMock.On("UpdateWithContext", ctx, mock.MatchedBy(func(c domain.Contact) bool {
+			return c.ID == 3 && c.CreatedAt.Equal(mockedContact.CreatedAt) && c.UpdatedAt != nil
+		})).Return(nil)
+
+		_, err := service.Update(ctx, input, 1, 3)
+		assert.Nil(t, err)
+		repoMock.AssertNumberOfCalls(t, "UpdateWithContext", 1)
+	})
+
+	t.Run("When the contact does not exist, return the not found error.", func(t *testing.T) {
+		service, repoMock, customersMock, ctx := createService(t)
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("ExistsPrimaryEmailWithContext", ctx, "danilo@example.com", 1).Return(false)
+		repoMock.On("GetWithContext", ctx, 1, 4).Return(nil, ErrorContactNotFound)
+
+		_, err := service.Update(ctx, input, 1, 4)
+		assert.Equal(t, ErrorContactNotFound, err)
+	})
+}
@@ -0,0 +1,136 @@
+package customer
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/cache"
+	"github.com/danilosano/web-golang-api/pkg/database"
+)
+
+// CachedRepository is a read-through cache of customers by ID in front of a Repository.
+// Entries are invalidated whenever a customer is saved, updated, deleted, merged or changes status.
+type CachedRepository struct {
+	Repository
+	cache  cache.Cache
+	ttl    time.Duration
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func NewCachedRepository(r Repository, c cache.Cache, ttl time.Duration) *CachedRepository {
+	return &CachedRepository{
+		Repository: r,
+		cache:      c,
+		ttl:        ttl,
+	}
+}
+
+func (r *CachedRepository) Stats() cache.Stats {
+	return cache.Stats{Hits: r.hits.Load(), Misses: r.misses.Load()}
+}
+
+func (r *CachedRepository) GetWithContext(ctx context.Context, id int) (dto.ResultCustomerRequest, error) {
+	if c, ok := r.lookup(ctx, id); ok {
+		return c, nil
+	}
+
+	c, err := r.Repository.GetWithContext(ctx, id)
+	if err != nil {
+		return dto.ResultCustomerRequest{}, err
+	}
+
+	// Rows read inside a transaction may be rolled back, so they are not cached.
+	if !database.InTransaction(ctx) {
+		r.store(ctx, c)
+	}
+	return c, nil
+}
+
+// ExistsByIDWithContext answers from the cache when possible and otherwise loads
+// the whole customer, so that the Get that usually follows is a cache hit.
+func (r *CachedRepository) ExistsByIDWithContext(ctx context.Context, id int) bool {
+	_, err := r.GetWithContext(ctx, id)
+	return err == nil
+}
+
+func (r *CachedRepository) SaveWithContext(ctx context.Context, c domain.Customer) (int, error) {
+	id, err := r.Repository.SaveWithContext(ctx, c)
+	if err != nil {
+		return 0, err
+	}
+
+	r.invalidate(ctx, id)
+	return id, nil
+}
+
+func (r *CachedRepository) UpdateWithContext(ctx context.Context, c domain.Customer) error {
+	defer r.invalidate(ctx, c.ID)
+	return r.Repository.UpdateWithContext(ctx, c)
+}
+
+func (r *CachedRepository) DeleteWithContext(ctx context.Context, id int) error {
+	defer r.invalidate(ctx, id)
+	return r.Repository.DeleteWithContext(ctx, id)
+}
+
+func (r *CachedRepository) UpdateStatusWithContext(ctx context.Context, id int, from, to string, updatedAt time.Time) error {
+	defer r.invalidate(ctx, id)
+	return r.Repository.UpdateStatusWithContext(ctx, id, from, to, updatedAt)
+}
+
+// MergeWithContext also forgets the subsidiaries of the source, which move to the
+// target.
+func (r *CachedRepository) MergeWithContext(ctx context.Context, sourceID, targetID int) error {
+	children, err := r.Repository.GetChildrenWithContext(ctx, sourceID)
+	if err != nil {
+		return err
+	}
+	for _, c := range children {
+		defer r.invalidate(ctx, c.ID)
+	}
+
+	defer r.invalidate(ctx, targetID)
+	defer r.invalidate(ctx, sourceID)
+	return r.Repository.MergeWithContext(ctx, sourceID, targetID)
+}
+
+func (r *CachedRepository) lookup(ctx context.Context, id int) (dto.ResultCustomerRequest, bool) {
+	var c dto.ResultCustomerRequest
+
+	data, ok := r.cache.Get(ctx, cacheKey(id))
+	if !ok || json.Unmarshal(data, &c) != nil {
+		r.misses.Add(1)
+		return dto.ResultCustomerRequest{}, false
+	}
+
+	r.hits.Add(1)
+	return c, true
+}
+
+func (r *CachedRepository) store(ctx context.Context, c dto.ResultCustomerRequest) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return
+	}
+	r.cache.Set(ctx, cacheKey(c.ID), data, r.ttl)
+}
+
+// invalidate drops the entry of a customer, and drops it again once the
+// transaction carried by ctx commits, as a concurrent read may have cached the
+// row as it was before the commit in the meantime.
+func (r *CachedRepository) invalidate(ctx context.Context, id int) {
+	r.cache.Delete(ctx, cacheKey(id))
+	database.AfterCommit(ctx, func() {
+		r.cache.Delete(context.WithoutCancel(ctx), cacheKey(id))
+	})
+}
+
+func cacheKey(id int) string {
+	return "customer:" + strconv.Itoa(id)
+}
@@ -0,0 +1,84 @@
+package customer
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/cache"
+	"github.com/danilosano/web-golang-api/pkg/database"
+	mocks "github.com/danilosano/web-golang-api/pkg/tests/customers"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func createCachedRepository(t *testing.T) (*CachedRepository, *mocks.CustomersRepositoryMock, context.Context) {
+	t.Helper()
+	repoMock := new(mocks.CustomersRepositoryMock)
+	repository := NewCachedRepository(repoMock, cache.NewLRU(10), time.Minute)
+	return repository, repoMock, context.Background()
+}
+
+func TestCachedRepository(t *testing.T) {
+	t.Run("A customer is read from the repository once and then served from the cache.", func(t *testing.T) {
+		repository, repoMock, ctx := createCachedRepository(t)
+		repoMock.On("GetWithContext", ctx, 1).Return(mockedResultCustomer, nil).Once()
+
+		assert.True(t, repository.ExistsByIDWithContext(ctx, 1))
+		result, err := repository.GetWithContext(ctx, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, mockedResultCustomer, result)
+		assert.Equal(t, cache.Stats{Hits: 1, Misses: 1}, repository.Stats())
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("A missing customer is not cached.", func(t *testing.T) {
+		repository, repoMock, ctx := createCachedRepository(t)
+		repoMock.On("GetWithContext", ctx, 1).Return(dto.ResultCustomerRequest{}, sql.ErrNoRows).Twice()
+
+		assert.False(t, repository.ExistsByIDWithContext(ctx, 1))
+		assert.False(t, repository.ExistsByIDWithContext(ctx, 1))
+		assert.Equal(t, cache.Stats{Misses: 2}, repository.Stats())
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("Updating or deleting a customer invalidates its cache entry.", func(t *testing.T) {
+		repository, repoMock, ctx := createCachedRepository(t)
+		repoMock.On("GetWithContext", ctx, 1).Return(mockedResultCustomer, nil).Twice()
+		repoMock.On("UpdateWithContext", ctx, domain.Customer{ID: 1}).Return(nil)
+		repoMock.On("DeleteWithContext", ctx, 1).Return(nil)
+
+		_, _ = repository.GetWithContext(ctx, 1)
+		assert.NoError(t, repository.UpdateWithContext(ctx, domain.Customer{ID: 1}))
+		_, _ = repository.GetWithContext(ctx, 1)
+		assert.NoError(t, repository.DeleteWithContext(ctx, 1))
+
+		repoMock.On("GetWithContext", ctx, 1).Return(dto.ResultCustomerRequest{}, sql.ErrNoRows).Once()
+		assert.False(t, repository.ExistsByIDWithContext(ctx, 1))
+		repoMock.AssertExpectations(t)
+	})
+	t.Run("A customer read by another request before the change commits is invalidated again on commit.", func(t *testing.T) {
+		repository, repoMock, ctx := createCachedRepository(t)
+		transactor := database.NewTransactor(testutil.NopDB(t))
+		repoMock.On("GetWithContext", ctx, 1).Return(mockedResultCustomer, nil).Twice()
+		repoMock.On("UpdateWithContext", mock.Anything, domain.Customer{ID: 1}).Return(nil)
+
+		err := transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+			if err := repository.UpdateWithContext(txCtx, domain.Customer{ID: 1}); err != nil {
+				return err
+			}
+			_, _ = repository.GetWithContext(ctx, 1)
+			return nil
+		})
+		assert.NoError(t, err)
+
+		_, _ = repository.GetWithContext(ctx, 1)
+		assert.Equal(t, cache.Stats{Misses: 2}, repository.Stats())
+		repoMock.AssertExpectations(t)
+	})
+}
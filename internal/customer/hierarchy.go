@@ -0,0 +1,132 @@
+package customer
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+)
+
+// maxHierarchyDepth bounds the levels walked up or down a customer hierarchy.
+const maxHierarchyDepth = 100
+
+var (
+	ErrorParentNotFound = errors.New("parent customer not found")
+	ErrorHierarchyCycle = errors.New("a customer cannot be a subsidiary of itself or of its subsidiaries")
+)
+
+// validateParent checks that parentID may become the parent of the customer id,
+// 0 for a customer yet to be created: the parent must exist and must not be the
+// customer itself or one of its descendants.
+func (s *service) validateParent(ctx context.Context, id int, parentID *int) error {
+	if parentID == nil {
+		return nil
+	}
+
+	if *parentID == id {
+		return ErrorHierarchyCycle
+	}
+
+	if parentExist := s.repository.ExistsByIDWithContext(ctx, *parentID); !parentExist {
+		return ErrorParentNotFound
+	}
+
+	if id == 0 {
+		return nil
+	}
+
+	return s.notAncestor(ctx, id, *parentID)
+}
+
+// notAncestor returns ErrorHierarchyCycle when the customer ancestorID is found up
+// the hierarchy of the customer id.
+func (s *service) notAncestor(ctx context.Context, ancestorID, id int) error {
+	ancestors, err := s.repository.GetAncestorsWithContext(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range ancestors {
+		if a.ID == ancestorID {
+			return ErrorHierarchyCycle
+		}
+	}
+	return nil
+}
+
+// lockParent checks again, within the transaction carried by ctx, that parentID
+// may become the parent of the customer id. It locks the parent and its
+// ancestors, so that neither a concurrent move of the parent under the customer
+// nor its deletion can happen before the commit.
+func (s *service) lockParent(ctx context.Context, id int, parentID *int) error {
+	if parentID == nil {
+		return nil
+	}
+
+	hierarchy, err := s.repository.LockHierarchyWithContext(ctx, *parentID)
+	if err != nil {
+		return err
+	}
+
+	if len(hierarchy) == 0 {
+		return ErrorParentNotFound
+	}
+	if slices.Contains(hierarchy, id) {
+		return ErrorHierarchyCycle
+	}
+	return nil
+}
+
+// Children returns the direct subsidiaries of a customer.
+func (s *service) Children(ctx context.Context, id int) ([]dto.ResultCustomerRequest, error) {
+	if customerExist := s.repository.ExistsByIDWithContext(ctx, id); !customerExist {
+		return nil, s.notFound(ctx, id)
+	}
+
+	return s.repository.GetChildrenWithContext(ctx, id)
+}
+
+// Ancestors returns the parent of a customer, its parent in turn and so on, up to
+// the top of the hierarchy.
+func (s *service) Ancestors(ctx context.Context, id int) ([]dto.ResultCustomerRequest, error) {
+	if customerExist := s.repository.ExistsByIDWithContext(ctx, id); !customerExist {
+		return nil, s.notFound(ctx, id)
+	}
+
+	return s.repository.GetAncestorsWithContext(ctx, id)
+}
+
+// Tree returns a customer along with all of its subsidiaries, nested.
+func (s *service) Tree(ctx context.Context, id int) (dto.CustomerTree, error) {
+	customers, err := s.repository.GetTreeWithContext(ctx, id)
+	if err != nil {
+		return dto.CustomerTree{}, err
+	}
+
+	if len(customers) == 0 {
+		return dto.CustomerTree{}, s.notFound(ctx, id)
+	}
+
+	children := map[int][]dto.ResultCustomerRequest{}
+	for _, c := range customers[1:] {
+		children[*c.ParentID] = append(children[*c.ParentID], c)
+	}
+
+	return buildTree(customers[0], children, map[int]bool{}), nil
+}
+
+// buildTree nests the children under c, skipping the customers already placed so
+// that a cycle in the stored hierarchy, which GetTreeWithContext walks until its
+// depth limit, cannot recurse forever.
+func buildTree(c dto.ResultCustomerRequest, children map[int][]dto.ResultCustomerRequest, placed map[int]bool) dto.CustomerTree {
+	placed[c.ID] = true
+	node := dto.CustomerTree{Customer: c}
+	for _, child := range children[c.ID] {
+		if placed[child.ID] {
+			continue
+		}
+		node.Children = append(node.Children, buildTree(child, children, placed))
+	}
+	return node
+}
@@ -0,0 +1,200 @@
+package customer
+
+import (
+	"testing"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func subsidiary(id, parentID int, name string) dto.ResultCustomerRequest {
+	c := customerNamed(id, name, "Ltd")
+	c.ParentID = &parentID
+	return c
+}
+
+func TestParent(t *testing.T) {
+	number := 10
+	parentID := func(id int) *int { return &id }
+
+	t.Run("A customer is created as a subsidiary of an existing customer.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("ExistsByIDWithContext", ctx, 3).Return(true)
+		repoMock.On("ExistsByCustomerNumberWithContext", ctx, number).Return(false)
+		repoMock.On("LockHierarchyWithContext", ctx, 3).Return([]int{3}, nil)
+		repoMock.On("SaveWithContext", ctx, mock.MatchedBy(func(c domain.Customer) bool {
+			return c.ParentID != nil && *c.ParentID == 3
+		})).Return(7, nil)
+		repoMock.On("GetWithContext", ctx, 7).Return(subsidiary(7, 3, "Acme"), nil)
+
+		result, err := service.Save(ctx, dto.CreateCustomerRequest{CustomerNumber: &number, FirstName: "Acme", LastName: "Ltd", ParentID: parentID(3)})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, *result.ParentID)
+	})
+
+	t.Run("When the parent does not exist, the customer is not created.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("ExistsByIDWithContext", ctx, 3).Return(false)
+
+		_, err := service.Save(ctx, dto.CreateCustomerRequest{CustomerNumber: &number, FirstName: "Acme", LastName: "Ltd", ParentID: parentID(3)})
+		assert.Equal(t, ErrorParentNotFound, err)
+		repoMock.AssertNotCalled(t, "SaveWithContext", mock.Anything, mock.Anything)
+	})
+
+	t.Run("When the parent is deleted before the customer is stored, the customer is not created.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("ExistsByIDWithContext", ctx, 3).Return(true)
+		repoMock.On("ExistsByCustomerNumberWithContext", ctx, number).Return(false)
+		repoMock.On("LockHierarchyWithContext", ctx, 3).Return([]int{}, nil)
+
+		_, err := service.Save(ctx, dto.CreateCustomerRequest{CustomerNumber: &number, FirstName: "Acme", LastName: "Ltd", ParentID: parentID(3)})
+		assert.Equal(t, ErrorParentNotFound, err)
+		repoMock.AssertNotCalled(t, "SaveWithContext", mock.Anything, mock.Anything)
+	})
+
+	t.Run("A customer moves under another parent when no cycle results.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("ExistsByIDWithContext", ctx, mock.Anything).Return(true)
+		repoMock.On("ExistsByCustomerNumberAndIDWithContext", ctx, 1, number).Return(true)
+		repoMock.On("GetAncestorsWithContext", ctx, 3).Return([]dto.ResultCustomerRequest{customerNamed(2, "Acme", "Holding")}, nil)
+		repoMock.On("LockHierarchyWithContext", ctx, 3).Return([]int{3, 2}, nil)
+		repoMock.On("UpdateWithContext", ctx, mock.MatchedBy(func(c domain.Customer) bool {
+			return c.ID == 1 && c.ParentID != nil && *c.ParentID == 3
+		})).Return(nil)
+		repoMock.On("GetWithContext", ctx, 1).Return(subsidiary(1, 3, "Acme"), nil)
+
+		result, err := service.Update(ctx, dto.UpdateCustomerRequest{CustomerNumber: &number, FirstName: "Acme", LastName: "Ltd", ParentID: parentID(3)}, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, *result.ParentID)
+	})
+
+	t.Run("A customer cannot be its own parent.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("ExistsByCustomerNumberAndIDWithContext", ctx, 1, number).Return(true)
+
+		_, err := service.Update(ctx, dto.UpdateCustomerRequest{CustomerNumber: &number, FirstName: "Acme", LastName: "Ltd", ParentID: parentID(1)}, 1)
+		assert.Equal(t, ErrorHierarchyCycle, err)
+		repoMock.AssertNotCalled(t, "UpdateWithContext", mock.Anything, mock.Anything)
+	})
+
+	t.Run("A customer cannot become a subsidiary of one of its subsidiaries.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("ExistsByIDWithContext", ctx, mock.Anything).Return(true)
+		repoMock.On("ExistsByCustomerNumberAndIDWithContext", ctx, 1, number).Return(true)
+		repoMock.On("GetAncestorsWithContext", ctx, 3).Return([]dto.ResultCustomerRequest{subsidiary(2, 1, "Acme"), customerNamed(1, "Acme", "Holding")}, nil)
+
+		_, err := service.Update(ctx, dto.UpdateCustomerRequest{CustomerNumber: &number, FirstName: "Acme", LastName: "Ltd", ParentID: parentID(3)}, 1)
+		assert.Equal(t, ErrorHierarchyCycle, err)
+		repoMock.AssertNotCalled(t, "UpdateWithContext", mock.Anything, mock.Anything)
+	})
+
+	t.Run("When the parent was moved under the customer concurrently, the cycle is found once the hierarchy is locked.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("ExistsByIDWithContext", ctx, mock.Anything).Return(true)
+		repoMock.On("ExistsByCustomerNumberAndIDWithContext", ctx, 1, number).Return(true)
+		repoMock.On("GetAncestorsWithContext", ctx, 3).Return([]dto.ResultCustomerRequest{}, nil)
+		repoMock.On("LockHierarchyWithContext", ctx, 3).Return([]int{3, 1}, nil)
+
+		_, err := service.Update(ctx, dto.UpdateCustomerRequest{CustomerNumber: &number, FirstName: "Acme", LastName: "Ltd", ParentID: parentID(3)}, 1)
+		assert.Equal(t, ErrorHierarchyCycle, err)
+		repoMock.AssertNotCalled(t, "UpdateWithContext", mock.Anything, mock.Anything)
+	})
+}
+
+func TestChildren(t *testing.T) {
+	t.Run("The direct subsidiaries of the customer are returned.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		children := []dto.ResultCustomerRequest{subsidiary(2, 1, "Acme"), subsidiary(3, 1, "Globex")}
+		repoMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("GetChildrenWithContext", ctx, 1).Return(children, nil)
+
+		result, err := service.Children(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, children, result)
+	})
+
+	t.Run("When the customer does not exist, an error will be returned.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("ExistsByIDWithContext", ctx, 1).Return(false)
+		repoMock.On("GetMergedIntoWithContext", ctx, 1).Return(0, ErrorCustomerNotFound)
+
+		_, err := service.Children(ctx, 1)
+		assert.Equal(t, ErrorCustomerNotFound, err)
+	})
+}
+
+func TestAncestors(t *testing.T) {
+	t.Run("The ancestors of the customer are returned, nearest first.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		ancestors := []dto.ResultCustomerRequest{subsidiary(2, 1, "Acme"), customerNamed(1, "Acme", "Holding")}
+		repoMock.On("ExistsByIDWithContext", ctx, 3).Return(true)
+		repoMock.On("GetAncestorsWithContext", ctx, 3).Return(ancestors, nil)
+
+		result, err := service.Ancestors(ctx, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, ancestors, result)
+	})
+
+	t.Run("When the customer does not exist, an error will be returned.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("ExistsByIDWithContext", ctx, 3).Return(false)
+		repoMock.On("GetMergedIntoWithContext", ctx, 3).Return(0, ErrorCustomerNotFound)
+
+		_, err := service.Ancestors(ctx, 3)
+		assert.Equal(t, ErrorCustomerNotFound, err)
+	})
+}
+
+func TestTree(t *testing.T) {
+	t.Run("The customers of the hierarchy are nested under their parents.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		root := customerNamed(1, "Acme", "Holding")
+		repoMock.On("GetTreeWithContext", ctx, 1).Return([]dto.ResultCustomerRequest{
+			root,
+			subsidiary(2, 1, "Acme"),
+			subsidiary(3, 1, "Globex"),
+			subsidiary(4, 2, "Initech"),
+		}, nil)
+
+		tree, err := service.Tree(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, dto.CustomerTree{
+			Customer: root,
+			Children: []dto.CustomerTree{
+				{Customer: subsidiary(2, 1, "Acme"), Children: []dto.CustomerTree{{Customer: subsidiary(4, 2, "Initech")}}},
+				{Customer: subsidiary(3, 1, "Globex")},
+			},
+		}, tree)
+	})
+
+	t.Run("A cycle in the stored hierarchy does not nest the customers forever.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		root := subsidiary(1, 2, "Acme")
+		// The tree walks the cycle 1 -> 2 -> 1 until its depth limit.
+		repoMock.On("GetTreeWithContext", ctx, 1).Return([]dto.ResultCustomerRequest{
+			root,
+			subsidiary(2, 1, "Globex"),
+			root,
+			subsidiary(2, 1, "Globex"),
+		}, nil)
+
+		tree, err := service.Tree(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, dto.CustomerTree{
+			Customer: root,
+			Children: []dto.CustomerTree{{Customer: subsidiary(2, 1, "Globex")}},
+		}, tree)
+	})
+
+	t.Run("When the customer does not exist, an error will be returned.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("GetTreeWithContext", ctx, 1).Return([]dto.ResultCustomerRequest{}, nil)
+		repoMock.On("GetMergedIntoWithContext", ctx, 1).Return(0, ErrorCustomerNotFound)
+
+		_, err := service.Tree(ctx, 1)
+		assert.Equal(t, ErrorCustomerNotFound, err)
+	})
+}
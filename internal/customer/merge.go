@@ -0,0 +1,252 @@
+package customer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// DuplicateNameThreshold is the name similarity from which a customer is
+	// reported as a duplicate candidate without sharing any contact.
+	DuplicateNameThreshold = 0.93
+	// duplicateSearchLimit bounds the customers whose names are compared.
+	duplicateSearchLimit = 50
+)
+
+var ErrorMergeSameCustomer = errors.New("a customer cannot be merged into itself")
+
+// MergedError is returned when looking up a customer merged into another one. It
+// matches ErrorCustomerNotFound for the callers not following the merge.
+type MergedError struct {
+	TargetID int
+}
+
+func (e *MergedError) Error() string {
+	return fmt.Sprintf("customer merged into customer %d", e.TargetID)
+}
+
+func (e *MergedError) Is(target error) bool {
+	return target == ErrorCustomerNotFound
+}
+
+// notFound returns the error reporting a missing customer, a *MergedError when it
+// was merged into another one.
+func (s *service) notFound(ctx context.Context, id int) error {
+	if targetID, err := s.repository.GetMergedIntoWithContext(ctx, id); err == nil {
+		return &MergedError{TargetID: targetID}
+	}
+	return ErrorCustomerNotFound
+}
+
+// FindDuplicates returns the customers that may be the same person as the given
+// one: those sharing a contact value with it, first, and those with a similar
+// name.
+func (s *service) FindDuplicates(ctx context.Context, id int) ([]dto.DuplicateCandidate, error) {
+	if customerExist := s.repository.ExistsByIDWithContext(ctx, id); !customerExist {
+		return nil, s.notFound(ctx, id)
+	}
+
+	c, err := s.repository.GetWithContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	name := normalizeName(c.FirstName + " " + c.LastName)
+
+	shared, err := s.repository.GetSharedContactsWithContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := s.repository.GetByNameMatchWithContext(ctx, c.FirstName+" "+c.LastName, id, duplicateSearchLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []dto.DuplicateCandidate
+	for _, m := range matches {
+		similarity := nameSimilarity(name, normalizeName(m.FirstName+" "+m.LastName))
+		if similarity < DuplicateNameThreshold && len(shared[m.ID]) == 0 {
+			continue
+		}
+		candidates = append(candidates, dto.DuplicateCandidate{Customer: m, NameSimilarity: similarity, SharedContacts: shared[m.ID]})
+		delete(shared, m.ID)
+	}
+
+	for otherID, values := range shared {
+		other, err := s.repository.GetWithContext(ctx, otherID)
+		if err != nil {
+			return nil, err
+		}
+		similarity := nameSimilarity(name, normalizeName(other.FirstName+" "+other.LastName))
+		candidates = append(candidates, dto.DuplicateCandidate{Customer: other, NameSimilarity: similarity, SharedContacts: values})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if len(a.SharedContacts) != len(b.SharedContacts) {
+			return len(a.SharedContacts) > len(b.SharedContacts)
+		}
+		if a.NameSimilarity != b.NameSimilarity {
+			return a.NameSimilarity > b.NameSimilarity
+		}
+		return a.Customer.ID < b.Customer.ID
+	})
+
+	return candidates, nil
+}
+
+// Merge moves the addresses, contacts, notes, attachments, tags and subsidiaries
+// of the source customer to the target one, then deletes the source, which keeps pointing to
+// the target. Both customers record the merge in their audit trail, and each
+// subsidiary moved records its update.
+func (s *service) Merge(ctx context.Context, input dto.MergeRequest) (dto.ResultCustomerRequest, error) {
+	if input.SourceID == input.TargetID {
+		return dto.ResultCustomerRequest{}, ErrorMergeSameCustomer
+	}
+
+	var target dto.ResultCustomerRequest
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, id := range []int{input.SourceID, input.TargetID} {
+			if customerExist := s.repository.ExistsByIDWithContext(ctx, id); !customerExist {
+				return s.notFound(ctx, id)
+			}
+		}
+
+		// The subsidiaries of the source move to the target, which must not be one of them.
+		if err := s.lockParent(ctx, input.SourceID, &input.TargetID); err != nil {
+			return err
+		}
+
+		source, err := s.repository.GetWithContext(ctx, input.SourceID)
+		if err != nil {
+			return err
+		}
+
+		children, err := s.repository.GetChildrenWithContext(ctx, input.SourceID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.repository.MergeWithContext(ctx, input.SourceID, input.TargetID); err != nil {
+			return err
+		}
+
+		target, err = s.repository.GetWithContext(ctx, input.TargetID)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range []int{input.SourceID, input.TargetID} {
+			if err := s.record(ctx, domain.AuditActionMerge, id, source, target); err != nil {
+				return err
+			}
+		}
+
+		if err := s.emit(ctx, domain.EventCustomerDeleted, input.SourceID, source); err != nil {
+			return err
+		}
+		if err := s.emit(ctx, domain.EventCustomerUpdated, input.TargetID, target); err != nil {
+			return err
+		}
+
+		for _, before := range children {
+			after, err := s.repository.GetWithContext(ctx, before.ID)
+			if err != nil {
+				return err
+			}
+			if err := s.record(ctx, domain.AuditActionUpdate, before.ID, before, after); err != nil {
+				return err
+			}
+			if err := s.emit(ctx, domain.EventCustomerUpdated, before.ID, after); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return dto.ResultCustomerRequest{}, err
+	}
+
+	return target, nil
+}
+
+// normalizeName lowers the case of a name, strips its accents and punctuation and
+// sorts its words, so that "Sano, Danilo" and "danilo sanó" compare equal.
+func normalizeName(name string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(name) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(' ')
+		}
+	}
+
+	words := strings.Fields(b.String())
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+// nameSimilarity returns the Jaro-Winkler similarity of two names, from 0 for
+// names sharing no letter to 1 for equal names.
+func nameSimilarity(a, b string) float64 {
+	s1, s2 := []rune(a), []rune(b)
+	if len(s1) == 0 || len(s2) == 0 {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	window := max(len(s1), len(s2))/2 - 1
+	window = max(window, 0)
+	matched1 := make([]bool, len(s1))
+	matched2 := make([]bool, len(s2))
+
+	matches := 0
+	for i := range s1 {
+		for j := max(0, i-window); j < min(len(s2), i+window+1); j++ {
+			if !matched2[j] && s1[i] == s2[j] {
+				matched1[i], matched2[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions, j := 0, 0
+	for i := range s1 {
+		if !matched1[i] {
+			continue
+		}
+		for !matched2[j] {
+			j++
+		}
+		if s1[i] != s2[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(s1)) + m/float64(len(s2)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < min(4, len(s1), len(s2)) && s1[prefix] == s2[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
@@ -0,0 +1,165 @@
+package customer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/reqctx"
+	auditMocks "github.com/danilosano/web-golang-api/pkg/tests/audit"
+	mocks "github.com/danilosano/web-golang-api/pkg/tests/customers"
+	outboxMocks "github.com/danilosano/web-golang-api/pkg/tests/outbox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func customerNamed(id int, firstName, lastName string) dto.ResultCustomerRequest {
+	return dto.ResultCustomerRequest{ID: id, FirstName: firstName, LastName: lastName}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	t.Run("The customers sharing contacts come first, then those with similar names.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("GetWithContext", ctx, 1).Return(customerNamed(1, "Danilo", "Sano"), nil)
+		repoMock.On("GetSharedContactsWithContext", ctx, 1).Return(map[int][]string{
+			4: {"danilo@example.com"},
+			9: {"+5511999990000", "danilo@example.com"},
+		}, nil)
+		repoMock.On("GetByNameMatchWithContext", ctx, "Danilo Sano", 1, duplicateSearchLimit).Return([]dto.ResultCustomerRequest{
+			customerNamed(2, "Sano", "Danilo"),
+			customerNamed(3, "Danielle", "Santos"),
+			customerNamed(4, "D.", "Sano"),
+			customerNamed(5, "Dánilo", "Sanó"),
+		}, nil)
+		repoMock.On("GetWithContext", ctx, 9).Return(customerNamed(9, "Maria", "Silva"), nil)
+
+		candidates, err := service.FindDuplicates(ctx, 1)
+		assert.NoError(t, err)
+		ids := []int{}
+		for _, c := range candidates {
+			ids = append(ids, c.Customer.ID)
+		}
+		assert.Equal(t, []int{9, 4, 2, 5}, ids)
+		assert.Equal(t, []string{"danilo@example.com"}, candidates[1].SharedContacts)
+		assert.Equal(t, 1.0, candidates[2].NameSimilarity)
+	})
+
+	t.Run("When the customer does not exist, an error will be returned.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("ExistsByIDWithContext", ctx, 1).Return(false)
+		repoMock.On("GetMergedIntoWithContext", ctx, 1).Return(0, ErrorCustomerNotFound)
+
+		_, err := service.FindDuplicates(ctx, 1)
+		assert.Equal(t, ErrorCustomerNotFound, err)
+	})
+}
+
+func TestMerge(t *testing.T) {
+	source := customerNamed(1, "Danilo", "Sano")
+	target := customerNamed(2, "Danilo", "Sano")
+	request := dto.MergeRequest{SourceID: 1, TargetID: 2}
+
+	t.Run("The source is merged into the target, the merge being audited on both and announced, as is the move of its subsidiaries.", func(t *testing.T) {
+		repoMock := new(mocks.CustomersRepositoryMock)
+		auditMock := new(auditMocks.AuditRepositoryMock)
+		outboxMock := new(outboxMocks.OutboxRepositoryMock)
+		service := NewService(repoMock, WithAuditLog(auditMock), WithOutbox(outboxMock))
+		ctx := reqctx.WithUserID(context.Background(), "agent-1")
+		repoMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("ExistsByIDWithContext", ctx, 2).Return(true)
+		repoMock.On("LockHierarchyWithContext", ctx, 2).Return([]int{2}, nil)
+		repoMock.On("GetWithContext", ctx, 1).Return(source, nil)
+		sourceID, targetID := 1, 2
+		child := customerNamed(3, "Acme", "Retail")
+		child.ParentID = &sourceID
+		moved := child
+		moved.ParentID = &targetID
+		repoMock.On("GetChildrenWithContext", ctx, 1).Return([]dto.ResultCustomerRequest{child}, nil)
+		repoMock.On("MergeWithContext", ctx, 1, 2).Return(nil)
+		repoMock.On("GetWithContext", ctx, 2).Return(target, nil)
+		repoMock.On("GetWithContext", ctx, 3).Return(moved, nil)
+		for _, id := range []int{1, 2} {
+			auditMock.On("SaveWithContext", ctx, mock.MatchedBy(func(e domain.AuditEntry) bool {
+				return e.Action == domain.AuditActionMerge && e.CustomerID == id && e.Actor == "agent-1" &&
+					len(e.Before) > 0 && len(e.After) > 0
+			})).Return(1, nil).Once()
+		}
+		auditMock.On("SaveWithContext", ctx, mock.MatchedBy(func(e domain.AuditEntry) bool {
+			return e.Action == domain.AuditActionUpdate && e.CustomerID == 3 && len(e.Before) > 0 && len(e.After) > 0
+		})).Return(3, nil).Once()
+		outboxMock.On("SaveWithContext", ctx, mock.MatchedBy(func(e domain.Event) bool {
+			return e.Type == domain.EventCustomerDeleted && e.CustomerID == 1
+		})).Return(nil).Once()
+		for _, id := range []int{2, 3} {
+			outboxMock.On("SaveWithContext", ctx, mock.MatchedBy(func(e domain.Event) bool {
+				return e.Type == domain.EventCustomerUpdated && e.CustomerID == id
+			})).Return(nil).Once()
+		}
+
+		result, err := service.Merge(ctx, request)
+		assert.NoError(t, err)
+		assert.Equal(t, target, result)
+		repoMock.AssertExpectations(t)
+		auditMock.AssertExpectations(t)
+		outboxMock.AssertExpectations(t)
+	})
+
+	t.Run("A customer cannot be merged into itself.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+
+		_, err := service.Merge(ctx, dto.MergeRequest{SourceID: 1, TargetID: 1})
+		assert.Equal(t, ErrorMergeSameCustomer, err)
+		repoMock.AssertNotCalled(t, "MergeWithContext", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("A customer already merged cannot be merged again.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("ExistsByIDWithContext", ctx, 1).Return(false)
+		repoMock.On("GetMergedIntoWithContext", ctx, 1).Return(3, nil)
+
+		_, err := service.Merge(ctx, request)
+		assert.ErrorIs(t, err, ErrorCustomerNotFound)
+		repoMock.AssertNotCalled(t, "MergeWithContext", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("When the records cannot be moved, the error is returned.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("ExistsByIDWithContext", ctx, mock.Anything).Return(true)
+		repoMock.On("LockHierarchyWithContext", ctx, 2).Return([]int{2}, nil)
+		repoMock.On("GetWithContext", ctx, 1).Return(source, nil)
+		repoMock.On("GetChildrenWithContext", ctx, 1).Return([]dto.ResultCustomerRequest{}, nil)
+		repoMock.On("MergeWithContext", ctx, 1, 2).Return(errors.New("generic error"))
+
+		_, err := service.Merge(ctx, request)
+		assert.Equal(t, errors.New("generic error"), err)
+	})
+
+	t.Run("A customer cannot be merged into one of its subsidiaries.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("ExistsByIDWithContext", ctx, mock.Anything).Return(true)
+		repoMock.On("LockHierarchyWithContext", ctx, 2).Return([]int{2, 5, 1}, nil)
+
+		_, err := service.Merge(ctx, request)
+		assert.Equal(t, ErrorHierarchyCycle, err)
+		repoMock.AssertNotCalled(t, "MergeWithContext", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestNameSimilarity(t *testing.T) {
+	t.Run("Names are compared regardless of case, accents, punctuation and word order.", func(t *testing.T) {
+		assert.Equal(t, "danilo sano", normalizeName("Sano, Danilo"))
+		assert.Equal(t, "danilo sano", normalizeName("  DÂNILO   sanó "))
+		assert.Equal(t, 1.0, nameSimilarity(normalizeName("Sano, Danilo"), normalizeName("danilo sanó")))
+	})
+
+	t.Run("The similarity is the Jaro-Winkler one.", func(t *testing.T) {
+		assert.InDelta(t, 0.961, nameSimilarity("martha", "marhta"), 0.001)
+		assert.InDelta(t, 0.840, nameSimilarity("dwayne", "duane"), 0.001)
+		assert.InDelta(t, 0.813, nameSimilarity("dixon", "dicksonx"), 0.001)
+		assert.Equal(t, 0.0, nameSimilarity("abc", "xyz"))
+		assert.Equal(t, 0.0, nameSimilarity("", "xyz"))
+	})
+}
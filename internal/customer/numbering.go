@@ -0,0 +1,107 @@
+package customer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// customerNumberSequence is the name of the sequence the customer numbers are
+// allocated from.
+const customerNumberSequence = "customer_number"
+
+// maxNumberDigits keeps the allocated numbers within the INT customer_number
+// column.
+const maxNumberDigits = 9
+
+var (
+	ErrorCustomerNumberRequired   = errors.New("customer number is required")
+	ErrorCustomerNumbersExhausted = errors.New("customer numbers exhausted")
+	ErrorInvalidNumbering         = errors.New("invalid customer numbering")
+)
+
+// Sequence hands out the successive values of a named counter, starting at 1.
+type Sequence interface {
+	NextWithContext(ctx context.Context, name string) (int64, error)
+}
+
+// Numbering describes the customer numbers allocated by the service when none is
+// given. A number is made of the Prefix digits, the sequence value padded with
+// zeros to Width digits and, with CheckDigit, a Luhn check digit: the prefix 7,
+// width 5 and a check digit turn the value 42 into 7000425. Numbers remain
+// integers so that every API keeps accepting them.
+type Numbering struct {
+	// Prefix is written before the sequence value. Zero means no prefix.
+	Prefix int
+	// Width is the number of digits of the sequence value, the larger values
+	// being refused. Zero means no padding.
+	Width int
+	// CheckDigit appends a Luhn check digit, catching most typing mistakes.
+	CheckDigit bool
+	// GapFree allocates the number in the transaction creating the customer, so
+	// that a failure gives it back. Customers are then created one at a time.
+	GapFree bool
+}
+
+// Validate reports whether the numbers described fit the customer_number column.
+func (n Numbering) Validate() error {
+	if n.Prefix < 0 || n.Width < 0 {
+		return fmt.Errorf("%w: prefix and width cannot be negative", ErrorInvalidNumbering)
+	}
+
+	digits := n.Width
+	if n.Prefix > 0 {
+		digits += len(strconv.Itoa(n.Prefix))
+	}
+	if n.CheckDigit {
+		digits++
+	}
+	if digits > maxNumberDigits {
+		return fmt.Errorf("%w: numbers cannot be longer than %d digits", ErrorInvalidNumbering, maxNumberDigits)
+	}
+	return nil
+}
+
+// Format returns the customer number of a sequence value.
+func (n Numbering) Format(value int64) (int, error) {
+	var b strings.Builder
+	if n.Prefix > 0 {
+		b.WriteString(strconv.Itoa(n.Prefix))
+	}
+
+	digits := strconv.FormatInt(value, 10)
+	if n.Width > 0 {
+		if len(digits) > n.Width {
+			return 0, ErrorCustomerNumbersExhausted
+		}
+		b.WriteString(strings.Repeat("0", n.Width-len(digits)))
+	}
+	b.WriteString(digits)
+
+	if n.CheckDigit {
+		b.WriteByte(luhnDigit(b.String()))
+	}
+	if b.Len() > maxNumberDigits {
+		return 0, ErrorCustomerNumbersExhausted
+	}
+
+	return strconv.Atoi(b.String())
+}
+
+// luhnDigit returns the Luhn check digit of a string of decimal digits.
+func luhnDigit(digits string) byte {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-1-i)%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
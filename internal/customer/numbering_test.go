@@ -0,0 +1,52 @@
+package customer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumberingFormat(t *testing.T) {
+	t.Run("The sequence value is padded, prefixed and followed by its check digit.", func(t *testing.T) {
+		for _, tc := range []struct {
+			numbering Numbering
+			value     int64
+			expected  int
+		}{
+			{Numbering{}, 42, 42},
+			{Numbering{Width: 6}, 42, 42},
+			{Numbering{Prefix: 7, Width: 5}, 42, 700042},
+			{Numbering{Prefix: 7, Width: 5, CheckDigit: true}, 42, 7000425},
+			{Numbering{Prefix: 7, Width: 5, CheckDigit: true}, 1, 7000011},
+		} {
+			number, err := tc.numbering.Format(tc.value)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, number)
+		}
+	})
+
+	t.Run("The values that no longer fit the width, or the column, are refused.", func(t *testing.T) {
+		_, err := Numbering{Prefix: 7, Width: 2}.Format(100)
+		assert.Equal(t, ErrorCustomerNumbersExhausted, err)
+
+		_, err = Numbering{CheckDigit: true}.Format(799273987)
+		assert.Equal(t, ErrorCustomerNumbersExhausted, err)
+	})
+}
+
+func TestNumberingValidate(t *testing.T) {
+	t.Run("The numbers must fit the customer_number column.", func(t *testing.T) {
+		assert.NoError(t, Numbering{}.Validate())
+		assert.NoError(t, Numbering{Prefix: 12, Width: 6, CheckDigit: true}.Validate())
+		assert.ErrorIs(t, Numbering{Prefix: 123, Width: 6, CheckDigit: true}.Validate(), ErrorInvalidNumbering)
+		assert.ErrorIs(t, Numbering{Width: -1}.Validate(), ErrorInvalidNumbering)
+	})
+}
+
+func TestLuhnDigit(t *testing.T) {
+	t.Run("The check digit matches the Luhn algorithm.", func(t *testing.T) {
+		assert.Equal(t, byte('3'), luhnDigit("7992739871"))
+		assert.Equal(t, byte('0'), luhnDigit("0"))
+		assert.Equal(t, byte('5'), luhnDigit("700042"))
+	})
+}
@@ -4,22 +4,43 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/danilosano/web-golang-api/internal/domain"
 	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/database"
+	"github.com/go-sql-driver/mysql"
 )
 
+// mysqlDuplicateEntry is the MySQL error raised when a unique index is violated.
+const mysqlDuplicateEntry = 1062
+
 type Repository interface {
 	GetAllWithContext(ctx context.Context) ([]dto.ResultCustomerRequest, error)
 	GetWithContext(ctx context.Context, id int) (dto.ResultCustomerRequest, error)
 	GetByCustomerNumberWithContext(ctx context.Context, customerNumber int) (dto.ResultCustomerRequest, error)
+	ListWithContext(ctx context.Context, f dto.CustomerFilter) ([]dto.ResultCustomerRequest, int, error)
 	ExistsByCustomerNumberWithContext(ctx context.Context, cid int) bool
 	ExistsByIDWithContext(ctx context.Context, id int) bool
 	ExistsByCustomerNumberAndIDWithContext(ctx context.Context, id, cid int) bool
 	SaveWithContext(ctx context.Context, s domain.Customer) (int, error)
 	UpdateWithContext(ctx context.Context, s domain.Customer) error
 	DeleteWithContext(ctx context.Context, id int) error
+	UpdateStatusWithContext(ctx context.Context, id int, from, to string, updatedAt time.Time) error
+	SaveStatusChangeWithContext(ctx context.Context, change domain.CustomerStatusChange) (int, error)
+	GetStatusChangesWithContext(ctx context.Context, customerID int) ([]domain.CustomerStatusChange, error)
+	GetStatusChangesAfterWithContext(ctx context.Context, customerID int, after time.Time, afterID, limit int) ([]domain.CustomerStatusChange, error)
+	GetByNameMatchWithContext(ctx context.Context, name string, excludeID, limit int) ([]dto.ResultCustomerRequest, error)
+	GetSharedContactsWithContext(ctx context.Context, id int) (map[int][]string, error)
+	MergeWithContext(ctx context.Context, sourceID, targetID int) error
+	GetMergedIntoWithContext(ctx context.Context, id int) (int, error)
+	GetChildrenWithContext(ctx context.Context, id int) ([]dto.ResultCustomerRequest, error)
+	GetAncestorsWithContext(ctx context.Context, id int) ([]dto.ResultCustomerRequest, error)
+	LockHierarchyWithContext(ctx context.Context, id int) ([]int, error)
+	GetTreeWithContext(ctx context.Context, rootID int) ([]dto.ResultCustomerRequest, error)
 }
 
 type repository struct {
@@ -33,8 +54,8 @@ func NewRepository(db *sql.DB) Repository {
 }
 
 func (r *repository) GetAllWithContext(ctx context.Context) ([]dto.ResultCustomerRequest, error) {
-	query := "SELECT customer_id,customer_number, first_name, last_name, created_at, updated_at FROM customers WHERE deleted_at IS NULL;"
-	rows, err := r.db.QueryContext(ctx, query)
+	query := "SELECT customer_id,customer_number, first_name, last_name, status, attributes, created_at, updated_at, parent_id FROM customers WHERE deleted_at IS NULL;"
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +64,7 @@ func (r *repository) GetAllWithContext(ctx context.Context) ([]dto.ResultCustome
 
 	for rows.Next() {
 		c := dto.ResultCustomerRequest{}
-		_ = rows.Scan(&c.ID, &c.CustomerNumber, &c.FirstName, &c.LastName, &c.CreatedAt, &c.UpdatedAt)
+		_ = rows.Scan(&c.ID, &c.CustomerNumber, &c.FirstName, &c.LastName, &c.Status, &c.Attributes, &c.CreatedAt, &c.UpdatedAt, &c.ParentID)
 		customers = append(customers, c)
 	}
 
@@ -51,10 +72,10 @@ func (r *repository) GetAllWithContext(ctx context.Context) ([]dto.ResultCustome
 }
 
 func (r *repository) GetWithContext(ctx context.Context, id int) (dto.ResultCustomerRequest, error) {
-	query := "SELECT customer_id,customer_number, first_name, last_name, created_at, updated_at FROM customers WHERE deleted_at IS NULL and customer_id=?;"
-	row := r.db.QueryRowContext(ctx, query, id)
+	query := "SELECT customer_id,customer_number, first_name, last_name, status, attributes, created_at, updated_at, parent_id FROM customers WHERE deleted_at IS NULL and customer_id=?;"
+	row := database.Conn(ctx, r.db).QueryRowContext(ctx, query, id)
 	c := dto.ResultCustomerRequest{}
-	err := row.Scan(&c.ID, &c.CustomerNumber, &c.FirstName, &c.LastName, &c.CreatedAt, &c.UpdatedAt)
+	err := row.Scan(&c.ID, &c.CustomerNumber, &c.FirstName, &c.LastName, &c.Status, &c.Attributes, &c.CreatedAt, &c.UpdatedAt, &c.ParentID)
 	if err != nil {
 		return dto.ResultCustomerRequest{}, err
 	}
@@ -63,10 +84,10 @@ func (r *repository) GetWithContext(ctx context.Context, id int) (dto.ResultCust
 }
 
 func (r *repository) GetByCustomerNumberWithContext(ctx context.Context, customerNumber int) (dto.ResultCustomerRequest, error) {
-	query := "SELECT customer_id,customer_number, first_name, last_name, created_at, updated_at FROM customers WHERE deleted_at IS NULL and customer_number=?;"
-	row := r.db.QueryRowContext(ctx, query, customerNumber)
+	query := "SELECT customer_id,customer_number, first_name, last_name, status, attributes, created_at, updated_at, parent_id FROM customers WHERE deleted_at IS NULL and customer_number=?;"
+	row := database.Conn(ctx, r.db).QueryRowContext(ctx, query, customerNumber)
 	c := dto.ResultCustomerRequest{}
-	err := row.Scan(&c.ID, &c.CustomerNumber, &c.FirstName, &c.LastName, &c.CreatedAt, &c.UpdatedAt)
+	err := row.Scan(&c.ID, &c.CustomerNumber, &c.FirstName, &c.LastName, &c.Status, &c.Attributes, &c.CreatedAt, &c.UpdatedAt, &c.ParentID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return dto.ResultCustomerRequest{}, ErrorCustomerNotFound
@@ -77,37 +98,148 @@ func (r *repository) GetByCustomerNumberWithContext(ctx context.Context, custome
 	return c, nil
 }
 
+// ListWithContext returns the requested page of the customers matching f, ordered by
+// ID, along with the total number of matching customers. Names match by prefix,
+// emails any email contact of the customer, and tags the tags of the customer,
+// regardless of case. Attributes match their value exactly.
+func (r *repository) ListWithContext(ctx context.Context, f dto.CustomerFilter) ([]dto.ResultCustomerRequest, int, error) {
+	where, args := filterClause(f)
+
+	var total int
+	countQuery := "SELECT COUNT(*) FROM customers WHERE " + where + ";"
+	if err := database.Conn(ctx, r.db).QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := "SELECT customer_id,customer_number, first_name, last_name, status, attributes, created_at, updated_at, parent_id FROM customers WHERE " + where + " ORDER BY customer_id LIMIT ? OFFSET ?;"
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, query, append(args, f.PageSize, (f.Page-1)*f.PageSize)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	customers := []dto.ResultCustomerRequest{}
+	for rows.Next() {
+		c := dto.ResultCustomerRequest{}
+		if err := rows.Scan(&c.ID, &c.CustomerNumber, &c.FirstName, &c.LastName, &c.Status, &c.Attributes, &c.CreatedAt, &c.UpdatedAt, &c.ParentID); err != nil {
+			return nil, 0, err
+		}
+		customers = append(customers, c)
+	}
+
+	return customers, total, rows.Err()
+}
+
+// filterClause builds the WHERE conditions, and their arguments, selecting the
+// customers matching f.
+func filterClause(f dto.CustomerFilter) (string, []any) {
+	conds := []string{"deleted_at IS NULL"}
+	var args []any
+
+	if f.CustomerNumber != nil {
+		conds = append(conds, "customer_number=?")
+		args = append(args, *f.CustomerNumber)
+	}
+	if f.FirstName != "" {
+		conds = append(conds, "first_name LIKE ?")
+		args = append(args, likePrefix(f.FirstName))
+	}
+	if f.LastName != "" {
+		conds = append(conds, "last_name LIKE ?")
+		args = append(args, likePrefix(f.LastName))
+	}
+	if f.Email != "" {
+		conds = append(conds, "customer_id IN (SELECT customer_id FROM contacts WHERE deleted_at IS NULL and type='email' and value=?)")
+		args = append(args, strings.ToLower(f.Email))
+	}
+	if f.Status != "" {
+		conds = append(conds, "status=?")
+		args = append(args, f.Status)
+	}
+	if tags := tagNames(f.Tags); len(tags) > 0 {
+		cond := "customer_id IN (SELECT ct.customer_id FROM customer_tags ct JOIN tags t ON t.tag_id=ct.tag_id WHERE t.name IN (" +
+			strings.TrimSuffix(strings.Repeat("?,", len(tags)), ",") + ")"
+		for _, tag := range tags {
+			args = append(args, tag)
+		}
+		if f.TagMatch == "all" {
+			cond += " GROUP BY ct.customer_id HAVING COUNT(*)=?"
+			args = append(args, len(tags))
+		}
+		conds = append(conds, cond+")")
+	}
+	for _, attribute := range f.Attributes {
+		name, value, _ := strings.Cut(attribute, ":")
+		conds = append(conds, "JSON_UNQUOTE(JSON_EXTRACT(attributes, CONCAT('$.', JSON_QUOTE(?))))=?")
+		args = append(args, name, value)
+	}
+
+	return strings.Join(conds, " and "), args
+}
+
+// tagNames returns the distinct tags of a filter, in lower case as they are stored.
+func tagNames(tags []string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		name := strings.ToLower(strings.TrimSpace(tag))
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// likePrefix returns a LIKE pattern matching the values starting with s.
+func likePrefix(s string) string {
+	return likeEscaper.Replace(s) + "%"
+}
+
 func (r *repository) ExistsByCustomerNumberWithContext(ctx context.Context, cid int) bool {
 	query := "SELECT customer_number FROM customers WHERE deleted_at IS NULL and customer_number=?;"
-	row := r.db.QueryRowContext(ctx, query, cid)
+	row := database.Conn(ctx, r.db).QueryRowContext(ctx, query, cid)
 	err := row.Scan(&cid)
 	return errors.Is(err, nil)
 }
 
 func (r *repository) ExistsByIDWithContext(ctx context.Context, id int) bool {
 	query := "SELECT customer_id FROM customers WHERE deleted_at IS NULL and customer_id=?;"
-	row := r.db.QueryRowContext(ctx, query, id)
+	row := database.Conn(ctx, r.db).QueryRowContext(ctx, query, id)
 	err := row.Scan(&id)
 	return errors.Is(err, nil)
 }
 
 func (r *repository) ExistsByCustomerNumberAndIDWithContext(ctx context.Context, id, cid int) bool {
 	query := "SELECT customer_id FROM customers WHERE deleted_at IS NULL and customer_id=? and customer_number=?;"
-	row := r.db.QueryRowContext(ctx, query, id, cid)
+	row := database.Conn(ctx, r.db).QueryRowContext(ctx, query, id, cid)
 	err := row.Scan(&id)
 	return errors.Is(err, nil)
 }
 
+// duplicateError reports a violation of the unique index on the numbers of the
+// live customers as ErrorCustomerNumberAlreadyExist. It catches the concurrent
+// changes that ExistsByCustomerNumberWithContext cannot see.
+func duplicateError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+		return ErrorCustomerNumberAlreadyExist
+	}
+	return err
+}
+
 func (r *repository) SaveWithContext(ctx context.Context, c domain.Customer) (int, error) {
-	query := "INSERT INTO customers (customer_number, first_name, last_name, created_at) VALUES (?, ?, ?, ?);"
-	stmt, err := r.db.PrepareContext(ctx, query)
+	query := "INSERT INTO customers (customer_number, first_name, last_name, status, attributes, parent_id, created_at) VALUES (?, ?, ?, ?, ?, ?, ?);"
+	stmt, err := database.Conn(ctx, r.db).PrepareContext(ctx, query)
 	if err != nil {
 		return 0, err
 	}
 
-	res, err := stmt.ExecContext(ctx, &c.CustomerNumber, &c.FirstName, &c.LastName, &c.CreatedAt)
+	res, err := stmt.ExecContext(ctx, &c.CustomerNumber, &c.FirstName, &c.LastName, &c.Status, c.Attributes, c.ParentID, &c.CreatedAt)
 	if err != nil {
-		return 0, err
+		return 0, duplicateError(err)
 	}
 
 	id, err := res.LastInsertId()
@@ -119,15 +251,15 @@ func (r *repository) SaveWithContext(ctx context.Context, c domain.Customer) (in
 }
 
 func (r *repository) UpdateWithContext(ctx context.Context, c domain.Customer) error {
-	query := "UPDATE customers SET customer_number=?, first_name=?, last_name=?, updated_at=? WHERE customer_id=?;"
-	stmt, err := r.db.PrepareContext(ctx, query)
+	query := "UPDATE customers SET customer_number=?, first_name=?, last_name=?, attributes=?, parent_id=?, updated_at=? WHERE customer_id=?;"
+	stmt, err := database.Conn(ctx, r.db).PrepareContext(ctx, query)
 	if err != nil {
 		return err
 	}
 
-	res, err := stmt.ExecContext(ctx, &c.CustomerNumber, &c.FirstName, &c.LastName, &c.UpdatedAt, &c.ID)
+	res, err := stmt.ExecContext(ctx, &c.CustomerNumber, &c.FirstName, &c.LastName, c.Attributes, c.ParentID, &c.UpdatedAt, &c.ID)
 	if err != nil {
-		return err
+		return duplicateError(err)
 	}
 
 	_, err = res.RowsAffected()
@@ -140,7 +272,7 @@ func (r *repository) UpdateWithContext(ctx context.Context, c domain.Customer) e
 
 func (r *repository) DeleteWithContext(ctx context.Context, id int) error {
 	query := "UPDATE customers SET deleted_at=? WHERE customer_id=?;"
-	stmt, err := r.db.PrepareContext(ctx, query)
+	stmt, err := database.Conn(ctx, r.db).PrepareContext(ctx, query)
 	if err != nil {
 		return err
 	}
@@ -159,5 +291,302 @@ func (r *repository) DeleteWithContext(ctx context.Context, id int) error {
 		return ErrorCustomerNotFound
 	}
 
+	return r.deleteRelated(ctx, id)
+}
+
+// UpdateStatusWithContext moves a customer from one status to another. When the
+// customer is no longer in the from status, because of a concurrent change, it
+// returns ErrorIllegalStatusTransition.
+func (r *repository) UpdateStatusWithContext(ctx context.Context, id int, from, to string, updatedAt time.Time) error {
+	query := "UPDATE customers SET status=?, updated_at=? WHERE customer_id=? and status=? and deleted_at IS NULL;"
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, query, to, updatedAt, id, from)
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affect < 1 {
+		if !r.ExistsByIDWithContext(ctx, id) {
+			return ErrorCustomerNotFound
+		}
+		return fmt.Errorf("%w: the status is no longer %s", ErrorIllegalStatusTransition, from)
+	}
+
+	return nil
+}
+
+func (r *repository) SaveStatusChangeWithContext(ctx context.Context, change domain.CustomerStatusChange) (int, error) {
+	query := "INSERT INTO customer_status_changes (customer_id, from_status, to_status, reason, actor, created_at) VALUES (?, ?, ?, ?, ?, ?);"
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, query, change.CustomerID, change.From, change.To, change.Reason, change.Actor, change.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+const statusChangeColumns = "status_change_id, customer_id, from_status, to_status, reason, actor, created_at"
+
+// GetStatusChangesWithContext returns the status changes of a customer, oldest first.
+func (r *repository) GetStatusChangesWithContext(ctx context.Context, customerID int) ([]domain.CustomerStatusChange, error) {
+	query := "SELECT " + statusChangeColumns + " FROM customer_status_changes WHERE customer_id=? ORDER BY created_at, status_change_id;"
+	return r.queryStatusChanges(ctx, query, customerID)
+}
+
+// GetStatusChangesAfterWithContext returns up to limit status changes of a
+// customer made after the given date or, on that date, with an ID greater than
+// afterID, oldest first.
+func (r *repository) GetStatusChangesAfterWithContext(ctx context.Context, customerID int, after time.Time, afterID, limit int) ([]domain.CustomerStatusChange, error) {
+	query := "SELECT " + statusChangeColumns + " FROM customer_status_changes WHERE customer_id=? and (created_at>? or (created_at=? and status_change_id>?)) ORDER BY created_at, status_change_id LIMIT ?;"
+	return r.queryStatusChanges(ctx, query, customerID, after, after, afterID, limit)
+}
+
+func (r *repository) queryStatusChanges(ctx context.Context, query string, args ...any) ([]domain.CustomerStatusChange, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []domain.CustomerStatusChange{}
+	for rows.Next() {
+		c := domain.CustomerStatusChange{}
+		if err := rows.Scan(&c.ID, &c.CustomerID, &c.From, &c.To, &c.Reason, &c.Actor, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+
+	return changes, rows.Err()
+}
+
+// GetByNameMatchWithContext returns up to limit customers, other than excludeID,
+// whose names share bigrams with name in the FULLTEXT index, best matches first.
+func (r *repository) GetByNameMatchWithContext(ctx context.Context, name string, excludeID, limit int) ([]dto.ResultCustomerRequest, error) {
+	query := "SELECT customer_id, customer_number, first_name, last_name, status, attributes, created_at, updated_at, parent_id FROM customers " +
+		"WHERE deleted_at IS NULL and customer_id<>? and MATCH(first_name, last_name) AGAINST (? IN NATURAL LANGUAGE MODE) " +
+		"ORDER BY MATCH(first_name, last_name) AGAINST (? IN NATURAL LANGUAGE MODE) DESC, customer_id LIMIT ?;"
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, query, excludeID, name, name, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var customers []dto.ResultCustomerRequest
+	for rows.Next() {
+		c := dto.ResultCustomerRequest{}
+		if err := rows.Scan(&c.ID, &c.CustomerNumber, &c.FirstName, &c.LastName, &c.Status, &c.Attributes, &c.CreatedAt, &c.UpdatedAt, &c.ParentID); err != nil {
+			return nil, err
+		}
+		customers = append(customers, c)
+	}
+
+	return customers, rows.Err()
+}
+
+// GetSharedContactsWithContext returns, by customer ID, the contact values the
+// other customers have in common with the given one.
+func (r *repository) GetSharedContactsWithContext(ctx context.Context, id int) (map[int][]string, error) {
+	query := "SELECT DISTINCT o.customer_id, o.value FROM contacts c " +
+		"JOIN contacts o ON o.type=c.type and o.value=c.value and o.customer_id<>c.customer_id and o.deleted_at IS NULL " +
+		"JOIN customers cu ON cu.customer_id=o.customer_id and cu.deleted_at IS NULL " +
+		"WHERE c.customer_id=? and c.deleted_at IS NULL ORDER BY o.customer_id, o.value;"
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	shared := map[int][]string{}
+	for rows.Next() {
+		var customerID int
+		var value string
+		if err := rows.Scan(&customerID, &value); err != nil {
+			return nil, err
+		}
+		shared[customerID] = append(shared[customerID], value)
+	}
+
+	return shared, rows.Err()
+}
+
+// MergeWithContext moves the records of the source customer to the target one and
+// soft deletes the source, pointing it to the target. The moved contacts and
+// addresses lose their primary or default flag when the target already has one
+// of the same type, and the subsidiaries moved have their update time set. It
+// should run in a transaction.
+func (r *repository) MergeWithContext(ctx context.Context, sourceID, targetID int) error {
+	conn := database.Conn(ctx, r.db)
+	queries := []string{
+		"UPDATE contacts SET is_primary=FALSE WHERE customer_id=? and deleted_at IS NULL and is_primary and type IN " +
+			"(SELECT type FROM (SELECT type FROM contacts WHERE customer_id=? and deleted_at IS NULL and is_primary) AS t);",
+		"UPDATE addresses SET is_default=FALSE WHERE customer_id=? and deleted_at IS NULL and is_default and type IN " +
+			"(SELECT type FROM (SELECT type FROM addresses WHERE customer_id=? and deleted_at IS NULL and is_default) AS t);",
+	}
+	for _, query := range queries {
+		if _, err := conn.ExecContext(ctx, query, sourceID, targetID); err != nil {
+			return err
+		}
+	}
+
+	for _, table := range relatedTables {
+		query := "UPDATE " + table + " SET customer_id=? WHERE customer_id=? and deleted_at IS NULL;"
+		if _, err := conn.ExecContext(ctx, query, targetID, sourceID); err != nil {
+			return err
+		}
+	}
+
+	query := "INSERT IGNORE INTO customer_tags (customer_id, tag_id, tagged_at) SELECT ?, tag_id, tagged_at FROM customer_tags WHERE customer_id=?;"
+	if _, err := conn.ExecContext(ctx, query, targetID, sourceID); err != nil {
+		return err
+	}
+	if _, err := conn.ExecContext(ctx, "DELETE FROM customer_tags WHERE customer_id=?;", sourceID); err != nil {
+		return err
+	}
+
+	// The subsidiaries of the source, and the customers merged earlier into it, now
+	// point to the target.
+	now := time.Now()
+	if _, err := conn.ExecContext(ctx, "UPDATE customers SET parent_id=?, updated_at=? WHERE parent_id=?;", targetID, now, sourceID); err != nil {
+		return err
+	}
+	if _, err := conn.ExecContext(ctx, "UPDATE customers SET merged_into=? WHERE merged_into=?;", targetID, sourceID); err != nil {
+		return err
+	}
+
+	res, err := conn.ExecContext(ctx, "UPDATE customers SET deleted_at=?, merged_into=? WHERE customer_id=? and deleted_at IS NULL;", now, targetID, sourceID)
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affect < 1 {
+		return ErrorCustomerNotFound
+	}
+
+	return nil
+}
+
+// GetMergedIntoWithContext returns the customer a merged customer was merged into.
+func (r *repository) GetMergedIntoWithContext(ctx context.Context, id int) (int, error) {
+	query := "SELECT merged_into FROM customers WHERE customer_id=? and merged_into IS NOT NULL;"
+	var targetID int
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(&targetID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrorCustomerNotFound
+		}
+		return 0, err
+	}
+
+	return targetID, nil
+}
+
+// GetChildrenWithContext returns the direct subsidiaries of a customer, ordered by ID.
+func (r *repository) GetChildrenWithContext(ctx context.Context, id int) ([]dto.ResultCustomerRequest, error) {
+	query := "SELECT customer_id, customer_number, first_name, last_name, status, attributes, created_at, updated_at, parent_id FROM customers " +
+		"WHERE deleted_at IS NULL and parent_id=? ORDER BY customer_id;"
+	return r.queryCustomers(ctx, query, id)
+}
+
+// GetAncestorsWithContext returns the parent of a customer, its parent in turn and
+// so on up to the top of the hierarchy, nearest first. The chain stops at a
+// deleted customer, and after maxHierarchyDepth levels.
+func (r *repository) GetAncestorsWithContext(ctx context.Context, id int) ([]dto.ResultCustomerRequest, error) {
+	query := "WITH RECURSIVE ancestors (customer_id, parent_id, depth) AS (" +
+		"SELECT customer_id, parent_id, 0 FROM customers WHERE customer_id=? and deleted_at IS NULL " +
+		"UNION ALL " +
+		"SELECT c.customer_id, c.parent_id, a.depth + 1 FROM customers c JOIN ancestors a ON c.customer_id=a.parent_id " +
+		"WHERE c.deleted_at IS NULL and a.depth < ?) " +
+		"SELECT c.customer_id, c.customer_number, c.first_name, c.last_name, c.status, c.attributes, c.created_at, c.updated_at, c.parent_id " +
+		"FROM ancestors a JOIN customers c ON c.customer_id=a.customer_id WHERE a.depth > 0 ORDER BY a.depth;"
+	return r.queryCustomers(ctx, query, id, maxHierarchyDepth)
+}
+
+// LockHierarchyWithContext locks a customer and its ancestors with SELECT ... FOR
+// UPDATE, one level at a time so that each parent is read as last committed, and
+// returns their IDs, the customer first. It returns no ID when the customer does
+// not exist, and stops at a deleted customer, at a customer already walked and
+// after maxHierarchyDepth levels. It should run in a transaction.
+func (r *repository) LockHierarchyWithContext(ctx context.Context, id int) ([]int, error) {
+	query := "SELECT parent_id FROM customers WHERE customer_id=? and deleted_at IS NULL FOR UPDATE;"
+	conn := database.Conn(ctx, r.db)
+
+	ids := []int{}
+	for next := &id; next != nil && len(ids) <= maxHierarchyDepth && !slices.Contains(ids, *next); {
+		var parentID *int
+		err := conn.QueryRowContext(ctx, query, *next).Scan(&parentID)
+		if errors.Is(err, sql.ErrNoRows) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, *next)
+		next = parentID
+	}
+
+	return ids, nil
+}
+
+// GetTreeWithContext returns a customer followed by all of its descendants, level
+// by level and ordered by ID within a level, down to maxHierarchyDepth levels.
+// It returns no customer when the root does not exist.
+func (r *repository) GetTreeWithContext(ctx context.Context, rootID int) ([]dto.ResultCustomerRequest, error) {
+	query := "WITH RECURSIVE tree (customer_id, depth) AS (" +
+		"SELECT customer_id, 0 FROM customers WHERE customer_id=? and deleted_at IS NULL " +
+		"UNION ALL " +
+		"SELECT c.customer_id, t.depth + 1 FROM customers c JOIN tree t ON c.parent_id=t.customer_id " +
+		"WHERE c.deleted_at IS NULL and t.depth < ?) " +
+		"SELECT c.customer_id, c.customer_number, c.first_name, c.last_name, c.status, c.attributes, c.created_at, c.updated_at, c.parent_id " +
+		"FROM tree t JOIN customers c ON c.customer_id=t.customer_id ORDER BY t.depth, c.customer_id;"
+	return r.queryCustomers(ctx, query, rootID, maxHierarchyDepth)
+}
+
+func (r *repository) queryCustomers(ctx context.Context, query string, args ...any) ([]dto.ResultCustomerRequest, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	customers := []dto.ResultCustomerRequest{}
+	for rows.Next() {
+		c := dto.ResultCustomerRequest{}
+		if err := rows.Scan(&c.ID, &c.CustomerNumber, &c.FirstName, &c.LastName, &c.Status, &c.Attributes, &c.CreatedAt, &c.UpdatedAt, &c.ParentID); err != nil {
+			return nil, err
+		}
+		customers = append(customers, c)
+	}
+
+	return customers, rows.Err()
+}
+
+// relatedTables hold the records owned by a customer, deleted along with it.
+var relatedTables = []string{"addresses", "contacts", "notes", "attachments"}
+
+// deleteRelated soft deletes the records of a deleted customer, in the same
+// transaction when ctx carries one.
+func (r *repository) deleteRelated(ctx context.Context, id int) error {
+	now := time.Now()
+	for _, table := range relatedTables {
+		query := "UPDATE " + table + " SET deleted_at=? WHERE customer_id=? and deleted_at IS NULL;"
+		if _, err := database.Conn(ctx, r.db).ExecContext(ctx, query, now, id); err != nil {
+			return err
+		}
+	}
 	return nil
 }
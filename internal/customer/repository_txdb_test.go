@@ -2,6 +2,7 @@ package customer
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 	"time"
 
@@ -21,7 +22,7 @@ var (
 	}
 
 	mockedCustomerUpdated = domain.Customer{
-		CustomerNumber: 999,
+		CustomerNumber: 998,
 		FirstName:      "Danilo",
 		LastName:       "Sano",
 		CreatedAt:      time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
@@ -41,6 +42,10 @@ func TestSuite_CustomerRepository(t *testing.T) {
 	testExistsByCustomerNumberAndIDWithContext(t, repository)
 	testGetByCustomerNumberWithContext(t, repository)
 	testGetAllWithContext(t, repository)
+	testListWithContext(t, repository)
+	testUpdateStatusWithContext(t, repository)
+	testMergeWithContext(t, repository, db)
+	testHierarchyWithContext(t, repository)
 
 	db.Close()
 }
@@ -55,13 +60,18 @@ func testStoreWithContext(t *testing.T, repository Repository) {
 
 	result := repository.ExistsByIDWithContext(ctx, id)
 	assert.True(t, result)
+
+	_, err = repository.SaveWithContext(ctx, mockedCustomer)
+	assert.Equal(t, ErrorCustomerNumberAlreadyExist, err)
 }
 
 func testUpdateWithContext(t *testing.T, repository Repository) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	id, err := repository.SaveWithContext(ctx, mockedCustomer)
+	saved := mockedCustomer
+	saved.CustomerNumber = 997
+	id, err := repository.SaveWithContext(ctx, saved)
 	assert.NoError(t, err)
 	assert.True(t, id > 0)
 
@@ -73,6 +83,11 @@ func testUpdateWithContext(t *testing.T, repository Repository) {
 	err = repository.UpdateWithContext(ctx, mockedCustomerUpdated)
 	assert.NoError(t, err)
 
+	taken := mockedCustomerUpdated
+	taken.CustomerNumber = mockedCustomer.CustomerNumber
+	err = repository.UpdateWithContext(ctx, taken)
+	assert.Equal(t, ErrorCustomerNumberAlreadyExist, err)
+
 	result, err := repository.GetWithContext(ctx, id)
 	assert.Nil(t, err)
 	assert.Equal(t, dto.ResultCustomerRequest{
@@ -89,7 +104,9 @@ func testDeleteWithContext(t *testing.T, repository Repository) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	id, err := repository.SaveWithContext(ctx, mockedCustomer)
+	deleted := mockedCustomer
+	deleted.CustomerNumber = 996
+	id, err := repository.SaveWithContext(ctx, deleted)
 	assert.NoError(t, err)
 	assert.True(t, id > 0)
 
@@ -101,16 +118,16 @@ func testDeleteWithContext(t *testing.T, repository Repository) {
 
 	exists = repository.ExistsByIDWithContext(ctx, id)
 	assert.False(t, exists)
+
+	// The number of a deleted customer can be given to another one.
+	_, err = repository.SaveWithContext(ctx, deleted)
+	assert.NoError(t, err)
 }
 
 func testExistsByCustomerNumberWithContext(t *testing.T, repository Repository) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	id, err := repository.SaveWithContext(ctx, mockedCustomer)
-	assert.NoError(t, err)
-	assert.True(t, id > 0)
-
 	exists := repository.ExistsByCustomerNumberWithContext(ctx, mockedCustomer.CustomerNumber)
 	assert.True(t, exists)
 }
@@ -119,11 +136,13 @@ func testExistsByCustomerNumberAndIDWithContext(t *testing.T, repository Reposit
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	id, err := repository.SaveWithContext(ctx, mockedCustomer)
+	numbered := mockedCustomer
+	numbered.CustomerNumber = 995
+	id, err := repository.SaveWithContext(ctx, numbered)
 	assert.NoError(t, err)
 	assert.True(t, id > 0)
 
-	exists := repository.ExistsByCustomerNumberAndIDWithContext(ctx, id, mockedCustomer.CustomerNumber)
+	exists := repository.ExistsByCustomerNumberAndIDWithContext(ctx, id, numbered.CustomerNumber)
 	assert.True(t, exists)
 }
 
@@ -132,15 +151,17 @@ func testGetByCustomerNumberWithContext(t *testing.T, repository Repository) {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 		defer cancel()
 
-		id, err := repository.SaveWithContext(ctx, mockedCustomer)
+		numbered := mockedCustomer
+		numbered.CustomerNumber = 994
+		id, err := repository.SaveWithContext(ctx, numbered)
 		assert.NoError(t, err)
 		assert.True(t, id > 0)
 
-		mockedCustomer.ID = id
+		numbered.ID = id
 
-		result, err := repository.GetByCustomerNumberWithContext(ctx, mockedCustomer.CustomerNumber)
+		result, err := repository.GetByCustomerNumberWithContext(ctx, numbered.CustomerNumber)
 		assert.NoError(t, err)
-		assert.Equal(t, mockedCustomer, domain.Customer{
+		assert.Equal(t, numbered, domain.Customer{
 			ID:             id,
 			CustomerNumber: *result.CustomerNumber,
 			FirstName:      result.FirstName,
@@ -167,3 +188,189 @@ func testGetAllWithContext(t *testing.T, repository Repository) {
 	assert.NoError(t, err)
 	assert.True(t, len(report) > 0)
 }
+
+func testListWithContext(t *testing.T, repository Repository) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	listed := mockedCustomer
+	listed.CustomerNumber = 424242
+	listed.FirstName = "Zélia_%"
+	_, err := repository.SaveWithContext(ctx, listed)
+	assert.NoError(t, err)
+
+	customers, total, err := repository.ListWithContext(ctx, dto.CustomerFilter{FirstName: "Zélia_", Page: 1, PageSize: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, listed.CustomerNumber, *customers[0].CustomerNumber)
+
+	customers, total, err = repository.ListWithContext(ctx, dto.CustomerFilter{FirstName: "Zéliax", Page: 1, PageSize: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.Empty(t, customers)
+}
+
+func testUpdateStatusWithContext(t *testing.T, repository Repository) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	prospect := mockedCustomer
+	prospect.CustomerNumber = 434343
+	prospect.Status = domain.CustomerStatusProspect
+	id, err := repository.SaveWithContext(ctx, prospect)
+	assert.NoError(t, err)
+
+	err = repository.UpdateStatusWithContext(ctx, id, domain.CustomerStatusProspect, domain.CustomerStatusActive, time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	_, err = repository.SaveStatusChangeWithContext(ctx, domain.CustomerStatusChange{
+		CustomerID: id,
+		From:       domain.CustomerStatusProspect,
+		To:         domain.CustomerStatusActive,
+		Reason:     "Signed the contract",
+		Actor:      "agent-1",
+		CreatedAt:  time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	assert.NoError(t, err)
+
+	customers, total, err := repository.ListWithContext(ctx, dto.CustomerFilter{CustomerNumber: &prospect.CustomerNumber, Status: domain.CustomerStatusActive, Page: 1, PageSize: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, domain.CustomerStatusActive, customers[0].Status)
+
+	changes, err := repository.GetStatusChangesWithContext(ctx, id)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, "Signed the contract", changes[0].Reason)
+
+	err = repository.UpdateStatusWithContext(ctx, id, domain.CustomerStatusProspect, domain.CustomerStatusSuspended, time.Now())
+	assert.ErrorIs(t, err, ErrorIllegalStatusTransition)
+
+	err = repository.UpdateStatusWithContext(ctx, 0, domain.CustomerStatusProspect, domain.CustomerStatusActive, time.Now())
+	assert.ErrorIs(t, err, ErrorCustomerNotFound)
+}
+
+func testMergeWithContext(t *testing.T, repository Repository, db *sql.DB) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	sourceID, err := repository.SaveWithContext(ctx, domain.Customer{CustomerNumber: 939391, FirstName: "Danilo", LastName: "Sano", Status: domain.CustomerStatusProspect, CreatedAt: now})
+	assert.NoError(t, err)
+	targetID, err := repository.SaveWithContext(ctx, domain.Customer{CustomerNumber: 939392, FirstName: "Sano", LastName: "Danilo", Status: domain.CustomerStatusActive, CreatedAt: now})
+	assert.NoError(t, err)
+	earlierID, err := repository.SaveWithContext(ctx, domain.Customer{CustomerNumber: 939393, FirstName: "D.", LastName: "Sano", Status: domain.CustomerStatusProspect, CreatedAt: now})
+	assert.NoError(t, err)
+
+	contacts := "INSERT INTO contacts (customer_id, type, value, is_primary, created_at) VALUES (?, ?, ?, ?, ?);"
+	for _, args := range [][]any{
+		{sourceID, "email", "danilo@example.com", true, now},
+		{sourceID, "phone", "+5511999990000", false, now},
+		{targetID, "email", "sano@example.com", true, now},
+		{targetID, "phone", "+5511999990000", false, now},
+	} {
+		_, err := db.ExecContext(ctx, contacts, args...)
+		assert.NoError(t, err)
+	}
+
+	shared, err := repository.GetSharedContactsWithContext(ctx, sourceID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"+5511999990000"}, shared[targetID])
+
+	matches, err := repository.GetByNameMatchWithContext(ctx, "Danilo Sano", sourceID, 10)
+	assert.NoError(t, err)
+	ids := []int{}
+	for _, m := range matches {
+		ids = append(ids, m.ID)
+	}
+	assert.Contains(t, ids, targetID)
+	assert.NotContains(t, ids, sourceID)
+
+	// earlierID was merged into the source before, and follows it to the target.
+	_, err = db.ExecContext(ctx, "UPDATE customers SET deleted_at=?, merged_into=? WHERE customer_id=?;", now, sourceID, earlierID)
+	assert.NoError(t, err)
+
+	childID, err := repository.SaveWithContext(ctx, domain.Customer{CustomerNumber: 939394, FirstName: "Sano", LastName: "Retail", Status: domain.CustomerStatusActive, ParentID: &sourceID, CreatedAt: now})
+	assert.NoError(t, err)
+
+	assert.NoError(t, repository.MergeWithContext(ctx, sourceID, targetID))
+	assert.False(t, repository.ExistsByIDWithContext(ctx, sourceID))
+	child, err := repository.GetWithContext(ctx, childID)
+	assert.NoError(t, err)
+	assert.Equal(t, &targetID, child.ParentID)
+	assert.NotNil(t, child.UpdatedAt)
+	for _, id := range []int{sourceID, earlierID} {
+		mergedInto, err := repository.GetMergedIntoWithContext(ctx, id)
+		assert.NoError(t, err)
+		assert.Equal(t, targetID, mergedInto)
+	}
+	_, err = repository.GetMergedIntoWithContext(ctx, targetID)
+	assert.Equal(t, ErrorCustomerNotFound, err)
+
+	var moved, primaries int
+	err = db.QueryRowContext(ctx, "SELECT COUNT(*), COALESCE(SUM(is_primary), 0) FROM contacts WHERE customer_id=? and deleted_at IS NULL;", targetID).Scan(&moved, &primaries)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, moved)
+	assert.Equal(t, 1, primaries)
+
+	assert.Equal(t, ErrorCustomerNotFound, repository.MergeWithContext(ctx, sourceID, targetID))
+}
+
+func testHierarchyWithContext(t *testing.T, repository Repository) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	save := func(number int, name string, parentID *int) int {
+		id, err := repository.SaveWithContext(ctx, domain.Customer{CustomerNumber: number, FirstName: name, LastName: "Ltd", Status: domain.CustomerStatusActive, ParentID: parentID, CreatedAt: now})
+		assert.NoError(t, err)
+		return id
+	}
+	ids := func(customers []dto.ResultCustomerRequest) []int {
+		result := []int{}
+		for _, c := range customers {
+			result = append(result, c.ID)
+		}
+		return result
+	}
+
+	holdingID := save(949491, "Acme Holding", nil)
+	acmeID := save(949492, "Acme", &holdingID)
+	globexID := save(949493, "Globex", &holdingID)
+	initechID := save(949494, "Initech", &acmeID)
+	deletedID := save(949495, "Hooli", &acmeID)
+	assert.NoError(t, repository.DeleteWithContext(ctx, deletedID))
+
+	initech, err := repository.GetWithContext(ctx, initechID)
+	assert.NoError(t, err)
+	assert.Equal(t, acmeID, *initech.ParentID)
+
+	children, err := repository.GetChildrenWithContext(ctx, holdingID)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{acmeID, globexID}, ids(children))
+
+	ancestors, err := repository.GetAncestorsWithContext(ctx, initechID)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{acmeID, holdingID}, ids(ancestors))
+
+	hierarchy, err := repository.LockHierarchyWithContext(ctx, initechID)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{initechID, acmeID, holdingID}, hierarchy)
+
+	hierarchy, err = repository.LockHierarchyWithContext(ctx, deletedID)
+	assert.NoError(t, err)
+	assert.Empty(t, hierarchy)
+
+	tree, err := repository.GetTreeWithContext(ctx, holdingID)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{holdingID, acmeID, globexID, initechID}, ids(tree))
+
+	tree, err = repository.GetTreeWithContext(ctx, deletedID)
+	assert.NoError(t, err)
+	assert.Empty(t, tree)
+
+	// The subsidiaries of a merged customer move to the customer it was merged into.
+	assert.NoError(t, repository.MergeWithContext(ctx, acmeID, globexID))
+	children, err = repository.GetChildrenWithContext(ctx, globexID)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{initechID}, ids(children))
+}
@@ -3,15 +3,33 @@ package customer
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
+	"github.com/danilosano/web-golang-api/internal/audit"
 	"github.com/danilosano/web-golang-api/internal/domain"
 	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/internal/outbox"
+	"github.com/danilosano/web-golang-api/pkg/database"
+	"github.com/danilosano/web-golang-api/pkg/reqctx"
 )
 
 var (
 	ErrorCustomerNumberAlreadyExist = errors.New("customer number already exists")
 	ErrorCustomerNotFound           = errors.New("customer not found")
+	ErrorIllegalStatusTransition    = errors.New("illegal status transition")
+)
+
+// transitions lists, for each status, the statuses a customer may move to.
+var transitions = map[string][]string{
+	domain.CustomerStatusProspect:  {domain.CustomerStatusActive, domain.CustomerStatusClosed},
+	domain.CustomerStatusActive:    {domain.CustomerStatusSuspended, domain.CustomerStatusClosed},
+	domain.CustomerStatusSuspended: {domain.CustomerStatusActive, domain.CustomerStatusClosed},
+	domain.CustomerStatusClosed:    {},
+}
+
+const (
+	DefaultPageSize = 20
 )
 
 type Service interface {
@@ -20,36 +38,148 @@ type Service interface {
 	Delete(ctx context.Context, id int) error
 	Update(ctx context.Context, s dto.UpdateCustomerRequest, id int) (dto.ResultCustomerRequest, error)
 	Get(ctx context.Context, id int) (dto.ResultCustomerRequest, error)
+	GetByCustomerNumber(ctx context.Context, customerNumber int) (dto.ResultCustomerRequest, error)
+	List(ctx context.Context, f dto.CustomerFilter) (dto.CustomerPage, error)
+	ChangeStatus(ctx context.Context, id int, input dto.CustomerStatusRequest) (dto.ResultCustomerRequest, error)
+	FindDuplicates(ctx context.Context, id int) ([]dto.DuplicateCandidate, error)
+	Merge(ctx context.Context, input dto.MergeRequest) (dto.ResultCustomerRequest, error)
+	Children(ctx context.Context, id int) ([]dto.ResultCustomerRequest, error)
+	Ancestors(ctx context.Context, id int) ([]dto.ResultCustomerRequest, error)
+	Tree(ctx context.Context, id int) (dto.CustomerTree, error)
+}
+
+// AttributeSchema validates the custom attributes of a customer and returns them
+// normalized.
+type AttributeSchema interface {
+	Validate(ctx context.Context, attributes domain.Attributes) (domain.Attributes, error)
 }
 
 type service struct {
 	repository Repository
+	transactor database.Transactor
+	auditLog   audit.Repository
+	outbox     outbox.Repository
+	attributes AttributeSchema
+	sequence   Sequence
+	numbering  Numbering
 }
 
-func NewService(r Repository) Service {
-	return &service{
-		repository: r,
+type Option func(*service)
+
+// WithTransactor runs every change, and the records that go along with it, in a transaction.
+func WithTransactor(t database.Transactor) Option {
+	return func(s *service) {
+		s.transactor = t
 	}
 }
 
-func (s *service) Save(ctx context.Context, input dto.CreateCustomerRequest) (dto.ResultCustomerRequest, error) {
-	sr := domain.Customer{
-		ID:             0,
-		CustomerNumber: *input.CustomerNumber,
-		FirstName:      input.FirstName,
-		LastName:       input.LastName,
-		CreatedAt:      time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), time.Now().Hour(), time.Now().Minute(),time.Now().Second(), 0, time.Now().Location())}
+// WithAuditLog records every change made to a customer in the audit log.
+func WithAuditLog(r audit.Repository) Option {
+	return func(s *service) {
+		s.auditLog = r
+	}
+}
+
+// WithOutbox stores a domain event in the outbox for every change made to a customer.
+func WithOutbox(r outbox.Repository) Option {
+	return func(s *service) {
+		s.outbox = r
+	}
+}
 
-	if customerNumberExist := s.repository.ExistsByCustomerNumberWithContext(ctx, *input.CustomerNumber); customerNumberExist {
-		return dto.ResultCustomerRequest{}, ErrorCustomerNumberAlreadyExist
+// WithAttributeSchema checks the custom attributes of the customers saved or
+// updated against schema. Without it they are stored as given.
+func WithAttributeSchema(schema AttributeSchema) Option {
+	return func(s *service) {
+		s.attributes = schema
 	}
+}
+
+// WithNumbering allocates the number of the customers created without one from
+// seq, as described by n. Without it the number must always be given.
+func WithNumbering(seq Sequence, n Numbering) Option {
+	return func(s *service) {
+		s.sequence = seq
+		s.numbering = n
+	}
+}
+
+func NewService(r Repository, opts ...Option) Service {
+	s := &service{
+		repository: r,
+		transactor: database.NoopTransactor(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
 
-	customerIdCreated, err := s.repository.SaveWithContext(ctx, sr)
+func (s *service) Save(ctx context.Context, input dto.CreateCustomerRequest) (dto.ResultCustomerRequest, error) {
+	attributes, err := s.validateAttributes(ctx, input.Attributes)
 	if err != nil {
 		return dto.ResultCustomerRequest{}, err
 	}
 
-	customer, err := s.repository.GetWithContext(ctx, customerIdCreated)
+	if err := s.validateParent(ctx, 0, input.ParentID); err != nil {
+		return dto.ResultCustomerRequest{}, err
+	}
+
+	sr := domain.Customer{
+		ID:         0,
+		FirstName:  input.FirstName,
+		LastName:   input.LastName,
+		Status:     domain.CustomerStatusProspect,
+		Attributes: attributes,
+		ParentID:   input.ParentID,
+		CreatedAt:  time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), time.Now().Hour(), time.Now().Minute(), time.Now().Second(), 0, time.Now().Location())}
+
+	allocate := input.CustomerNumber == nil
+	switch {
+	case !allocate:
+		if customerNumberExist := s.repository.ExistsByCustomerNumberWithContext(ctx, *input.CustomerNumber); customerNumberExist {
+			return dto.ResultCustomerRequest{}, ErrorCustomerNumberAlreadyExist
+		}
+		sr.CustomerNumber = *input.CustomerNumber
+	case s.sequence == nil:
+		return dto.ResultCustomerRequest{}, ErrorCustomerNumberRequired
+	case !s.numbering.GapFree:
+		if sr.CustomerNumber, err = s.nextNumber(ctx); err != nil {
+			return dto.ResultCustomerRequest{}, err
+		}
+		allocate = false
+	}
+
+	var customer dto.ResultCustomerRequest
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.lockParent(ctx, 0, input.ParentID); err != nil {
+			return err
+		}
+
+		if allocate {
+			number, err := s.nextNumber(ctx)
+			if err != nil {
+				return err
+			}
+			sr.CustomerNumber = number
+		}
+
+		customerIdCreated, err := s.repository.SaveWithContext(ctx, sr)
+		if err != nil {
+			return err
+		}
+
+		customer, err = s.repository.GetWithContext(ctx, customerIdCreated)
+		if err != nil {
+			return err
+		}
+
+		if err := s.record(ctx, domain.AuditActionCreate, customerIdCreated, nil, customer); err != nil {
+			return err
+		}
+
+		return s.emit(ctx, domain.EventCustomerCreated, customerIdCreated, customer)
+	})
 	if err != nil {
 		return dto.ResultCustomerRequest{}, err
 	}
@@ -57,6 +187,26 @@ func (s *service) Save(ctx context.Context, input dto.CreateCustomerRequest) (dt
 	return customer, nil
 }
 
+// nextNumber allocates the next customer number, skipping the numbers already
+// given explicitly to other customers.
+func (s *service) nextNumber(ctx context.Context) (int, error) {
+	for {
+		value, err := s.sequence.NextWithContext(ctx, customerNumberSequence)
+		if err != nil {
+			return 0, err
+		}
+
+		number, err := s.numbering.Format(value)
+		if err != nil {
+			return 0, err
+		}
+
+		if !s.repository.ExistsByCustomerNumberWithContext(ctx, number) {
+			return number, nil
+		}
+	}
+}
+
 func (s *service) GetAll(ctx context.Context) ([]dto.ResultCustomerRequest, error) {
 	return s.repository.GetAllWithContext(ctx)
 }
@@ -66,7 +216,22 @@ func (s *service) Delete(ctx context.Context, id int) error {
 		return ErrorCustomerNotFound
 	}
 
-	return s.repository.DeleteWithContext(ctx, id)
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.snapshot(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := s.repository.DeleteWithContext(ctx, id); err != nil {
+			return err
+		}
+
+		if err := s.record(ctx, domain.AuditActionDelete, id, before, nil); err != nil {
+			return err
+		}
+
+		return s.emit(ctx, domain.EventCustomerDeleted, id, before)
+	})
 }
 
 func (s *service) Update(ctx context.Context, input dto.UpdateCustomerRequest, id int) (dto.ResultCustomerRequest, error) {
@@ -80,19 +245,50 @@ func (s *service) Update(ctx context.Context, input dto.UpdateCustomerRequest, i
 		}
 	}
 
+	attributes, err := s.validateAttributes(ctx, input.Attributes)
+	if err != nil {
+		return dto.ResultCustomerRequest{}, err
+	}
+
+	if err := s.validateParent(ctx, id, input.ParentID); err != nil {
+		return dto.ResultCustomerRequest{}, err
+	}
+
 	sr := domain.Customer{
 		ID:             id,
 		CustomerNumber: *input.CustomerNumber,
 		FirstName:      input.FirstName,
 		LastName:       input.LastName,
-		UpdatedAt:      time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), time.Now().Hour(), time.Now().Minute(),time.Now().Second(), 0, time.Now().Location())}
+		Attributes:     attributes,
+		ParentID:       input.ParentID,
+		UpdatedAt:      time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), time.Now().Hour(), time.Now().Minute(), time.Now().Second(), 0, time.Now().Location())}
 
-	err := s.repository.UpdateWithContext(ctx, sr)
-	if err != nil {
-		return dto.ResultCustomerRequest{}, err
-	}
+	var sctn dto.ResultCustomerRequest
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.lockParent(ctx, id, input.ParentID); err != nil {
+			return err
+		}
+
+		before, err := s.snapshot(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := s.repository.UpdateWithContext(ctx, sr); err != nil {
+			return err
+		}
+
+		sctn, err = s.repository.GetWithContext(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := s.record(ctx, domain.AuditActionUpdate, id, before, sctn); err != nil {
+			return err
+		}
 
-	sctn, err := s.repository.GetWithContext(ctx, id)
+		return s.emit(ctx, domain.EventCustomerUpdated, id, sctn)
+	})
 	if err != nil {
 		return dto.ResultCustomerRequest{}, err
 	}
@@ -100,9 +296,10 @@ func (s *service) Update(ctx context.Context, input dto.UpdateCustomerRequest, i
 	return sctn, nil
 }
 
+// Get returns a customer, or a *MergedError when it was merged into another one.
 func (s *service) Get(ctx context.Context, id int) (dto.ResultCustomerRequest, error) {
 	if customerExist := s.repository.ExistsByIDWithContext(ctx, id); !customerExist {
-		return dto.ResultCustomerRequest{}, ErrorCustomerNotFound
+		return dto.ResultCustomerRequest{}, s.notFound(ctx, id)
 	}
 
 	customer, err := s.repository.GetWithContext(ctx, id)
@@ -111,3 +308,142 @@ func (s *service) Get(ctx context.Context, id int) (dto.ResultCustomerRequest, e
 	}
 	return customer, nil
 }
+
+func (s *service) GetByCustomerNumber(ctx context.Context, customerNumber int) (dto.ResultCustomerRequest, error) {
+	return s.repository.GetByCustomerNumberWithContext(ctx, customerNumber)
+}
+
+// List returns a page of the customers matching f, the first page of
+// DefaultPageSize customers unless f says otherwise.
+func (s *service) List(ctx context.Context, f dto.CustomerFilter) (dto.CustomerPage, error) {
+	if f.Page < 1 {
+		f.Page = 1
+	}
+	if f.PageSize < 1 {
+		f.PageSize = DefaultPageSize
+	}
+
+	customers, total, err := s.repository.ListWithContext(ctx, f)
+	if err != nil {
+		return dto.CustomerPage{}, err
+	}
+
+	return dto.CustomerPage{Customers: customers, Page: f.Page, PageSize: f.PageSize, Total: total}, nil
+}
+
+// ChangeStatus moves a customer to the requested status, recording the change
+// along with its reason. Moves the lifecycle does not allow are rejected with
+// ErrorIllegalStatusTransition.
+func (s *service) ChangeStatus(ctx context.Context, id int, input dto.CustomerStatusRequest) (dto.ResultCustomerRequest, error) {
+	if customerExist := s.repository.ExistsByIDWithContext(ctx, id); !customerExist {
+		return dto.ResultCustomerRequest{}, ErrorCustomerNotFound
+	}
+
+	var sctn dto.ResultCustomerRequest
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.repository.GetWithContext(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if !CanTransition(before.Status, input.Status) {
+			return fmt.Errorf("%w: from %s to %s", ErrorIllegalStatusTransition, before.Status, input.Status)
+		}
+
+		now := time.Now().Truncate(time.Second)
+		if err := s.repository.UpdateStatusWithContext(ctx, id, before.Status, input.Status, now); err != nil {
+			return err
+		}
+
+		actor := reqctx.UserID(ctx)
+		if actor == "" {
+			actor = audit.AnonymousActor
+		}
+
+		change := domain.CustomerStatusChange{
+			CustomerID: id,
+			From:       before.Status,
+			To:         input.Status,
+			Reason:     input.Reason,
+			Actor:      actor,
+			CreatedAt:  now,
+		}
+		if _, err := s.repository.SaveStatusChangeWithContext(ctx, change); err != nil {
+			return err
+		}
+
+		sctn, err = s.repository.GetWithContext(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := s.record(ctx, domain.AuditActionUpdate, id, before, sctn); err != nil {
+			return err
+		}
+
+		return s.emit(ctx, domain.EventCustomerUpdated, id, sctn)
+	})
+	if err != nil {
+		return dto.ResultCustomerRequest{}, err
+	}
+
+	return sctn, nil
+}
+
+// CanTransition reports whether a customer may move from one status to another.
+func CanTransition(from, to string) bool {
+	for _, allowed := range transitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// validateAttributes checks attributes against the schema, when there is one.
+func (s *service) validateAttributes(ctx context.Context, attributes domain.Attributes) (domain.Attributes, error) {
+	if s.attributes == nil {
+		return attributes, nil
+	}
+	return s.attributes.Validate(ctx, attributes)
+}
+
+// snapshot returns the current state of a customer for the audit log and the
+// outbox, or nil when both are disabled.
+func (s *service) snapshot(ctx context.Context, id int) (any, error) {
+	if s.auditLog == nil && s.outbox == nil {
+		return nil, nil
+	}
+	return s.repository.GetWithContext(ctx, id)
+}
+
+// record writes an audit entry, within the transaction carried by ctx, when the
+// audit log is enabled.
+func (s *service) record(ctx context.Context, action string, id int, before, after any) error {
+	if s.auditLog == nil {
+		return nil
+	}
+
+	entry, err := audit.NewEntry(ctx, action, id, before, after)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.auditLog.SaveWithContext(ctx, entry)
+	return err
+}
+
+// emit stores a domain event in the outbox, within the transaction carried by ctx,
+// when the outbox is enabled.
+func (s *service) emit(ctx context.Context, eventType string, id int, payload any) error {
+	if s.outbox == nil {
+		return nil
+	}
+
+	event, err := outbox.NewEvent(eventType, id, payload)
+	if err != nil {
+		return err
+	}
+
+	return s.outbox.SaveWithContext(ctx, event)
+}
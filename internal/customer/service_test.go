@@ -3,13 +3,21 @@ package customer
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/danilosano/web-golang-api/internal/domain"
 	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/reqctx"
+	attributeMocks "github.com/danilosano/web-golang-api/pkg/tests/attributes"
+	auditMocks "github.com/danilosano/web-golang-api/pkg/tests/audit"
 	mocks "github.com/danilosano/web-golang-api/pkg/tests/customers"
+	outboxMocks "github.com/danilosano/web-golang-api/pkg/tests/outbox"
+	sequenceMocks "github.com/danilosano/web-golang-api/pkg/tests/sequence"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func createService(t *testing.T) (Service, *mocks.CustomersRepositoryMock, context.Context) {
@@ -75,6 +83,27 @@ func TestGetAll(t *testing.T) {
 	})
 }
 
+func TestList(t *testing.T) {
+	t.Run("Without pagination, the first page of the default size is requested.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("ListWithContext", ctx, dto.CustomerFilter{LastName: "Sa", Page: 1, PageSize: DefaultPageSize}).Return(mockedCustomerList, 1, nil)
+
+		page, err := service.List(ctx, dto.CustomerFilter{LastName: "Sa"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, dto.CustomerPage{Customers: mockedCustomerList, Page: 1, PageSize: DefaultPageSize, Total: 1}, page)
+	})
+
+	t.Run("When the backend returns an unexpected error, return the error.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("ListWithContext", ctx, mock.Anything).Return(nil, 0, errors.New("unexpected error"))
+
+		_, err := service.List(ctx, dto.CustomerFilter{Page: 3, PageSize: 10})
+
+		assert.NotNil(t, err)
+	})
+}
+
 func TestCreate(t *testing.T) {
 	t.Run("If it contains the required fields, it will be created.", func(t *testing.T) {
 		service, repoMock, ctx := createService(t)
@@ -84,6 +113,7 @@ func TestCreate(t *testing.T) {
 			CustomerNumber: *input.CustomerNumber,
 			FirstName:      input.FirstName,
 			LastName:       input.LastName,
+			Status:         domain.CustomerStatusProspect,
 			CreatedAt:      time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), time.Now().Hour(), time.Now().Minute(), time.Now().Second(), 0, time.Now().Location())}).Return(1, nil)
 		repoMock.On("GetWithContext", ctx, 1).Return(mockedResultCustomer, nil)
 
@@ -110,6 +140,7 @@ func TestCreate(t *testing.T) {
 			CustomerNumber: *input.CustomerNumber,
 			FirstName:      input.FirstName,
 			LastName:       input.LastName,
+			Status:         domain.CustomerStatusProspect,
 			CreatedAt:      time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), time.Now().Hour(), time.Now().Minute(), time.Now().Second(), 0, time.Now().Location())}).Return(1, errors.New("generic error"))
 
 		_, err := service.Save(ctx, input)
@@ -125,6 +156,7 @@ func TestCreate(t *testing.T) {
 			CustomerNumber: *input.CustomerNumber,
 			FirstName:      input.FirstName,
 			LastName:       input.LastName,
+			Status:         domain.CustomerStatusProspect,
 			CreatedAt:      time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), time.Now().Hour(), time.Now().Minute(), time.Now().Second(), 0, time.Now().Location())}).Return(1, nil)
 		repoMock.On("GetWithContext", ctx, 1).Return(domain.Customer{}, errors.New("generic error"))
 
@@ -139,12 +171,26 @@ func TestGet(t *testing.T) {
 		service, repoMock, ctx := createService(t)
 
 		repoMock.On("ExistsByIDWithContext", ctx, 1).Return(false)
+		repoMock.On("GetMergedIntoWithContext", ctx, 1).Return(0, ErrorCustomerNotFound)
 
 		_, err := service.Get(ctx, 1)
 		assert.NotNil(t, err)
 		assert.Equal(t, ErrorCustomerNotFound, err)
 	})
 
+	t.Run("If the customer was merged, the error points to the customer it was merged into.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("ExistsByIDWithContext", ctx, 1).Return(false)
+		repoMock.On("GetMergedIntoWithContext", ctx, 1).Return(7, nil)
+
+		_, err := service.Get(ctx, 1)
+		var merged *MergedError
+		if assert.ErrorAs(t, err, &merged) {
+			assert.Equal(t, 7, merged.TargetID)
+		}
+		assert.ErrorIs(t, err, ErrorCustomerNotFound)
+	})
+
 	t.Run("If the element searched for by id exists, it will return the requested element information.", func(t *testing.T) {
 		service, repoMock, ctx := createService(t)
 
@@ -275,3 +321,310 @@ func TestDelete(t *testing.T) {
 		assert.Equal(t, errors.New("generic error"), err)
 	})
 }
+
+func TestAttributeSchema(t *testing.T) {
+	t.Run("The attributes are stored as normalized by the schema.", func(t *testing.T) {
+		repoMock := new(mocks.CustomersRepositoryMock)
+		schemaMock := new(attributeMocks.AttributeServiceMock)
+		service := NewService(repoMock, WithAttributeSchema(schemaMock))
+		ctx := context.Background()
+		withAttributes := input
+		withAttributes.Attributes = domain.Attributes{"employees": "250"}
+		schemaMock.On("Validate", ctx, withAttributes.Attributes).Return(domain.Attributes{"employees": 250.0}, nil)
+		repoMock.On("ExistsByCustomerNumberWithContext", ctx, *input.CustomerNumber).Return(false)
+		repoMock.On("SaveWithContext", ctx, mock.MatchedBy(func(c domain.Customer) bool {
+			return c.Attributes["employees"] == 250.0
+		})).Return(1, nil)
+		repoMock.On("GetWithContext", ctx, 1).Return(mockedResultCustomer, nil)
+
+		_, err := service.Save(ctx, withAttributes)
+		assert.Nil(t, err)
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("When the schema rejects the attributes, the customer is not updated.", func(t *testing.T) {
+		repoMock := new(mocks.CustomersRepositoryMock)
+		schemaMock := new(attributeMocks.AttributeServiceMock)
+		service := NewService(repoMock, WithAttributeSchema(schemaMock))
+		ctx := context.Background()
+		schemaErr := errors.New("invalid attribute: tier is required")
+		repoMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("ExistsByCustomerNumberAndIDWithContext", ctx, 1, *inputUpdate.CustomerNumber).Return(true)
+		schemaMock.On("Validate", ctx, inputUpdate.Attributes).Return(nil, schemaErr)
+
+		_, err := service.Update(ctx, inputUpdate, 1)
+		assert.Equal(t, schemaErr, err)
+		repoMock.AssertNotCalled(t, "UpdateWithContext", mock.Anything, mock.Anything)
+	})
+}
+
+// txKey marks the contexts of the functions run by markingTransactor.
+type txKey struct{}
+
+// markingTransactor runs functions with a marked context, telling the calls made
+// within the transaction from the others.
+type markingTransactor struct{}
+
+func (markingTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(context.WithValue(ctx, txKey{}, true))
+}
+
+func inTransaction(inside bool) any {
+	return mock.MatchedBy(func(ctx context.Context) bool {
+		return (ctx.Value(txKey{}) != nil) == inside
+	})
+}
+
+func TestNumbering(t *testing.T) {
+	numbering := Numbering{Prefix: 7, Width: 5, CheckDigit: true}
+	withoutNumber := dto.CreateCustomerRequest{FirstName: "Danilo", LastName: "Sano"}
+
+	t.Run("Without numbering, the customer number is required.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+
+		_, err := service.Save(ctx, withoutNumber)
+		assert.Equal(t, ErrorCustomerNumberRequired, err)
+		repoMock.AssertNotCalled(t, "SaveWithContext", mock.Anything, mock.Anything)
+	})
+
+	t.Run("The next free number is allocated, skipping those given explicitly.", func(t *testing.T) {
+		repoMock := new(mocks.CustomersRepositoryMock)
+		sequenceMock := new(sequenceMocks.SequenceRepositoryMock)
+		service := NewService(repoMock, WithTransactor(markingTransactor{}), WithNumbering(sequenceMock, numbering))
+		ctx := context.Background()
+		sequenceMock.On("NextWithContext", inTransaction(false), "customer_number").Return(int64(1), nil).Once()
+		sequenceMock.On("NextWithContext", inTransaction(false), "customer_number").Return(int64(2), nil).Once()
+		repoMock.On("ExistsByCustomerNumberWithContext", mock.Anything, 7000011).Return(true)
+		repoMock.On("ExistsByCustomerNumberWithContext", mock.Anything, 7000029).Return(false)
+		repoMock.On("SaveWithContext", mock.Anything, mock.MatchedBy(func(c domain.Customer) bool {
+			return c.CustomerNumber == 7000029
+		})).Return(1, nil)
+		repoMock.On("GetWithContext", mock.Anything, 1).Return(mockedResultCustomer, nil)
+
+		_, err := service.Save(ctx, withoutNumber)
+		assert.Nil(t, err)
+		sequenceMock.AssertExpectations(t)
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("When gap-free, the number is allocated in the transaction creating the customer.", func(t *testing.T) {
+		repoMock := new(mocks.CustomersRepositoryMock)
+		sequenceMock := new(sequenceMocks.SequenceRepositoryMock)
+		gapFree := numbering
+		gapFree.GapFree = true
+		service := NewService(repoMock, WithTransactor(markingTransactor{}), WithNumbering(sequenceMock, gapFree))
+		ctx := context.Background()
+		sequenceMock.On("NextWithContext", inTransaction(true), "customer_number").Return(int64(1), nil)
+		repoMock.On("ExistsByCustomerNumberWithContext", mock.Anything, 7000011).Return(false)
+		repoMock.On("SaveWithContext", mock.Anything, mock.Anything).Return(0, errors.New("generic error"))
+
+		_, err := service.Save(ctx, withoutNumber)
+		assert.Equal(t, errors.New("generic error"), err)
+		sequenceMock.AssertExpectations(t)
+	})
+
+	t.Run("When the numbers are exhausted, the customer is not created.", func(t *testing.T) {
+		repoMock := new(mocks.CustomersRepositoryMock)
+		sequenceMock := new(sequenceMocks.SequenceRepositoryMock)
+		service := NewService(repoMock, WithNumbering(sequenceMock, numbering))
+		ctx := context.Background()
+		sequenceMock.On("NextWithContext", ctx, "customer_number").Return(int64(100000), nil)
+
+		_, err := service.Save(ctx, withoutNumber)
+		assert.Equal(t, ErrorCustomerNumbersExhausted, err)
+		repoMock.AssertNotCalled(t, "SaveWithContext", mock.Anything, mock.Anything)
+	})
+
+	t.Run("An explicit number is kept as given.", func(t *testing.T) {
+		repoMock := new(mocks.CustomersRepositoryMock)
+		sequenceMock := new(sequenceMocks.SequenceRepositoryMock)
+		service := NewService(repoMock, WithNumbering(sequenceMock, numbering))
+		ctx := context.Background()
+		repoMock.On("ExistsByCustomerNumberWithContext", ctx, *input.CustomerNumber).Return(false)
+		repoMock.On("SaveWithContext", ctx, mock.MatchedBy(func(c domain.Customer) bool {
+			return c.CustomerNumber == *input.CustomerNumber
+		})).Return(1, nil)
+		repoMock.On("GetWithContext", ctx, 1).Return(mockedResultCustomer, nil)
+
+		_, err := service.Save(ctx, input)
+		assert.Nil(t, err)
+		sequenceMock.AssertNotCalled(t, "NextWithContext", mock.Anything, mock.Anything)
+	})
+}
+
+func createAuditedService(t *testing.T) (Service, *mocks.CustomersRepositoryMock, *auditMocks.AuditRepositoryMock, context.Context) {
+	t.Helper()
+	repoMock := new(mocks.CustomersRepositoryMock)
+	auditMock := new(auditMocks.AuditRepositoryMock)
+	service := NewService(repoMock, WithAuditLog(auditMock))
+	ctx := reqctx.WithRequestID(reqctx.WithUserID(context.Background(), "agent-1"), "req-1")
+	return service, repoMock, auditMock, ctx
+}
+
+func TestAuditLog(t *testing.T) {
+	t.Run("Creating a customer records who created it and its new state.", func(t *testing.T) {
+		service, repoMock, auditMock, ctx := createAuditedService(t)
+		repoMock.On("ExistsByCustomerNumberWithContext", ctx, *input.CustomerNumber).Return(false)
+		repoMock.On("SaveWithContext", ctx, mock.Anything).Return(1, nil)
+		repoMock.On("GetWithContext", ctx, 1).Return(mockedResultCustomer, nil)
+		auditMock.On("SaveWithContext", ctx, mock.MatchedBy(func(e domain.AuditEntry) bool {
+			return e.Actor == "agent-1" && e.RequestID == "req-1" && e.Action == domain.AuditActionCreate &&
+				e.CustomerID == 1 && e.Before == nil && len(e.After) > 0
+		})).Return(1, nil)
+
+		_, err := service.Save(ctx, input)
+		assert.Nil(t, err)
+		auditMock.AssertExpectations(t)
+	})
+
+	t.Run("Updating a customer records its state before and after the change.", func(t *testing.T) {
+		service, repoMock, auditMock, ctx := createAuditedService(t)
+		before := mockedResultCustomer
+		before.FirstName = "Old"
+		repoMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("ExistsByCustomerNumberAndIDWithContext", ctx, 1, *inputUpdate.CustomerNumber).Return(true)
+		repoMock.On("GetWithContext", ctx, 1).Return(before, nil).Once()
+		repoMock.On("UpdateWithContext", ctx, mock.Anything).Return(nil)
+		repoMock.On("GetWithContext", ctx, 1).Return(mockedResultCustomer, nil).Once()
+		auditMock.On("SaveWithContext", ctx, mock.MatchedBy(func(e domain.AuditEntry) bool {
+			return e.Action == domain.AuditActionUpdate &&
+				strings.Contains(string(e.Before), `"first_name":"Old"`) &&
+				strings.Contains(string(e.After), `"first_name":"Danilo"`)
+		})).Return(1, nil)
+
+		_, err := service.Update(ctx, inputUpdate, 1)
+		assert.Nil(t, err)
+		auditMock.AssertExpectations(t)
+	})
+
+	t.Run("Deleting a customer records its last state, and a failure to record aborts the deletion.", func(t *testing.T) {
+		service, repoMock, auditMock, ctx := createAuditedService(t)
+		repoMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("GetWithContext", ctx, 1).Return(mockedResultCustomer, nil)
+		repoMock.On("DeleteWithContext", ctx, 1).Return(nil)
+		auditMock.On("SaveWithContext", ctx, mock.MatchedBy(func(e domain.AuditEntry) bool {
+			return e.Action == domain.AuditActionDelete && len(e.Before) > 0 && e.After == nil
+		})).Return(0, errors.New("generic error"))
+
+		err := service.Delete(ctx, 1)
+		assert.Equal(t, errors.New("generic error"), err)
+	})
+}
+
+func TestOutbox(t *testing.T) {
+	t.Run("Each change stores the matching domain event in the outbox.", func(t *testing.T) {
+		repoMock := new(mocks.CustomersRepositoryMock)
+		outboxMock := new(outboxMocks.OutboxRepositoryMock)
+		service := NewService(repoMock, WithOutbox(outboxMock))
+		ctx := context.Background()
+
+		repoMock.On("ExistsByCustomerNumberWithContext", ctx, *input.CustomerNumber).Return(false)
+		repoMock.On("SaveWithContext", ctx, mock.Anything).Return(1, nil)
+		repoMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("GetWithContext", ctx, 1).Return(mockedResultCustomer, nil)
+		repoMock.On("DeleteWithContext", ctx, 1).Return(nil)
+		for _, eventType := range []string{domain.EventCustomerCreated, domain.EventCustomerDeleted} {
+			eventType := eventType
+			outboxMock.On("SaveWithContext", ctx, mock.MatchedBy(func(e domain.Event) bool {
+				return e.Type == eventType && e.CustomerID == 1 && e.ID != "" &&
+					strings.Contains(string(e.Payload), `"first_name":"Danilo"`)
+			})).Return(nil).Once()
+		}
+
+		_, err := service.Save(ctx, input)
+		assert.Nil(t, err)
+		err = service.Delete(ctx, 1)
+		assert.Nil(t, err)
+		outboxMock.AssertExpectations(t)
+	})
+
+	t.Run("When the event cannot be stored, the change fails.", func(t *testing.T) {
+		repoMock := new(mocks.CustomersRepositoryMock)
+		outboxMock := new(outboxMocks.OutboxRepositoryMock)
+		service := NewService(repoMock, WithOutbox(outboxMock))
+		ctx := context.Background()
+
+		repoMock.On("ExistsByCustomerNumberWithContext", ctx, *input.CustomerNumber).Return(false)
+		repoMock.On("SaveWithContext", ctx, mock.Anything).Return(1, nil)
+		repoMock.On("GetWithContext", ctx, 1).Return(mockedResultCustomer, nil)
+		outboxMock.On("SaveWithContext", ctx, mock.Anything).Return(errors.New("generic error"))
+
+		_, err := service.Save(ctx, input)
+		assert.Equal(t, errors.New("generic error"), err)
+	})
+}
+
+func TestChangeStatus(t *testing.T) {
+	prospect := mockedResultCustomer
+	prospect.Status = domain.CustomerStatusProspect
+	active := mockedResultCustomer
+	active.Status = domain.CustomerStatusActive
+
+	t.Run("An allowed transition updates the status and records who made it and why.", func(t *testing.T) {
+		service, repoMock, auditMock, ctx := createAuditedService(t)
+		repoMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("GetWithContext", ctx, 1).Return(prospect, nil).Once()
+		repoMock.On("UpdateStatusWithContext", ctx, 1, domain.CustomerStatusProspect, domain.CustomerStatusActive, mock.Anything).Return(nil)
+		repoMock.On("SaveStatusChangeWithContext", ctx, mock.MatchedBy(func(c domain.CustomerStatusChange) bool {
+			return c.CustomerID == 1 && c.From == domain.CustomerStatusProspect && c.To == domain.CustomerStatusActive &&
+				c.Reason == "Signed the contract" && c.Actor == "agent-1"
+		})).Return(1, nil)
+		repoMock.On("GetWithContext", ctx, 1).Return(active, nil).Once()
+		auditMock.On("SaveWithContext", ctx, mock.MatchedBy(func(e domain.AuditEntry) bool {
+			return e.Action == domain.AuditActionUpdate &&
+				strings.Contains(string(e.Before), `"status":"prospect"`) &&
+				strings.Contains(string(e.After), `"status":"active"`)
+		})).Return(1, nil)
+
+		result, err := service.ChangeStatus(ctx, 1, dto.CustomerStatusRequest{Status: domain.CustomerStatusActive, Reason: "Signed the contract"})
+		assert.Nil(t, err)
+		assert.Equal(t, active, result)
+		repoMock.AssertExpectations(t)
+		auditMock.AssertExpectations(t)
+	})
+
+	t.Run("A transition the lifecycle does not allow is rejected without changing the customer.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		closed := mockedResultCustomer
+		closed.Status = domain.CustomerStatusClosed
+		repoMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("GetWithContext", ctx, 1).Return(closed, nil)
+
+		_, err := service.ChangeStatus(ctx, 1, dto.CustomerStatusRequest{Status: domain.CustomerStatusActive, Reason: "Came back"})
+		assert.ErrorIs(t, err, ErrorIllegalStatusTransition)
+		assert.EqualError(t, err, "illegal status transition: from closed to active")
+		repoMock.AssertNotCalled(t, "UpdateStatusWithContext", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("When the status changed concurrently, the transition is rejected without recording it.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("GetWithContext", ctx, 1).Return(prospect, nil)
+		repoMock.On("UpdateStatusWithContext", ctx, 1, domain.CustomerStatusProspect, domain.CustomerStatusActive, mock.Anything).
+			Return(fmt.Errorf("%w: the status is no longer prospect", ErrorIllegalStatusTransition))
+
+		_, err := service.ChangeStatus(ctx, 1, dto.CustomerStatusRequest{Status: domain.CustomerStatusActive, Reason: "Signed the contract"})
+		assert.ErrorIs(t, err, ErrorIllegalStatusTransition)
+		repoMock.AssertNotCalled(t, "SaveStatusChangeWithContext", mock.Anything, mock.Anything)
+	})
+
+	t.Run("When the customer does not exist, an error will be returned.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("ExistsByIDWithContext", ctx, 1).Return(false)
+
+		_, err := service.ChangeStatus(ctx, 1, dto.CustomerStatusRequest{Status: domain.CustomerStatusActive, Reason: "Signed"})
+		assert.Equal(t, ErrorCustomerNotFound, err)
+	})
+}
+
+func TestCanTransition(t *testing.T) {
+	t.Run("Only the moves of the lifecycle are allowed.", func(t *testing.T) {
+		assert.True(t, CanTransition(domain.CustomerStatusProspect, domain.CustomerStatusActive))
+		assert.True(t, CanTransition(domain.CustomerStatusSuspended, domain.CustomerStatusActive))
+		assert.True(t, CanTransition(domain.CustomerStatusActive, domain.CustomerStatusClosed))
+		assert.False(t, CanTransition(domain.CustomerStatusProspect, domain.CustomerStatusSuspended))
+		assert.False(t, CanTransition(domain.CustomerStatusActive, domain.CustomerStatusActive))
+		assert.False(t, CanTransition(domain.CustomerStatusClosed, domain.CustomerStatusActive))
+		assert.False(t, CanTransition("unknown", domain.CustomerStatusActive))
+	})
+}
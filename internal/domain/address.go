@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+const (
+	AddressTypeBilling  = "billing"
+	AddressTypeShipping = "shipping"
+)
+
+// Address is a billing or shipping address of a customer. A customer has at most
+// one default address of each type.
+type Address struct {
+	ID         int        `json:"id" xml:"id"`
+	CustomerID int        `json:"customer_id" xml:"customer_id"`
+	Type       string     `json:"type" xml:"type"`
+	Line1      string     `json:"line1" xml:"line1"`
+	Line2      string     `json:"line2,omitempty" xml:"line2,omitempty"`
+	City       string     `json:"city" xml:"city"`
+	Region     string     `json:"region,omitempty" xml:"region,omitempty"`
+	PostalCode string     `json:"postal_code" xml:"postal_code"`
+	Country    string     `json:"country" xml:"country"`
+	Default    bool       `json:"default" xml:"default"`
+	CreatedAt  time.Time  `json:"created_at" xml:"created_at"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty" xml:"updated_at,omitempty"`
+}
+
+// LastModified returns when the address was last changed.
+func (a Address) LastModified() time.Time {
+	if a.UpdatedAt != nil && a.UpdatedAt.After(a.CreatedAt) {
+		return *a.UpdatedAt
+	}
+	return a.CreatedAt
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// Attachment describes a file attached to a customer, such as a signed contract
+// or the scan of an ID. Its content is kept in a blob storage under StorageKey,
+// and Checksum is its hex-encoded SHA-256.
+type Attachment struct {
+	ID          int       `json:"id" xml:"id"`
+	CustomerID  int       `json:"customer_id" xml:"customer_id"`
+	FileName    string    `json:"file_name" xml:"file_name"`
+	ContentType string    `json:"content_type" xml:"content_type"`
+	Size        int64     `json:"size" xml:"size"`
+	Checksum    string    `json:"checksum" xml:"checksum"`
+	UploadedBy  string    `json:"uploaded_by" xml:"uploaded_by"`
+	StorageKey  string    `json:"-" xml:"-"`
+	CreatedAt   time.Time `json:"created_at" xml:"created_at"`
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	AttributeTypeString = "string"
+	AttributeTypeNumber = "number"
+	AttributeTypeDate   = "date"
+	AttributeTypeEnum   = "enum"
+)
+
+// AttributeDefinition describes a custom attribute of the customers: its name,
+// the type of its values and whether every customer must have it. Enum
+// attributes take one of their options.
+type AttributeDefinition struct {
+	ID        int        `json:"id" xml:"id"`
+	Name      string     `json:"name" xml:"name"`
+	Type      string     `json:"type" xml:"type"`
+	Required  bool       `json:"required" xml:"required"`
+	Options   []string   `json:"options,omitempty" xml:"options>option,omitempty"`
+	CreatedAt time.Time  `json:"created_at" xml:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty" xml:"updated_at,omitempty"`
+}
+
+// Attributes holds the custom attributes of a customer by name. Numbers are
+// float64 and dates strings in the 2006-01-02 layout. They are stored as a JSON
+// object and written in XML as <attribute name="...">value</attribute> elements.
+type Attributes map[string]any
+
+// Value stores the attributes as a JSON object, or NULL when there are none.
+func (a Attributes) Value() (driver.Value, error) {
+	if len(a) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(a)
+}
+
+// Scan reads the attributes from their JSON column.
+func (a *Attributes) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, a)
+	case string:
+		return json.Unmarshal([]byte(v), a)
+	default:
+		return fmt.Errorf("cannot scan %T into attributes", src)
+	}
+}
+
+type xmlAttribute struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
+}
+
+// MarshalXML writes the attributes ordered by name.
+func (a Attributes) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	names := make([]string, 0, len(a))
+	for name := range a {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	items := struct {
+		Attributes []xmlAttribute `xml:"attribute"`
+	}{}
+	for _, name := range names {
+		value := fmt.Sprint(a[name])
+		if f, ok := a[name].(float64); ok {
+			value = strconv.FormatFloat(f, 'f', -1, 64)
+		}
+		items.Attributes = append(items.Attributes, xmlAttribute{Name: name, Value: value})
+	}
+	return e.EncodeElement(items, start)
+}
+
+// UnmarshalXML reads every value as a string, left to the attribute definitions
+// to convert.
+func (a *Attributes) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var items struct {
+		Attributes []xmlAttribute `xml:"attribute"`
+	}
+	if err := d.DecodeElement(&items, &start); err != nil {
+		return err
+	}
+
+	*a = make(Attributes, len(items.Attributes))
+	for _, item := range items.Attributes {
+		(*a)[item.Name] = item.Value
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+	// AuditActionMerge is recorded on both customers of a merge, Before holding
+	// the source and After the target.
+	AuditActionMerge = "merge"
+)
+
+// AuditEntry records a change made to a customer. Actor is the X-User-ID header
+// of the request, or the x-user-id gRPC metadata, which only identifies the user
+// reliably when a gateway in front of the API authenticates it and sets it.
+type AuditEntry struct {
+	ID         int             `json:"id"`
+	Actor      string          `json:"actor"`
+	Action     string          `json:"action"`
+	CustomerID int             `json:"customer_id"`
+	Before     json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After      json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+	RequestID  string          `json:"request_id,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+const (
+	ContactTypeEmail = "email"
+	ContactTypePhone = "phone"
+)
+
+// Contact is an email address or a phone number of a customer. Emails are stored
+// in lower case and phones in E.164 format. A customer has at most one primary
+// contact of each type, and a primary email belongs to a single customer.
+type Contact struct {
+	ID         int        `json:"id" xml:"id"`
+	CustomerID int        `json:"customer_id" xml:"customer_id"`
+	Type       string     `json:"type" xml:"type"`
+	Label      string     `json:"label,omitempty" xml:"label,omitempty"`
+	Value      string     `json:"value" xml:"value"`
+	Primary    bool       `json:"primary" xml:"primary"`
+	Verified   bool       `json:"verified" xml:"verified"`
+	CreatedAt  time.Time  `json:"created_at" xml:"created_at"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty" xml:"updated_at,omitempty"`
+}
+
+// LastModified returns when the contact was last changed.
+func (c Contact) LastModified() time.Time {
+	if c.UpdatedAt != nil && c.UpdatedAt.After(c.CreatedAt) {
+		return *c.UpdatedAt
+	}
+	return c.CreatedAt
+}
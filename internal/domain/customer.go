@@ -2,12 +2,36 @@ package domain
 
 import "time"
 
+// Customer statuses, in lifecycle order. A customer starts as a prospect, and
+// closed customers stay closed.
+const (
+	CustomerStatusProspect  = "prospect"
+	CustomerStatusActive    = "active"
+	CustomerStatusSuspended = "suspended"
+	CustomerStatusClosed    = "closed"
+)
+
 type Customer struct {
-	ID             int       `json:"id" db:"customer_id"`
-	CustomerNumber int       `json:"customer_number"`
-	FirstName      string    `json:"first_name"`
-	LastName       string    `json:"last_name"`
-	CreatedAt      time.Time `json:"created_at,omitempty"`
-	UpdatedAt      time.Time `json:"updated_at,omitempty"`
-	DeletedAt      time.Time `json:"deleted_at,omitempty"`
+	ID             int        `json:"id" db:"customer_id"`
+	CustomerNumber int        `json:"customer_number"`
+	FirstName      string     `json:"first_name"`
+	LastName       string     `json:"last_name"`
+	Status         string     `json:"status"`
+	Attributes     Attributes `json:"attributes,omitempty"`
+	ParentID       *int       `json:"parent_id,omitempty"`
+	CreatedAt      time.Time  `json:"created_at,omitempty"`
+	UpdatedAt      time.Time  `json:"updated_at,omitempty"`
+	DeletedAt      time.Time  `json:"deleted_at,omitempty"`
+}
+
+// CustomerStatusChange records a transition of a customer from one status to
+// another, who made it and why.
+type CustomerStatusChange struct {
+	ID         int       `json:"id"`
+	CustomerID int       `json:"customer_id"`
+	From       string    `json:"from"`
+	To         string    `json:"to"`
+	Reason     string    `json:"reason"`
+	Actor      string    `json:"actor"`
+	CreatedAt  time.Time `json:"created_at"`
 }
@@ -0,0 +1,14 @@
+package dto
+
+// AddressRequest creates or replaces an address. The postal code is validated
+// against the format of the country, an ISO 3166-1 alpha-2 code such as "BR".
+type AddressRequest struct {
+	Type       string `json:"type" xml:"type" binding:"required,oneof=billing shipping"`
+	Line1      string `json:"line1" xml:"line1" binding:"required,varchar=255"`
+	Line2      string `json:"line2" xml:"line2" binding:"omitempty,varchar=255"`
+	City       string `json:"city" xml:"city" binding:"required,varchar=100"`
+	Region     string `json:"region" xml:"region" binding:"omitempty,varchar=100"`
+	PostalCode string `json:"postal_code" xml:"postal_code" binding:"required,varchar=20,postcode_iso3166_alpha2_field=Country"`
+	Country    string `json:"country" xml:"country" binding:"required,iso3166_1_alpha2"`
+	Default    bool   `json:"default" xml:"default"`
+}
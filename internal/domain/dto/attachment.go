@@ -0,0 +1,22 @@
+package dto
+
+import (
+	"io"
+	"mime/multipart"
+)
+
+// AttachmentRequest is the multipart form uploading an attachment. Checksum is
+// the hex-encoded SHA-256 of the file, checked against the content received
+// when given.
+type AttachmentRequest struct {
+	File     *multipart.FileHeader `form:"file" binding:"required" swaggerignore:"true"`
+	Checksum string                `form:"sha256" binding:"omitempty,len=64,hexadecimal"`
+}
+
+// AttachmentFile is an uploaded file along with the checksum expected of it.
+type AttachmentFile struct {
+	Name     string
+	Size     int64
+	Content  io.ReadSeeker
+	Checksum string
+}
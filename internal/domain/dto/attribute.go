@@ -0,0 +1,10 @@
+package dto
+
+// AttributeDefinitionRequest defines a custom attribute of the customers. Options
+// list the values of an enum attribute and are only allowed for enums.
+type AttributeDefinitionRequest struct {
+	Name     string   `json:"name" xml:"name" binding:"required,varchar=50"`
+	Type     string   `json:"type" xml:"type" binding:"required,oneof=string number date enum"`
+	Required bool     `json:"required" xml:"required"`
+	Options  []string `json:"options" xml:"options>option" binding:"omitempty,max=100,dive,required,varchar=100"`
+}
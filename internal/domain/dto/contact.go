@@ -0,0 +1,13 @@
+package dto
+
+// ContactRequest creates or replaces a contact. The value is an email address or
+// a phone number, depending on the type. Phones are normalized to E.164, and
+// national numbers need the region, an ISO 3166-1 alpha-2 code such as "BR".
+type ContactRequest struct {
+	Type     string `json:"type" xml:"type" binding:"required,oneof=email phone"`
+	Label    string `json:"label" xml:"label" binding:"omitempty,oneof=home work mobile other"`
+	Value    string `json:"value" xml:"value" binding:"required,varchar=255"`
+	Region   string `json:"region" xml:"region" binding:"omitempty,iso3166_1_alpha2"`
+	Primary  bool   `json:"primary" xml:"primary"`
+	Verified bool   `json:"verified" xml:"verified"`
+}
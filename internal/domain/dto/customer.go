@@ -1,57 +1,104 @@
 package dto
 
 import (
-	"errors"
 	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
 )
 
+// CreateCustomerRequest creates a customer. CustomerNumber may be left out when
+// the service allocates the numbers. ParentID makes the customer a subsidiary of
+// another one.
 type CreateCustomerRequest struct {
-	CustomerNumber *int   `json:"customer_number"`
-	FirstName      string `json:"first_name"`
-	LastName       string `json:"last_name"`
+	CustomerNumber *int              `json:"customer_number" xml:"customer_number" binding:"omitempty,gt=0"`
+	FirstName      string            `json:"first_name" xml:"first_name" binding:"required,varchar=100"`
+	LastName       string            `json:"last_name" xml:"last_name" binding:"required,varchar=100"`
+	Attributes     domain.Attributes `json:"attributes,omitempty" xml:"attributes,omitempty" binding:"omitempty,max=50" swaggertype:"object"`
+	ParentID       *int              `json:"parent_id,omitempty" xml:"parent_id,omitempty" binding:"omitempty,gt=0"`
 }
 
+// UpdateCustomerRequest replaces a customer. Leaving ParentID out detaches it
+// from its parent.
 type UpdateCustomerRequest struct {
-	CustomerNumber *int   `json:"customer_number" binding:"required"`
-	FirstName      string `json:"first_name" binding:"required"`
-	LastName       string `json:"last_name" binding:"required"`
+	CustomerNumber *int              `json:"customer_number" xml:"customer_number" binding:"required,gt=0"`
+	FirstName      string            `json:"first_name" xml:"first_name" binding:"required,varchar=100"`
+	LastName       string            `json:"last_name" xml:"last_name" binding:"required,varchar=100"`
+	Attributes     domain.Attributes `json:"attributes,omitempty" xml:"attributes,omitempty" binding:"omitempty,max=50" swaggertype:"object"`
+	ParentID       *int              `json:"parent_id,omitempty" xml:"parent_id,omitempty" binding:"omitempty,gt=0"`
 }
 
 type ResultCustomerRequest struct {
-	ID             int        `json:"id"`
-	CustomerNumber *int       `json:"customer_number"`
-	FirstName      string     `json:"first_name"`
-	LastName       string     `json:"last_name"`
-	CreatedAt      time.Time  `json:"created_at,omitempty"`
-	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
-}
-
-func (c *CreateCustomerRequest) Validate() error {
-	if c.CustomerNumber == nil {
-		return errors.New("invalid input: customer number is required")
-	} else if *c.CustomerNumber <= 0 {
-		return errors.New("invalid input: customer number must be greater than 0")
-	}
-	if c.FirstName == "" {
-		return errors.New("invalid input: first name is required")
-	}
-	if c.LastName == "" {
-		return errors.New("invalid input: first name is required")
-	}
-	return nil
+	ID             int               `json:"id" xml:"id"`
+	CustomerNumber *int              `json:"customer_number" xml:"customer_number"`
+	FirstName      string            `json:"first_name" xml:"first_name"`
+	LastName       string            `json:"last_name" xml:"last_name"`
+	Status         string            `json:"status" xml:"status"`
+	Attributes     domain.Attributes `json:"attributes,omitempty" xml:"attributes,omitempty" swaggertype:"object"`
+	CreatedAt      time.Time         `json:"created_at,omitempty" xml:"created_at,omitempty"`
+	UpdatedAt      *time.Time        `json:"updated_at,omitempty" xml:"updated_at,omitempty"`
+	ParentID       *int              `json:"parent_id,omitempty" xml:"parent_id,omitempty"`
+	// Addresses, Contacts and Tags are only filled when asked for with ?expand=.
+	Addresses []domain.Address `json:"addresses,omitempty" xml:"addresses>address,omitempty"`
+	Contacts  []domain.Contact `json:"contacts,omitempty" xml:"contacts>contact,omitempty"`
+	Tags      []domain.Tag     `json:"tags,omitempty" xml:"tags>tag,omitempty"`
 }
 
-func (c *UpdateCustomerRequest) Validate() error {
-	if c.CustomerNumber == nil {
-		return errors.New("invalid input: customer number is required")
-	} else if *c.CustomerNumber <= 0 {
-		return errors.New("invalid input: customer number must be greater than 0")
+// CustomerFilter narrows and paginates a customer listing. Empty fields do not filter.
+// Tags selects the customers carrying any of the tags, or all of them when TagMatch
+// is "all". Attributes holds "name:value" pairs, all matched by the customers.
+type CustomerFilter struct {
+	CustomerNumber *int     `json:"customer_number" form:"customer_number" binding:"omitempty,gt=0"`
+	FirstName      string   `json:"first_name" form:"first_name" binding:"omitempty,varchar=100"`
+	LastName       string   `json:"last_name" form:"last_name" binding:"omitempty,varchar=100"`
+	Email          string   `json:"email" form:"email" binding:"omitempty,email,varchar=255"`
+	Status         string   `json:"status" form:"status" binding:"omitempty,oneof=prospect active suspended closed"`
+	Tags           []string `json:"tags" form:"tag" binding:"omitempty,max=20,dive,required,varchar=50"`
+	TagMatch       string   `json:"tag_match" form:"tag_match" binding:"omitempty,oneof=any all"`
+	Attributes     []string `json:"attributes" form:"attr" binding:"omitempty,max=10,dive,required,contains=:,varchar=306"`
+	Page           int      `json:"page" form:"page" binding:"omitempty,gte=1"`
+	PageSize       int      `json:"page_size" form:"page_size" binding:"omitempty,gte=1,lte=100"`
+}
+
+// CustomerStatusRequest moves a customer to another status of its lifecycle.
+type CustomerStatusRequest struct {
+	Status string `json:"status" xml:"status" binding:"required,oneof=prospect active suspended closed"`
+	Reason string `json:"reason" xml:"reason" binding:"required,varchar=255"`
+}
+
+// CustomerTree is a customer along with its subsidiaries, recursively.
+type CustomerTree struct {
+	Customer ResultCustomerRequest `json:"customer" xml:"customer"`
+	Children []CustomerTree        `json:"children,omitempty" xml:"children>child,omitempty"`
+}
+
+type CustomerPage struct {
+	Customers []ResultCustomerRequest `json:"customers" xml:"customers>customer"`
+	Page      int                     `json:"page" xml:"page"`
+	PageSize  int                     `json:"page_size" xml:"page_size"`
+	Total     int                     `json:"total" xml:"total"`
+}
+
+// LastModified returns when the customer, or one of its expanded addresses,
+// contacts or tags, was last changed.
+func (r ResultCustomerRequest) LastModified() time.Time {
+	modified := r.CreatedAt
+	if r.UpdatedAt != nil && r.UpdatedAt.After(modified) {
+		modified = *r.UpdatedAt
+	}
+	for _, a := range r.Addresses {
+		if a.LastModified().After(modified) {
+			modified = a.LastModified()
+		}
 	}
-	if c.FirstName == "" {
-		return errors.New("invalid input: first name is required")
+	for _, c := range r.Contacts {
+		if c.LastModified().After(modified) {
+			modified = c.LastModified()
+		}
 	}
-	if c.LastName == "" {
-		return errors.New("invalid input: first name is required")
+	for _, t := range r.Tags {
+		if t.TaggedAt.After(modified) {
+			modified = t.TaggedAt
+		}
 	}
-	return nil
+	return modified
 }
@@ -0,0 +1,16 @@
+package dto
+
+// DuplicateCandidate is a customer that may be the same person as another one.
+// NameSimilarity goes from 0 to 1, regardless of case, accents and the order of
+// the names, and SharedContacts lists the contact values both customers have.
+type DuplicateCandidate struct {
+	Customer       ResultCustomerRequest `json:"customer" xml:"customer"`
+	NameSimilarity float64               `json:"name_similarity" xml:"name_similarity"`
+	SharedContacts []string              `json:"shared_contacts,omitempty" xml:"shared_contacts>contact,omitempty"`
+}
+
+// MergeRequest merges the source customer into the target one, which must differ.
+type MergeRequest struct {
+	SourceID int `json:"source_id" xml:"source_id" binding:"required,gt=0"`
+	TargetID int `json:"target_id" xml:"target_id" binding:"required,gt=0"`
+}
@@ -0,0 +1,23 @@
+package dto
+
+import "github.com/danilosano/web-golang-api/internal/domain"
+
+// NoteRequest writes a note of a customer. Its author is the authenticated user.
+type NoteRequest struct {
+	Body   string `json:"body" xml:"body" binding:"required,max=10000"`
+	Pinned bool   `json:"pinned" xml:"pinned"`
+}
+
+// TimelineFilter requests a page of the timeline of a customer. Cursor is the
+// next_cursor of the previous page, empty for the first one.
+type TimelineFilter struct {
+	Cursor string `json:"cursor" form:"cursor" binding:"omitempty,max=200"`
+	Limit  int    `json:"limit" form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// TimelinePage is a page of the timeline of a customer, oldest event first.
+// NextCursor is set when more events follow.
+type TimelinePage struct {
+	Events     []domain.TimelineEvent `json:"events"`
+	NextCursor string                 `json:"next_cursor,omitempty"`
+}
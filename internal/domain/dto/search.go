@@ -0,0 +1,19 @@
+package dto
+
+type SearchCustomersRequest struct {
+	Query    string `json:"q" form:"q" binding:"required,min=2,varchar=100"`
+	Page     int    `json:"page" form:"page" binding:"omitempty,gte=1"`
+	PageSize int    `json:"page_size" form:"page_size" binding:"omitempty,gte=1,lte=100"`
+}
+
+type CustomerSearchResult struct {
+	ResultCustomerRequest
+	Score float64 `json:"score"`
+}
+
+type CustomerSearchPage struct {
+	Results  []CustomerSearchResult `json:"results"`
+	Page     int                    `json:"page"`
+	PageSize int                    `json:"page_size"`
+	Total    int                    `json:"total"`
+}
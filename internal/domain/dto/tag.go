@@ -0,0 +1,12 @@
+package dto
+
+// TagRequest puts tags on a customer. Names are matched regardless of case.
+type TagRequest struct {
+	Tags []string `json:"tags" xml:"tags>tag" binding:"required,min=1,max=20,dive,required,varchar=50"`
+}
+
+// BulkTagRequest puts the same tags on several customers at once.
+type BulkTagRequest struct {
+	CustomerIDs []int    `json:"customer_ids" xml:"customer_ids>customer_id" binding:"required,min=1,max=100,dive,gt=0"`
+	Tags        []string `json:"tags" xml:"tags>tag" binding:"required,min=1,max=20,dive,required,varchar=50"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+type WebhookRequest struct {
+	URL        string   `json:"url" binding:"required,http_url,varchar=255"`
+	EventTypes []string `json:"event_types" binding:"required,min=1,dive,oneof=customer.created customer.updated customer.deleted"`
+	Secret     string   `json:"secret" binding:"required,min=16,varchar=100"`
+	Active     *bool    `json:"active"`
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	EventCustomerCreated = "customer.created"
+	EventCustomerUpdated = "customer.updated"
+	EventCustomerDeleted = "customer.deleted"
+)
+
+// Event is a domain event describing a change to a customer. Payload holds the
+// customer as it is after the change, or as it was before a deletion.
+type Event struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	CustomerID int             `json:"customer_id"`
+	Payload    json.RawMessage `json:"payload" swaggertype:"object"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+
+// OutboxEntry is an event waiting in the outbox, with the state of its dispatch:
+// the failed attempts so far, when it may be attempted again, and until when a
+// dispatcher holds it.
+type OutboxEntry struct {
+	Event         Event
+	Attempts      int
+	NextAttemptAt *time.Time
+	LockedUntil   *time.Time
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// Note is a free-text record of an interaction with a customer, such as a call,
+// written by a support agent. Pinned notes are listed first.
+type Note struct {
+	ID         int        `json:"id" xml:"id"`
+	CustomerID int        `json:"customer_id" xml:"customer_id"`
+	Author     string     `json:"author" xml:"author"`
+	Body       string     `json:"body" xml:"body"`
+	Pinned     bool       `json:"pinned" xml:"pinned"`
+	CreatedAt  time.Time  `json:"created_at" xml:"created_at"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty" xml:"updated_at,omitempty"`
+}
+
+// NoteRevision keeps the body a note had before an edit, along with who edited
+// it and when.
+type NoteRevision struct {
+	ID       int       `json:"id" xml:"id"`
+	NoteID   int       `json:"note_id" xml:"note_id"`
+	Body     string    `json:"body" xml:"body"`
+	Editor   string    `json:"editor" xml:"editor"`
+	EditedAt time.Time `json:"edited_at" xml:"edited_at"`
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// Tag is a label put on a customer, such as "vip" or "churn-risk". Names are
+// stored in lower case and a customer carries each tag at most once.
+type Tag struct {
+	Name     string    `json:"name" xml:"name"`
+	TaggedAt time.Time `json:"tagged_at" xml:"tagged_at"`
+}
+
+// TagCount is the number of customers carrying a tag.
+type TagCount struct {
+	Name  string `json:"name" xml:"name"`
+	Count int    `json:"count" xml:"count"`
+}
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+const (
+	TimelineEventAudit        = "audit"
+	TimelineEventNote         = "note"
+	TimelineEventStatusChange = "status_change"
+)
+
+// TimelineEvent is an entry of the timeline of a customer: a change recorded in
+// the audit log, a note or a status change, with the record it comes from.
+type TimelineEvent struct {
+	Type         string                `json:"type"`
+	OccurredAt   time.Time             `json:"occurred_at"`
+	Actor        string                `json:"actor"`
+	Audit        *AuditEntry           `json:"audit,omitempty"`
+	Note         *Note                 `json:"note,omitempty"`
+	StatusChange *CustomerStatusChange `json:"status_change,omitempty"`
+}
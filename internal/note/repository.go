@@ -0,0 +1,169 @@
+package note
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/pkg/database"
+)
+
+type Repository interface {
+	GetByCustomerIDWithContext(ctx context.Context, customerID int) ([]domain.Note, error)
+	GetAfterWithContext(ctx context.Context, customerID int, after time.Time, afterID, limit int) ([]domain.Note, error)
+	GetWithContext(ctx context.Context, customerID, id int) (domain.Note, error)
+	SaveWithContext(ctx context.Context, n domain.Note) (int, error)
+	UpdateWithContext(ctx context.Context, n domain.Note) error
+	DeleteWithContext(ctx context.Context, customerID, id int) error
+	SaveRevisionWithContext(ctx context.Context, rev domain.NoteRevision) (int, error)
+	GetRevisionsWithContext(ctx context.Context, noteID int) ([]domain.NoteRevision, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+const noteColumns = "note_id, customer_id, author, body, pinned, created_at, updated_at"
+
+// GetByCustomerIDWithContext returns the notes of a customer, pinned ones first
+// and then newest first.
+func (r *repository) GetByCustomerIDWithContext(ctx context.Context, customerID int) ([]domain.Note, error) {
+	query := "SELECT " + noteColumns + " FROM notes WHERE deleted_at IS NULL and customer_id=? ORDER BY pinned DESC, created_at DESC, note_id DESC;"
+	return r.queryNotes(ctx, query, customerID)
+}
+
+// GetAfterWithContext returns up to limit notes of a customer written after the
+// given date or, on that date, with an ID greater than afterID, oldest first.
+func (r *repository) GetAfterWithContext(ctx context.Context, customerID int, after time.Time, afterID, limit int) ([]domain.Note, error) {
+	query := "SELECT " + noteColumns + " FROM notes WHERE deleted_at IS NULL and customer_id=? and (created_at>? or (created_at=? and note_id>?)) ORDER BY created_at, note_id LIMIT ?;"
+	return r.queryNotes(ctx, query, customerID, after, after, afterID, limit)
+}
+
+func (r *repository) GetWithContext(ctx context.Context, customerID, id int) (domain.Note, error) {
+	query := "SELECT " + noteColumns + " FROM notes WHERE deleted_at IS NULL and customer_id=? and note_id=?;"
+	row := database.Conn(ctx, r.db).QueryRowContext(ctx, query, customerID, id)
+	n, err := scanNote(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Note{}, ErrorNoteNotFound
+		}
+		return domain.Note{}, err
+	}
+
+	return n, nil
+}
+
+func (r *repository) SaveWithContext(ctx context.Context, n domain.Note) (int, error) {
+	query := "INSERT INTO notes (customer_id, author, body, pinned, created_at) VALUES (?, ?, ?, ?, ?);"
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, query, n.CustomerID, n.Author, n.Body, n.Pinned, n.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+func (r *repository) UpdateWithContext(ctx context.Context, n domain.Note) error {
+	query := "UPDATE notes SET body=?, pinned=?, updated_at=? WHERE customer_id=? and note_id=? and deleted_at IS NULL;"
+	_, err := database.Conn(ctx, r.db).ExecContext(ctx, query, n.Body, n.Pinned, n.UpdatedAt, n.CustomerID, n.ID)
+	return err
+}
+
+func (r *repository) DeleteWithContext(ctx context.Context, customerID, id int) error {
+	query := "UPDATE notes SET deleted_at=? WHERE customer_id=? and note_id=? and deleted_at IS NULL;"
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, query, time.Now(), customerID, id)
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affect < 1 {
+		return ErrorNoteNotFound
+	}
+
+	return nil
+}
+
+func (r *repository) SaveRevisionWithContext(ctx context.Context, rev domain.NoteRevision) (int, error) {
+	query := "INSERT INTO note_revisions (note_id, body, editor, edited_at) VALUES (?, ?, ?, ?);"
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, query, rev.NoteID, rev.Body, rev.Editor, rev.EditedAt)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+// GetRevisionsWithContext returns the previous bodies of a note, oldest first.
+func (r *repository) GetRevisionsWithContext(ctx context.Context, noteID int) ([]domain.NoteRevision, error) {
+	query := "SELECT revision_id, note_id, body, editor, edited_at FROM note_revisions WHERE note_id=? ORDER BY edited_at, revision_id;"
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, query, noteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []domain.NoteRevision
+
+	for rows.Next() {
+		rev := domain.NoteRevision{}
+		if err := rows.Scan(&rev.ID, &rev.NoteID, &rev.Body, &rev.Editor, &rev.EditedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+
+	return revisions, rows.Err()
+}
+
+func (r *repository) queryNotes(ctx context.Context, query string, args ...any) ([]domain.Note, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notes []domain.Note
+
+	for rows.Next() {
+		n, err := scanNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, n)
+	}
+
+	return notes, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanNote(row scanner) (domain.Note, error) {
+	n := domain.Note{}
+	err := row.Scan(&n.ID, &n.CustomerID, &n.Author, &n.Body, &n.Pinned, &n.CreatedAt, &n.UpdatedAt)
+	return n, err
+}
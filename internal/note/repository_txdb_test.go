@@ -0,0 +1,71 @@
+package note
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuite_NoteRepository(t *testing.T) {
+	db, err := testutil.InitTxdbDatabase(t)
+	assert.NoError(t, err)
+	repository := NewRepository(db)
+	customers := customer.NewRepository(db)
+
+	testNotesWithContext(t, repository, customers)
+
+	db.Close()
+}
+
+func testNotesWithContext(t *testing.T, repository Repository, customers customer.Repository) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	day1 := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	customerID, err := customers.SaveWithContext(ctx, domain.Customer{
+		CustomerNumber: 919191,
+		FirstName:      "Danilo",
+		LastName:       "Sano",
+		Status:         domain.CustomerStatusProspect,
+		CreatedAt:      day1,
+	})
+	assert.NoError(t, err)
+
+	first, err := repository.SaveWithContext(ctx, domain.Note{CustomerID: customerID, Author: "agent-1", Body: "Called.", CreatedAt: day1})
+	assert.NoError(t, err)
+	second, err := repository.SaveWithContext(ctx, domain.Note{CustomerID: customerID, Author: "agent-2", Body: "Emailed.", CreatedAt: day1})
+	assert.NoError(t, err)
+	third, err := repository.SaveWithContext(ctx, domain.Note{CustomerID: customerID, Author: "agent-1", Body: "Renewed.", Pinned: true, CreatedAt: day2})
+	assert.NoError(t, err)
+
+	notes, err := repository.GetByCustomerIDWithContext(ctx, customerID)
+	assert.NoError(t, err)
+	if assert.Len(t, notes, 3) {
+		assert.Equal(t, []int{third, second, first}, []int{notes[0].ID, notes[1].ID, notes[2].ID})
+	}
+
+	after, err := repository.GetAfterWithContext(ctx, customerID, day1, first, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, after, 2) {
+		assert.Equal(t, []int{second, third}, []int{after[0].ID, after[1].ID})
+	}
+
+	_, err = repository.SaveRevisionWithContext(ctx, domain.NoteRevision{NoteID: first, Body: "Called.", Editor: "agent-2", EditedAt: day2})
+	assert.NoError(t, err)
+	revisions, err := repository.GetRevisionsWithContext(ctx, first)
+	assert.NoError(t, err)
+	if assert.Len(t, revisions, 1) {
+		assert.Equal(t, domain.NoteRevision{ID: revisions[0].ID, NoteID: first, Body: "Called.", Editor: "agent-2", EditedAt: day2}, revisions[0])
+	}
+
+	assert.NoError(t, customers.DeleteWithContext(ctx, customerID))
+	_, err = repository.GetWithContext(ctx, customerID, first)
+	assert.ErrorIs(t, err, ErrorNoteNotFound)
+}
@@ -0,0 +1,164 @@
+package note
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/audit"
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/database"
+	"github.com/danilosano/web-golang-api/pkg/reqctx"
+)
+
+var (
+	ErrorNoteNotFound = errors.New("note not found")
+)
+
+type Service interface {
+	Save(ctx context.Context, input dto.NoteRequest, customerID int) (domain.Note, error)
+	GetAll(ctx context.Context, customerID int) ([]domain.Note, error)
+	Get(ctx context.Context, customerID, id int) (domain.Note, error)
+	Update(ctx context.Context, input dto.NoteRequest, customerID, id int) (domain.Note, error)
+	Delete(ctx context.Context, customerID, id int) error
+	GetRevisions(ctx context.Context, customerID, id int) ([]domain.NoteRevision, error)
+}
+
+type service struct {
+	repository Repository
+	customers  customer.Repository
+	transactor database.Transactor
+}
+
+type Option func(*service)
+
+// WithTransactor keeps the previous body of an edited note in the same
+// transaction as the edit.
+func WithTransactor(t database.Transactor) Option {
+	return func(s *service) {
+		s.transactor = t
+	}
+}
+
+// NewService manages the notes of the customers found in customers. The notes
+// of a deleted customer are deleted along with it by the customer repository.
+func NewService(r Repository, customers customer.Repository, opts ...Option) Service {
+	s := &service{
+		repository: r,
+		customers:  customers,
+		transactor: database.NoopTransactor(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Save writes a note authored by the user of ctx.
+func (s *service) Save(ctx context.Context, input dto.NoteRequest, customerID int) (domain.Note, error) {
+	if customerExist := s.customers.ExistsByIDWithContext(ctx, customerID); !customerExist {
+		return domain.Note{}, customer.ErrorCustomerNotFound
+	}
+
+	n := domain.Note{
+		CustomerID: customerID,
+		Author:     actor(ctx),
+		Body:       input.Body,
+		Pinned:     input.Pinned,
+		CreatedAt:  time.Now().Truncate(time.Second),
+	}
+
+	id, err := s.repository.SaveWithContext(ctx, n)
+	if err != nil {
+		return domain.Note{}, err
+	}
+
+	return s.repository.GetWithContext(ctx, customerID, id)
+}
+
+func (s *service) GetAll(ctx context.Context, customerID int) ([]domain.Note, error) {
+	if customerExist := s.customers.ExistsByIDWithContext(ctx, customerID); !customerExist {
+		return nil, customer.ErrorCustomerNotFound
+	}
+
+	return s.repository.GetByCustomerIDWithContext(ctx, customerID)
+}
+
+func (s *service) Get(ctx context.Context, customerID, id int) (domain.Note, error) {
+	if customerExist := s.customers.ExistsByIDWithContext(ctx, customerID); !customerExist {
+		return domain.Note{}, customer.ErrorCustomerNotFound
+	}
+
+	return s.repository.GetWithContext(ctx, customerID, id)
+}
+
+// Update changes the body and the pinned flag of a note. When the body changes,
+// the previous one is kept as a revision edited by the user of ctx.
+func (s *service) Update(ctx context.Context, input dto.NoteRequest, customerID, id int) (domain.Note, error) {
+	if customerExist := s.customers.ExistsByIDWithContext(ctx, customerID); !customerExist {
+		return domain.Note{}, customer.ErrorCustomerNotFound
+	}
+
+	var result domain.Note
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		current, err := s.repository.GetWithContext(ctx, customerID, id)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().Truncate(time.Second)
+		if current.Body != input.Body {
+			rev := domain.NoteRevision{
+				NoteID:   id,
+				Body:     current.Body,
+				Editor:   actor(ctx),
+				EditedAt: now,
+			}
+			if _, err := s.repository.SaveRevisionWithContext(ctx, rev); err != nil {
+				return err
+			}
+		}
+
+		current.Body = input.Body
+		current.Pinned = input.Pinned
+		current.UpdatedAt = &now
+		if err := s.repository.UpdateWithContext(ctx, current); err != nil {
+			return err
+		}
+
+		result, err = s.repository.GetWithContext(ctx, customerID, id)
+		return err
+	})
+	if err != nil {
+		return domain.Note{}, err
+	}
+
+	return result, nil
+}
+
+func (s *service) Delete(ctx context.Context, customerID, id int) error {
+	if customerExist := s.customers.ExistsByIDWithContext(ctx, customerID); !customerExist {
+		return customer.ErrorCustomerNotFound
+	}
+
+	return s.repository.DeleteWithContext(ctx, customerID, id)
+}
+
+// GetRevisions returns the edit history of a note, oldest first.
+func (s *service) GetRevisions(ctx context.Context, customerID, id int) ([]domain.NoteRevision, error) {
+	if _, err := s.Get(ctx, customerID, id); err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetRevisionsWithContext(ctx, id)
+}
+
+// actor returns the user of ctx, or audit.AnonymousActor without one.
+func actor(ctx context.Context) string {
+	if user := reqctx.UserID(ctx); user != "" {
+		return user
+	}
+	return audit.AnonymousActor
+}
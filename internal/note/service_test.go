@@ -0,0 +1,120 @@
+package note
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/reqctx"
+	customerMocks "github.com/danilosano/web-golang-api/pkg/tests/customers"
+	mocks "github.com/danilosano/web-golang-api/pkg/tests/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func createService(t *testing.T) (Service, *mocks.NoteRepositoryMock, *customerMocks.CustomersRepositoryMock, context.Context) {
+	t.Helper()
+	repoMock := new(mocks.NoteRepositoryMock)
+	customersMock := new(customerMocks.CustomersRepositoryMock)
+	return NewService(repoMock, customersMock), repoMock, customersMock, reqctx.WithUserID(context.Background(), "agent-1")
+}
+
+var mockedNote = domain.Note{
+	ID:         1,
+	CustomerID: 1,
+	Author:     "agent-1",
+	Body:       "Called about the late invoice.",
+	CreatedAt:  time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+}
+
+func TestSave(t *testing.T) {
+	t.Run("The note is authored by the user of the request.", func(t *testing.T) {
+		service, repoMock, customersMock, ctx := createService(t)
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("SaveWithContext", ctx, mock.MatchedBy(func(n domain.Note) bool {
+			return n.Author == "agent-1" && n.CustomerID == 1 && n.Pinned && !n.CreatedAt.IsZero()
+		})).Return(1, nil)
+		repoMock.On("GetWithContext", ctx, 1, 1).Return(mockedNote, nil)
+
+		result, err := service.Save(ctx, dto.NoteRequest{Body: mockedNote.Body, Pinned: true}, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, mockedNote, result)
+	})
+
+	t.Run("Without an authenticated user, the note is authored by the anonymous actor.", func(t *testing.T) {
+		service, repoMock, customersMock, _ := createService(t)
+		ctx := context.Background()
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("SaveWithContext", ctx, mock.MatchedBy(func(n domain.Note) bool {
+			return n.Author == "anonymous"
+		})).Return(1, nil)
+		repoMock.On("GetWithContext", ctx, 1, 1).Return(mockedNote, nil)
+
+		_, err := service.Save(ctx, dto.NoteRequest{Body: mockedNote.Body}, 1)
+		assert.Nil(t, err)
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("When the customer does not exist, an error will be returned.", func(t *testing.T) {
+		service, _, customersMock, ctx := createService(t)
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(false)
+
+		_, err := service.Save(ctx, dto.NoteRequest{Body: mockedNote.Body}, 1)
+		assert.Equal(t, customer.ErrorCustomerNotFound, err)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	t.Run("When the body changes, the previous one is kept as a revision.", func(t *testing.T) {
+		service, repoMock, customersMock, ctx := createService(t)
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("GetWithContext", ctx, 1, 1).Return(mockedNote, nil)
+		repoMock.On("SaveRevisionWithContext", ctx, mock.MatchedBy(func(rev domain.NoteRevision) bool {
+			return rev.NoteID == 1 && rev.Body == mockedNote.Body && rev.Editor == "agent-1"
+		})).Return(1, nil)
+		repoMock.On("UpdateWithContext", ctx, mock.MatchedBy(func(n domain.Note) bool {
+			return n.Body == "Invoice paid." && n.Author == "agent-1" && n.UpdatedAt != nil
+		})).Return(nil)
+
+		_, err := service.Update(ctx, dto.NoteRequest{Body: "Invoice paid."}, 1, 1)
+		assert.Nil(t, err)
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("Pinning a note without changing its body records no revision.", func(t *testing.T) {
+		service, repoMock, customersMock, ctx := createService(t)
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("GetWithContext", ctx, 1, 1).Return(mockedNote, nil)
+		repoMock.On("UpdateWithContext", ctx, mock.MatchedBy(func(n domain.Note) bool {
+			return n.Pinned
+		})).Return(nil)
+
+		_, err := service.Update(ctx, dto.NoteRequest{Body: mockedNote.Body, Pinned: true}, 1, 1)
+		assert.Nil(t, err)
+		repoMock.AssertNotCalled(t, "SaveRevisionWithContext", mock.Anything, mock.Anything)
+	})
+
+	t.Run("When the note does not exist, an error will be returned.", func(t *testing.T) {
+		service, repoMock, customersMock, ctx := createService(t)
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("GetWithContext", ctx, 1, 2).Return(nil, ErrorNoteNotFound)
+
+		_, err := service.Update(ctx, dto.NoteRequest{Body: "Invoice paid."}, 1, 2)
+		assert.Equal(t, ErrorNoteNotFound, err)
+	})
+}
+
+func TestGetRevisions(t *testing.T) {
+	t.Run("When the note belongs to another customer, an error will be returned.", func(t *testing.T) {
+		service, repoMock, customersMock, ctx := createService(t)
+		customersMock.On("ExistsByIDWithContext", ctx, 2).Return(true)
+		repoMock.On("GetWithContext", ctx, 2, 1).Return(nil, ErrorNoteNotFound)
+
+		_, err := service.GetRevisions(ctx, 2, 1)
+		assert.Equal(t, ErrorNoteNotFound, err)
+		repoMock.AssertNotCalled(t, "GetRevisionsWithContext", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,55 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+)
+
+// Message is a record published to a message broker.
+type Message struct {
+	Topic   string
+	Key     []byte
+	Value   []byte
+	Headers map[string]string
+}
+
+// Broker is the minimal interface of a message broker such as NATS or Kafka.
+// Adapters for those clients only need to map a Message onto their own record type.
+type Broker interface {
+	Publish(ctx context.Context, msg Message) error
+}
+
+// MemoryBroker is an in-process Broker delivering messages to subscribers of a topic.
+type MemoryBroker struct {
+	mu          sync.RWMutex
+	subscribers map[string][]chan Message
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{subscribers: make(map[string][]chan Message)}
+}
+
+// Subscribe returns a channel receiving every message published on topic from now
+// on. Publishing blocks while the channel buffer is full.
+func (b *MemoryBroker) Subscribe(topic string, buffer int) <-chan Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan Message, buffer)
+	b.subscribers[topic] = append(b.subscribers[topic], ch)
+	return ch
+}
+
+func (b *MemoryBroker) Publish(ctx context.Context, msg Message) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, ch := range b.subscribers[msg.Topic] {
+		select {
+		case ch <- msg:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package outbox
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/pkg/database"
+)
+
+const (
+	defaultMaxAttempts = 10
+	defaultBaseBackoff = 5 * time.Second
+	defaultMaxBackoff  = 5 * time.Minute
+	defaultLease       = 5 * time.Minute
+)
+
+// Dispatcher periodically publishes the events stored in the outbox. Events are
+// delivered at least once and in order: a failed event is retried with
+// exponential backoff before any later event is published, until MaxAttempts is
+// reached and it is dead lettered. Events are published outside of any
+// transaction, locked for Lease so that other dispatchers leave them alone.
+type Dispatcher struct {
+	repository  Repository
+	publisher   Publisher
+	transactor  database.Transactor
+	interval    time.Duration
+	batchSize   int
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	Lease       time.Duration
+	now         func() time.Time
+}
+
+func NewDispatcher(r Repository, p Publisher, t database.Transactor, interval time.Duration, batchSize int) *Dispatcher {
+	return &Dispatcher{
+		repository:  r,
+		publisher:   p,
+		transactor:  t,
+		interval:    interval,
+		batchSize:   batchSize,
+		MaxAttempts: defaultMaxAttempts,
+		BaseBackoff: defaultBaseBackoff,
+		MaxBackoff:  defaultMaxBackoff,
+		Lease:       defaultLease,
+		now:         time.Now,
+	}
+}
+
+// Run dispatches pending events every interval until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DispatchPending(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("error dispatching outbox events: %s\n", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchPending publishes one batch of pending events and returns how many were published.
+func (d *Dispatcher) DispatchPending(ctx context.Context) (int, error) {
+	entries, err := d.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	dispatched := 0
+	for i, e := range entries {
+		if err := d.publisher.Publish(ctx, e.Event); err != nil {
+			log.Printf("error publishing event %s: %s\n", e.Event.ID, err.Error())
+			if err := d.fail(ctx, e, err); err != nil {
+				return dispatched, err
+			}
+			return dispatched, d.repository.UnlockWithContext(ctx, eventIDs(entries[i+1:]))
+		}
+
+		if err := d.repository.MarkDispatchedWithContext(ctx, e.Event.ID); err != nil {
+			return dispatched, err
+		}
+		dispatched++
+	}
+	return dispatched, nil
+}
+
+// claim locks the oldest pending events up to the first one that is not due yet
+// or locked by another dispatcher, so that events are never published out of order.
+func (d *Dispatcher) claim(ctx context.Context) ([]domain.OutboxEntry, error) {
+	var claimed []domain.OutboxEntry
+	err := d.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		entries, err := d.repository.GetPendingWithContext(ctx, d.batchSize)
+		if err != nil {
+			return err
+		}
+
+		now := d.now()
+		for _, e := range entries {
+			if e.LockedUntil != nil && e.LockedUntil.After(now) || e.NextAttemptAt != nil && e.NextAttemptAt.After(now) {
+				break
+			}
+			claimed = append(claimed, e)
+		}
+
+		if len(claimed) == 0 {
+			return nil
+		}
+		return d.repository.LockWithContext(ctx, eventIDs(claimed), now.Add(d.Lease))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+// fail records a failed attempt, dead lettering the event once MaxAttempts is reached.
+func (d *Dispatcher) fail(ctx context.Context, e domain.OutboxEntry, cause error) error {
+	attempts := e.Attempts + 1
+	if attempts >= d.MaxAttempts {
+		log.Printf("dead lettering event %s after %d attempts\n", e.Event.ID, attempts)
+		return d.repository.DeadLetterWithContext(ctx, e.Event.ID, cause)
+	}
+	return d.repository.MarkFailedWithContext(ctx, e.Event.ID, cause, d.now().Add(d.backoff(attempts)))
+}
+
+// backoff doubles the delay after every failed attempt, up to MaxBackoff.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.BaseBackoff
+	for i := 1; i < attempts && delay < d.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > d.MaxBackoff {
+		delay = d.MaxBackoff
+	}
+	return delay
+}
+
+func eventIDs(entries []domain.OutboxEntry) []string {
+	ids := make([]string, len(entries))
+	for i, e := range entries {
+		ids[i] = e.Event.ID
+	}
+	return ids
+}
@@ -0,0 +1,139 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/pkg/database"
+	mocks "github.com/danilosano/web-golang-api/pkg/tests/outbox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	createdEvent = domain.Event{ID: "e1", Type: domain.EventCustomerCreated, CustomerID: 1, Payload: json.RawMessage(`{"id":1}`)}
+	updatedEvent = domain.Event{ID: "e2", Type: domain.EventCustomerUpdated, CustomerID: 1, Payload: json.RawMessage(`{"id":1}`)}
+	dispatchNow  = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+)
+
+func createDispatcher(t *testing.T, p Publisher) (*Dispatcher, *mocks.OutboxRepositoryMock, context.Context) {
+	t.Helper()
+	repoMock := new(mocks.OutboxRepositoryMock)
+	dispatcher := NewDispatcher(repoMock, p, database.NoopTransactor(), time.Second, 10)
+	dispatcher.now = func() time.Time { return dispatchNow }
+	return dispatcher, repoMock, context.Background()
+}
+
+func failingPublisher() Publisher {
+	return publisherFunc(func(context.Context, domain.Event) error { return errors.New("generic error") })
+}
+
+type publisherFunc func(ctx context.Context, e domain.Event) error
+
+func (f publisherFunc) Publish(ctx context.Context, e domain.Event) error {
+	return f(ctx, e)
+}
+
+func TestDispatcher(t *testing.T) {
+	t.Run("Pending events are published to the broker in order and marked as dispatched.", func(t *testing.T) {
+		broker := NewMemoryBroker()
+		messages := broker.Subscribe("customers", 2)
+		dispatcher, repoMock, ctx := createDispatcher(t, BrokerPublisher(broker, "customers"))
+		repoMock.On("GetPendingWithContext", ctx, 10).Return([]domain.OutboxEntry{{Event: createdEvent}, {Event: updatedEvent}}, nil)
+		repoMock.On("LockWithContext", ctx, []string{"e1", "e2"}, dispatchNow.Add(defaultLease)).Return(nil)
+		repoMock.On("MarkDispatchedWithContext", ctx, "e1").Return(nil)
+		repoMock.On("MarkDispatchedWithContext", ctx, "e2").Return(nil)
+
+		n, err := dispatcher.DispatchPending(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, n)
+
+		first, second := <-messages, <-messages
+		assert.Equal(t, "e1", first.Headers["event_id"])
+		assert.Equal(t, []byte("1"), first.Key)
+		assert.Equal(t, domain.EventCustomerUpdated, second.Headers["event_type"])
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("When publishing fails, the event is retried after a backoff and later events are unlocked to wait for it.", func(t *testing.T) {
+		var received []domain.Event
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var e domain.Event
+			_ = json.NewDecoder(r.Body).Decode(&e)
+			received = append(received, e)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer receiver.Close()
+
+		dispatcher, repoMock, ctx := createDispatcher(t, WebhookPublisher(receiver.URL, receiver.Client()))
+		repoMock.On("GetPendingWithContext", ctx, 10).Return([]domain.OutboxEntry{{Event: createdEvent, Attempts: 2}, {Event: updatedEvent}}, nil)
+		repoMock.On("LockWithContext", ctx, []string{"e1", "e2"}, mock.Anything).Return(nil)
+		repoMock.On("MarkFailedWithContext", ctx, "e1", mock.Anything, dispatchNow.Add(4*defaultBaseBackoff)).Return(nil)
+		repoMock.On("UnlockWithContext", ctx, []string{"e2"}).Return(nil)
+
+		n, err := dispatcher.DispatchPending(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+		assert.Len(t, received, 1)
+		assert.Equal(t, "e1", received[0].ID)
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("When the outbox cannot be read, the error is returned.", func(t *testing.T) {
+		dispatcher, repoMock, ctx := createDispatcher(t, LogPublisher(nil))
+		repoMock.On("GetPendingWithContext", ctx, 10).Return(nil, errors.New("generic error"))
+
+		_, err := dispatcher.DispatchPending(ctx)
+		assert.Equal(t, errors.New("generic error"), err)
+	})
+
+	t.Run("An event failing for the last allowed time is dead lettered.", func(t *testing.T) {
+		dispatcher, repoMock, ctx := createDispatcher(t, failingPublisher())
+		repoMock.On("GetPendingWithContext", ctx, 10).Return([]domain.OutboxEntry{{Event: createdEvent, Attempts: defaultMaxAttempts - 1}}, nil)
+		repoMock.On("LockWithContext", ctx, []string{"e1"}, mock.Anything).Return(nil)
+		repoMock.On("DeadLetterWithContext", ctx, "e1", errors.New("generic error")).Return(nil)
+		repoMock.On("UnlockWithContext", ctx, []string{}).Return(nil)
+
+		n, err := dispatcher.DispatchPending(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("Events waiting behind one not due yet or locked by another dispatcher are left alone.", func(t *testing.T) {
+		later := dispatchNow.Add(time.Minute)
+		for _, blocked := range []domain.OutboxEntry{{Event: updatedEvent, NextAttemptAt: &later}, {Event: updatedEvent, LockedUntil: &later}} {
+			published := 0
+			dispatcher, repoMock, ctx := createDispatcher(t, publisherFunc(func(context.Context, domain.Event) error {
+				published++
+				return nil
+			}))
+			thirdEvent := domain.Event{ID: "e3", Type: domain.EventCustomerDeleted, CustomerID: 1}
+			repoMock.On("GetPendingWithContext", ctx, 10).Return([]domain.OutboxEntry{{Event: createdEvent}, blocked, {Event: thirdEvent}}, nil)
+			repoMock.On("LockWithContext", ctx, []string{"e1"}, mock.Anything).Return(nil)
+			repoMock.On("MarkDispatchedWithContext", ctx, "e1").Return(nil)
+
+			n, err := dispatcher.DispatchPending(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, n)
+			assert.Equal(t, 1, published)
+			repoMock.AssertExpectations(t)
+		}
+	})
+
+	t.Run("When nothing is due, nothing is locked.", func(t *testing.T) {
+		dispatcher, repoMock, ctx := createDispatcher(t, failingPublisher())
+		repoMock.On("GetPendingWithContext", ctx, 10).Return([]domain.OutboxEntry{}, nil)
+
+		n, err := dispatcher.DispatchPending(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+		repoMock.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,51 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/google/uuid"
+)
+
+// NewEvent builds a domain event about a customer, with payload as its body.
+func NewEvent(eventType string, customerID int, payload any) (domain.Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return domain.Event{}, err
+	}
+
+	return domain.Event{
+		ID:         uuid.NewString(),
+		Type:       eventType,
+		CustomerID: customerID,
+		Payload:    data,
+		OccurredAt: time.Now().Truncate(time.Second),
+	}, nil
+}
+
+// Publisher delivers events to the outside world. An event may be published
+// again after a failure or a crash, so implementations must be idempotent or let
+// their receivers deduplicate by event ID.
+type Publisher interface {
+	Publish(ctx context.Context, e domain.Event) error
+}
+
+type multiPublisher []Publisher
+
+// MultiPublisher publishes every event to each of publishers in order, stopping
+// at the first failure. The event is then published again to all of them on the
+// next attempt, which idempotent publishers make harmless.
+func MultiPublisher(publishers ...Publisher) Publisher {
+	return multiPublisher(publishers)
+}
+
+func (m multiPublisher) Publish(ctx context.Context, e domain.Event) error {
+	for _, p := range m {
+		if err := p.Publish(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
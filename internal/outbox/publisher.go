@@ -0,0 +1,96 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+)
+
+type logPublisher struct {
+	logger *log.Logger
+}
+
+// LogPublisher writes every event to logger, or to the standard logger when nil.
+func LogPublisher(logger *log.Logger) Publisher {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &logPublisher{logger: logger}
+}
+
+func (p *logPublisher) Publish(_ context.Context, e domain.Event) error {
+	p.logger.Printf("event %s %s customer=%d payload=%s\n", e.ID, e.Type, e.CustomerID, e.Payload)
+	return nil
+}
+
+type webhookPublisher struct {
+	url    string
+	client *http.Client
+}
+
+// WebhookPublisher POSTs every event as JSON to url. Any non-2xx answer is a failure.
+func WebhookPublisher(url string, client *http.Client) Publisher {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &webhookPublisher{url: url, client: client}
+}
+
+func (p *webhookPublisher) Publish(ctx context.Context, e domain.Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", e.ID)
+	req.Header.Set("X-Event-Type", e.Type)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook %s answered %d", p.url, resp.StatusCode)
+	}
+	return nil
+}
+
+type brokerPublisher struct {
+	broker Broker
+	topic  string
+}
+
+// BrokerPublisher publishes every event as JSON on topic, keyed by customer ID so
+// that a partitioned broker keeps the events of a customer in order.
+func BrokerPublisher(broker Broker, topic string) Publisher {
+	return &brokerPublisher{broker: broker, topic: topic}
+}
+
+func (p *brokerPublisher) Publish(ctx context.Context, e domain.Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return p.broker.Publish(ctx, Message{
+		Topic: p.topic,
+		Key:   []byte(fmt.Sprint(e.CustomerID)),
+		Value: body,
+		Headers: map[string]string{
+			"event_id":   e.ID,
+			"event_type": e.Type,
+		},
+	})
+}
@@ -0,0 +1,114 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/pkg/database"
+)
+
+type Repository interface {
+	SaveWithContext(ctx context.Context, e domain.Event) error
+	GetPendingWithContext(ctx context.Context, limit int) ([]domain.OutboxEntry, error)
+	LockWithContext(ctx context.Context, ids []string, until time.Time) error
+	UnlockWithContext(ctx context.Context, ids []string) error
+	MarkDispatchedWithContext(ctx context.Context, id string) error
+	MarkFailedWithContext(ctx context.Context, id string, cause error, retryAt time.Time) error
+	DeadLetterWithContext(ctx context.Context, id string, cause error) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) SaveWithContext(ctx context.Context, e domain.Event) error {
+	query := "INSERT INTO outbox (event_id, event_type, customer_id, payload, occurred_at) VALUES (?, ?, ?, ?, ?);"
+	_, err := database.Conn(ctx, r.db).ExecContext(ctx, query, e.ID, e.Type, e.CustomerID, string(e.Payload), e.OccurredAt)
+	return err
+}
+
+// GetPendingWithContext returns the oldest events neither dispatched nor dead
+// lettered. Within a transaction the rows are locked until it ends, so that
+// dispatchers take turns to lock them with LockWithContext.
+func (r *repository) GetPendingWithContext(ctx context.Context, limit int) ([]domain.OutboxEntry, error) {
+	query := "SELECT event_id, event_type, customer_id, payload, occurred_at, attempts, next_attempt_at, locked_until FROM outbox " +
+		"WHERE dispatched_at IS NULL and dead_lettered_at IS NULL ORDER BY occurred_at, outbox_id LIMIT ?"
+	if database.InTransaction(ctx) {
+		query += " FOR UPDATE"
+	}
+
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, query+";", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []domain.OutboxEntry
+
+	for rows.Next() {
+		var payload []byte
+		e := domain.OutboxEntry{}
+		if err := rows.Scan(&e.Event.ID, &e.Event.Type, &e.Event.CustomerID, &payload, &e.Event.OccurredAt, &e.Attempts, &e.NextAttemptAt, &e.LockedUntil); err != nil {
+			return nil, err
+		}
+		e.Event.Payload = payload
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// LockWithContext holds the events for a dispatcher until the given time, while
+// it publishes them outside of any transaction.
+func (r *repository) LockWithContext(ctx context.Context, ids []string, until time.Time) error {
+	return r.updateAll(ctx, "UPDATE outbox SET locked_until=? WHERE event_id IN ", ids, until)
+}
+
+// UnlockWithContext releases events locked by LockWithContext that were not published.
+func (r *repository) UnlockWithContext(ctx context.Context, ids []string) error {
+	return r.updateAll(ctx, "UPDATE outbox SET locked_until=NULL WHERE event_id IN ", ids)
+}
+
+func (r *repository) updateAll(ctx context.Context, query string, ids []string, args ...any) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	query += "(" + strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ") + ");"
+	_, err := database.Conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *repository) MarkDispatchedWithContext(ctx context.Context, id string) error {
+	query := "UPDATE outbox SET dispatched_at=?, attempts=attempts+1, last_error=NULL, locked_until=NULL WHERE event_id=?;"
+	_, err := database.Conn(ctx, r.db).ExecContext(ctx, query, time.Now(), id)
+	return err
+}
+
+// MarkFailedWithContext records a failed attempt to publish an event, to be
+// attempted again from retryAt.
+func (r *repository) MarkFailedWithContext(ctx context.Context, id string, cause error, retryAt time.Time) error {
+	query := "UPDATE outbox SET attempts=attempts+1, last_error=?, next_attempt_at=?, locked_until=NULL WHERE event_id=?;"
+	_, err := database.Conn(ctx, r.db).ExecContext(ctx, query, cause.Error(), retryAt, id)
+	return err
+}
+
+// DeadLetterWithContext records the last failed attempt to publish an event,
+// which is not attempted again. Clearing dead_lettered_at requeues it.
+func (r *repository) DeadLetterWithContext(ctx context.Context, id string, cause error) error {
+	query := "UPDATE outbox SET attempts=attempts+1, last_error=?, dead_lettered_at=?, locked_until=NULL WHERE event_id=?;"
+	_, err := database.Conn(ctx, r.db).ExecContext(ctx, query, cause.Error(), time.Now(), id)
+	return err
+}
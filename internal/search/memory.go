@@ -0,0 +1,166 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// minScore is the similarity below which a customer is not a match.
+const minScore = 0.3
+
+type document struct {
+	customer dto.ResultCustomerRequest
+	terms    []map[string]struct{}
+}
+
+// MemoryIndex is an in-process Searcher ranking customers by the trigram
+// similarity of their names to the query, which tolerates partial and misspelled
+// names. It implements outbox.Publisher to stay current with customer events.
+type MemoryIndex struct {
+	mu   sync.RWMutex
+	docs map[int]document
+}
+
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{docs: make(map[int]document)}
+}
+
+// Index adds or replaces customers in the index.
+func (m *MemoryIndex) Index(customers ...dto.ResultCustomerRequest) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, c := range customers {
+		var terms []map[string]struct{}
+		for _, token := range tokenize(c.FirstName + " " + c.LastName) {
+			terms = append(terms, trigrams(token))
+		}
+		m.docs[c.ID] = document{customer: c, terms: terms}
+	}
+}
+
+// Remove drops a customer from the index.
+func (m *MemoryIndex) Remove(id int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.docs, id)
+}
+
+// Publish indexes the customer of e, or removes it when deleted. Publishing the
+// same event again leaves the index unchanged.
+func (m *MemoryIndex) Publish(_ context.Context, e domain.Event) error {
+	if e.Type == domain.EventCustomerDeleted {
+		m.Remove(e.CustomerID)
+		return nil
+	}
+
+	var c dto.ResultCustomerRequest
+	if err := json.Unmarshal(e.Payload, &c); err != nil {
+		return err
+	}
+	m.Index(c)
+	return nil
+}
+
+func (m *MemoryIndex) Search(_ context.Context, query string, page, pageSize int) (dto.CustomerSearchPage, error) {
+	page, pageSize = normalizePage(page, pageSize)
+
+	var queryTerms []map[string]struct{}
+	for _, token := range tokenize(query) {
+		queryTerms = append(queryTerms, trigrams(token))
+	}
+
+	m.mu.RLock()
+	var matches []dto.CustomerSearchResult
+	for _, doc := range m.docs {
+		if score := doc.score(queryTerms); score >= minScore {
+			matches = append(matches, dto.CustomerSearchResult{ResultCustomerRequest: doc.customer, Score: score})
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].ID < matches[j].ID
+	})
+
+	result := dto.CustomerSearchPage{Results: []dto.CustomerSearchResult{}, Page: page, PageSize: pageSize, Total: len(matches)}
+	start := (page - 1) * pageSize
+	if start < len(matches) {
+		end := min(start+pageSize, len(matches))
+		result.Results = append(result.Results, matches[start:end]...)
+	}
+	return result, nil
+}
+
+// score averages, over the query terms, the best similarity with a name term.
+func (d document) score(queryTerms []map[string]struct{}) float64 {
+	if len(queryTerms) == 0 {
+		return 0
+	}
+
+	var total float64
+	for _, q := range queryTerms {
+		var best float64
+		for _, t := range d.terms {
+			best = max(best, similarity(q, t))
+		}
+		total += best
+	}
+	return total / float64(len(queryTerms))
+}
+
+// similarity is the share of the query trigrams found in the term, so that a
+// prefix of a name is a full match.
+func similarity(query, term map[string]struct{}) float64 {
+	if len(query) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for g := range query {
+		if _, ok := term[g]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(query))
+}
+
+// tokenize lowercases s, strips its accents and splits it into words.
+func tokenize(s string) []string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, strings.ToLower(s))
+	if err != nil {
+		folded = strings.ToLower(s)
+	}
+	return strings.FieldsFunc(folded, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// trigrams returns the trigrams of a word padded with a leading space, so that
+// matching starts count more than matching middles.
+func trigrams(word string) map[string]struct{} {
+	r := []rune(" " + word)
+	grams := make(map[string]struct{})
+	for i := 0; i+3 <= len(r); i++ {
+		grams[string(r[i:i+3])] = struct{}{}
+	}
+	if len(grams) == 0 {
+		grams[string(r)] = struct{}{}
+	}
+	return grams
+}
@@ -0,0 +1,69 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func customer(id int, firstName, lastName string) dto.ResultCustomerRequest {
+	return dto.ResultCustomerRequest{ID: id, FirstName: firstName, LastName: lastName}
+}
+
+func resultIDs(page dto.CustomerSearchPage) []int {
+	var ids []int
+	for _, r := range page.Results {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func TestMemoryIndex(t *testing.T) {
+	ctx := context.Background()
+	index := NewMemoryIndex()
+	index.Index(
+		customer(1, "Danilo", "Sano"),
+		customer(2, "Daniela", "Souza"),
+		customer(3, "João", "Sant'Anna"),
+		customer(4, "Maria", "Oliveira"),
+	)
+
+	t.Run("Partial names match, best matches first.", func(t *testing.T) {
+		page, err := index.Search(ctx, "dani", 1, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, resultIDs(page))
+		assert.Equal(t, 2, page.Total)
+	})
+
+	t.Run("Misspelled and unaccented names match.", func(t *testing.T) {
+		page, _ := index.Search(ctx, "Danillo Sanno", 1, 10)
+		assert.Equal(t, 1, resultIDs(page)[0])
+
+		page, _ = index.Search(ctx, "joao", 1, 10)
+		assert.Equal(t, []int{3}, resultIDs(page))
+	})
+
+	t.Run("Results are paginated.", func(t *testing.T) {
+		page, _ := index.Search(ctx, "dani", 2, 1)
+		assert.Equal(t, []int{2}, resultIDs(page))
+		assert.Equal(t, 2, page.Total)
+
+		page, _ = index.Search(ctx, "dani", 3, 1)
+		assert.Empty(t, page.Results)
+	})
+
+	t.Run("The index follows customer events.", func(t *testing.T) {
+		payload, _ := json.Marshal(customer(4, "Mariana", "Oliveira"))
+		_ = index.Publish(ctx, domain.Event{Type: domain.EventCustomerUpdated, CustomerID: 4, Payload: payload})
+		page, _ := index.Search(ctx, "mariana", 1, 10)
+		assert.Equal(t, []int{4}, resultIDs(page))
+
+		_ = index.Publish(ctx, domain.Event{Type: domain.EventCustomerDeleted, CustomerID: 4})
+		page, _ = index.Search(ctx, "mariana", 1, 10)
+		assert.Empty(t, page.Results)
+	})
+}
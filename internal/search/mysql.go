@@ -0,0 +1,51 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/database"
+)
+
+type mysqlSearcher struct {
+	db *sql.DB
+}
+
+// NewMySQLSearcher searches the ngram FULLTEXT index of the customers names. As
+// the names are indexed by bigrams, partial and misspelled names still match and
+// rank by the number of bigrams they share with the query.
+func NewMySQLSearcher(db *sql.DB) Searcher {
+	return &mysqlSearcher{
+		db: db,
+	}
+}
+
+func (s *mysqlSearcher) Search(ctx context.Context, query string, page, pageSize int) (dto.CustomerSearchPage, error) {
+	page, pageSize = normalizePage(page, pageSize)
+	result := dto.CustomerSearchPage{Results: []dto.CustomerSearchResult{}, Page: page, PageSize: pageSize}
+
+	countQuery := "SELECT COUNT(*) FROM customers WHERE deleted_at IS NULL and MATCH(first_name, last_name) AGAINST (? IN NATURAL LANGUAGE MODE);"
+	if err := database.Conn(ctx, s.db).QueryRowContext(ctx, countQuery, query).Scan(&result.Total); err != nil {
+		return dto.CustomerSearchPage{}, err
+	}
+
+	searchQuery := "SELECT customer_id, customer_number, first_name, last_name, status, attributes, created_at, updated_at, parent_id, MATCH(first_name, last_name) AGAINST (? IN NATURAL LANGUAGE MODE) AS score " +
+		"FROM customers WHERE deleted_at IS NULL and MATCH(first_name, last_name) AGAINST (? IN NATURAL LANGUAGE MODE) " +
+		"ORDER BY score DESC, customer_id LIMIT ? OFFSET ?;"
+	rows, err := database.Conn(ctx, s.db).QueryContext(ctx, searchQuery, query, query, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return dto.CustomerSearchPage{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		r := dto.CustomerSearchResult{}
+		if err := rows.Scan(&r.ID, &r.CustomerNumber, &r.FirstName, &r.LastName, &r.Status, &r.Attributes, &r.CreatedAt, &r.UpdatedAt, &r.ParentID, &r.Score); err != nil {
+			return dto.CustomerSearchPage{}, err
+		}
+		result.Results = append(result.Results, r)
+	}
+
+	return result, rows.Err()
+}
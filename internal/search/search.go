@@ -0,0 +1,29 @@
+package search
+
+import (
+	"context"
+
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+)
+
+const (
+	DefaultPageSize = 20
+)
+
+// Searcher finds customers by approximate name, most relevant first. Besides the
+// MySQL FULLTEXT implementation, an embedded index (e.g. the in-memory one, or
+// Bleve) can be plugged in where no MySQL is available.
+type Searcher interface {
+	Search(ctx context.Context, query string, page, pageSize int) (dto.CustomerSearchPage, error)
+}
+
+// normalizePage applies the defaults of the optional page parameters.
+func normalizePage(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = DefaultPageSize
+	}
+	return page, pageSize
+}
@@ -0,0 +1,38 @@
+package sequence
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/danilosano/web-golang-api/pkg/database"
+)
+
+// Repository hands out the values of named counters stored in the sequences
+// table, which MySQL lacks natively.
+type Repository interface {
+	NextWithContext(ctx context.Context, name string) (int64, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+// NextWithContext increments the counter and returns its new value, starting at 1
+// for a counter never used. Within a transaction the counter stays locked until
+// it ends, a rollback giving the value back; otherwise the value is used up even
+// when the caller fails.
+func (r *repository) NextWithContext(ctx context.Context, name string) (int64, error) {
+	query := "INSERT INTO sequences (name, value) VALUES (?, LAST_INSERT_ID(1)) ON DUPLICATE KEY UPDATE value=LAST_INSERT_ID(value + 1);"
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, query, name)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.LastInsertId()
+}
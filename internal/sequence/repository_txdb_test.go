@@ -0,0 +1,38 @@
+package sequence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuite_SequenceRepository(t *testing.T) {
+	db, err := testutil.InitTxdbDatabase(t)
+	assert.NoError(t, err)
+	repository := NewRepository(db)
+
+	testNextWithContext(t, repository)
+
+	db.Close()
+}
+
+func testNextWithContext(t *testing.T, repository Repository) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	first, err := repository.NextWithContext(ctx, "test_sequence")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), first)
+
+	second, err := repository.NextWithContext(ctx, "test_sequence")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), second)
+
+	other, err := repository.NextWithContext(ctx, "other_sequence")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), other)
+}
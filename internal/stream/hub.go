@@ -0,0 +1,90 @@
+package stream
+
+import (
+	"context"
+	"sync"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+)
+
+// subscriberBuffer is the number of events a subscriber may lag behind before it
+// is disconnected. Disconnected clients resume with Last-Event-ID.
+const subscriberBuffer = 64
+
+// Hub fans customer events out to the connected stream clients and keeps the
+// latest ones in a bounded buffer so that reconnecting clients can resume.
+// It implements outbox.Publisher.
+type Hub struct {
+	mu          sync.Mutex
+	buffer      []domain.Event
+	size        int
+	subscribers map[chan domain.Event]struct{}
+}
+
+func NewHub(size int) *Hub {
+	return &Hub{
+		size:        size,
+		subscribers: make(map[chan domain.Event]struct{}),
+	}
+}
+
+// Publish sends e to the subscribers. An event still in the buffer was already
+// sent, and is ignored when the outbox publishes it again.
+func (h *Hub) Publish(_ context.Context, e domain.Event) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i := len(h.buffer) - 1; i >= 0; i-- {
+		if h.buffer[i].ID == e.ID {
+			return nil
+		}
+	}
+
+	h.buffer = append(h.buffer, e)
+	if len(h.buffer) > h.size {
+		h.buffer = h.buffer[len(h.buffer)-h.size:]
+	}
+
+	for ch := range h.subscribers {
+		select {
+		case ch <- e:
+		default:
+			delete(h.subscribers, ch)
+			close(ch)
+		}
+	}
+	return nil
+}
+
+// Subscribe returns the buffered events published after lastEventID, or every
+// buffered event when lastEventID is unknown, followed by a channel receiving the
+// events published from now on. The channel is closed when the subscriber falls
+// too far behind. cancel must be called once the subscriber is gone.
+func (h *Hub) Subscribe(lastEventID string) (replay []domain.Event, events <-chan domain.Event, cancel func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if lastEventID != "" {
+		replay = h.buffer
+		for i, e := range h.buffer {
+			if e.ID == lastEventID {
+				replay = h.buffer[i+1:]
+				break
+			}
+		}
+		replay = append([]domain.Event(nil), replay...)
+	}
+
+	ch := make(chan domain.Event, subscriberBuffer)
+	h.subscribers[ch] = struct{}{}
+
+	return replay, ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		if _, ok := h.subscribers[ch]; ok {
+			delete(h.subscribers, ch)
+			close(ch)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package stream
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func publish(h *Hub, ids ...string) {
+	for _, id := range ids {
+		_ = h.Publish(context.Background(), domain.Event{ID: id, Type: domain.EventCustomerUpdated})
+	}
+}
+
+func ids(events []domain.Event) []string {
+	var result []string
+	for _, e := range events {
+		result = append(result, e.ID)
+	}
+	return result
+}
+
+func TestHub(t *testing.T) {
+	t.Run("A new subscriber without Last-Event-ID only receives new events.", func(t *testing.T) {
+		hub := NewHub(10)
+		publish(hub, "a")
+
+		replay, events, cancel := hub.Subscribe("")
+		defer cancel()
+		publish(hub, "b")
+
+		assert.Empty(t, replay)
+		assert.Equal(t, "b", (<-events).ID)
+	})
+
+	t.Run("A resuming subscriber receives the buffered events after its last one.", func(t *testing.T) {
+		hub := NewHub(10)
+		publish(hub, "a", "b", "c")
+
+		replay, _, cancel := hub.Subscribe("a")
+		defer cancel()
+
+		assert.Equal(t, []string{"b", "c"}, ids(replay))
+	})
+
+	t.Run("When the last event is no longer buffered, every buffered event is replayed.", func(t *testing.T) {
+		hub := NewHub(2)
+		publish(hub, "a", "b", "c")
+
+		replay, _, cancel := hub.Subscribe("a")
+		defer cancel()
+
+		assert.Equal(t, []string{"b", "c"}, ids(replay))
+	})
+
+	t.Run("A subscriber falling too far behind is disconnected.", func(t *testing.T) {
+		hub := NewHub(1)
+		_, events, cancel := hub.Subscribe("")
+		defer cancel()
+
+		for i := 0; i <= subscriberBuffer; i++ {
+			publish(hub, strconv.Itoa(i))
+		}
+
+		received := 0
+		for range events {
+			received++
+		}
+		assert.Equal(t, subscriberBuffer, received)
+	})
+	t.Run("An event published again while still buffered is not sent twice.", func(t *testing.T) {
+		hub := NewHub(10)
+		_, events, cancel := hub.Subscribe("")
+		defer cancel()
+
+		publish(hub, "a", "a", "b")
+
+		assert.Equal(t, "a", (<-events).ID)
+		assert.Equal(t, "b", (<-events).ID)
+	})
+}
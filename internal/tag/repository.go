@@ -0,0 +1,181 @@
+package tag
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/pkg/database"
+)
+
+type Repository interface {
+	GetByCustomerIDWithContext(ctx context.Context, customerID int) ([]domain.Tag, error)
+	GetByCustomerIDsWithContext(ctx context.Context, customerIDs []int) (map[int][]domain.Tag, error)
+	EnsureWithContext(ctx context.Context, names []string, createdAt time.Time) ([]int, error)
+	AddWithContext(ctx context.Context, customerIDs, tagIDs []int, taggedAt time.Time) error
+	RemoveWithContext(ctx context.Context, customerID int, name string) error
+	CountWithContext(ctx context.Context) ([]domain.TagCount, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) GetByCustomerIDWithContext(ctx context.Context, customerID int) ([]domain.Tag, error) {
+	byCustomer, err := r.GetByCustomerIDsWithContext(ctx, []int{customerID})
+	if err != nil {
+		return nil, err
+	}
+	return byCustomer[customerID], nil
+}
+
+// GetByCustomerIDsWithContext returns the tags of several customers at once, keyed
+// by customer ID and ordered by name. Customers without tags are left out.
+func (r *repository) GetByCustomerIDsWithContext(ctx context.Context, customerIDs []int) (map[int][]domain.Tag, error) {
+	byCustomer := make(map[int][]domain.Tag)
+	if len(customerIDs) == 0 {
+		return byCustomer, nil
+	}
+
+	query := "SELECT ct.customer_id, t.name, ct.tagged_at FROM customer_tags ct JOIN tags t ON t.tag_id=ct.tag_id " +
+		"WHERE ct.customer_id IN (" + placeholders(len(customerIDs)) + ") ORDER BY ct.customer_id, t.name;"
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, query, intArgs(customerIDs)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var customerID int
+		t := domain.Tag{}
+		if err := rows.Scan(&customerID, &t.Name, &t.TaggedAt); err != nil {
+			return nil, err
+		}
+		byCustomer[customerID] = append(byCustomer[customerID], t)
+	}
+
+	return byCustomer, rows.Err()
+}
+
+// EnsureWithContext creates the tags that do not exist yet and returns the IDs of
+// every tag named.
+func (r *repository) EnsureWithContext(ctx context.Context, names []string, createdAt time.Time) ([]int, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	args := make([]any, 0, len(names)*2)
+	for _, name := range names {
+		args = append(args, name, createdAt)
+	}
+	insert := "INSERT INTO tags (name, created_at) VALUES " +
+		strings.TrimSuffix(strings.Repeat("(?, ?),", len(names)), ",") + " ON DUPLICATE KEY UPDATE tag_id=tag_id;"
+	if _, err := database.Conn(ctx, r.db).ExecContext(ctx, insert, args...); err != nil {
+		return nil, err
+	}
+
+	args = make([]any, len(names))
+	for i, name := range names {
+		args[i] = name
+	}
+	query := "SELECT tag_id FROM tags WHERE name IN (" + placeholders(len(names)) + ");"
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// AddWithContext puts every tag on every customer, leaving the tags a customer
+// already carries untouched.
+func (r *repository) AddWithContext(ctx context.Context, customerIDs, tagIDs []int, taggedAt time.Time) error {
+	if len(customerIDs) == 0 || len(tagIDs) == 0 {
+		return nil
+	}
+
+	args := make([]any, 0, len(customerIDs)*len(tagIDs)*3)
+	for _, customerID := range customerIDs {
+		for _, tagID := range tagIDs {
+			args = append(args, customerID, tagID, taggedAt)
+		}
+	}
+	query := "INSERT IGNORE INTO customer_tags (customer_id, tag_id, tagged_at) VALUES " +
+		strings.TrimSuffix(strings.Repeat("(?, ?, ?),", len(customerIDs)*len(tagIDs)), ",") + ";"
+	_, err := database.Conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *repository) RemoveWithContext(ctx context.Context, customerID int, name string) error {
+	query := "DELETE ct FROM customer_tags ct JOIN tags t ON t.tag_id=ct.tag_id WHERE ct.customer_id=? and t.name=?;"
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, query, customerID, name)
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affect < 1 {
+		return ErrorTagNotFound
+	}
+
+	return nil
+}
+
+// CountWithContext returns how many customers carry each tag, the most used tags
+// first. Deleted customers and unused tags are not counted.
+func (r *repository) CountWithContext(ctx context.Context) ([]domain.TagCount, error) {
+	query := "SELECT t.name, COUNT(*) AS customers FROM tags t " +
+		"JOIN customer_tags ct ON ct.tag_id=t.tag_id " +
+		"JOIN customers c ON c.customer_id=ct.customer_id and c.deleted_at IS NULL " +
+		"GROUP BY t.tag_id, t.name ORDER BY customers DESC, t.name;"
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []domain.TagCount
+	for rows.Next() {
+		c := domain.TagCount{}
+		if err := rows.Scan(&c.Name, &c.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+
+	return counts, rows.Err()
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+func intArgs(values []int) []any {
+	args := make([]any, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return args
+}
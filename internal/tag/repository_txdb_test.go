@@ -0,0 +1,76 @@
+package tag
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuite_TagRepository(t *testing.T) {
+	db, err := testutil.InitTxdbDatabase(t)
+	assert.NoError(t, err)
+	repository := NewRepository(db)
+	customers := customer.NewRepository(db)
+
+	testTagsWithContext(t, repository, customers)
+
+	db.Close()
+}
+
+func saveCustomer(ctx context.Context, t *testing.T, customers customer.Repository, number int) int {
+	t.Helper()
+	id, err := customers.SaveWithContext(ctx, domain.Customer{
+		CustomerNumber: number,
+		FirstName:      "Danilo",
+		LastName:       "Sano",
+		CreatedAt:      time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	assert.NoError(t, err)
+	return id
+}
+
+func testTagsWithContext(t *testing.T, repository Repository, customers customer.Repository) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	now := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	first := saveCustomer(ctx, t, customers, 717171)
+	second := saveCustomer(ctx, t, customers, 727272)
+
+	vip, err := repository.EnsureWithContext(ctx, []string{"txdb-vip"}, now)
+	assert.NoError(t, err)
+	both, err := repository.EnsureWithContext(ctx, []string{"txdb-vip", "txdb-churn-risk"}, now)
+	assert.NoError(t, err)
+	assert.Len(t, both, 2)
+	assert.Contains(t, both, vip[0])
+
+	assert.NoError(t, repository.AddWithContext(ctx, []int{first, second}, vip, now))
+	assert.NoError(t, repository.AddWithContext(ctx, []int{first}, both, now))
+
+	tags, err := repository.GetByCustomerIDWithContext(ctx, first)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Tag{{Name: "txdb-churn-risk", TaggedAt: now}, {Name: "txdb-vip", TaggedAt: now}}, tags)
+
+	_, total, err := customers.ListWithContext(ctx, dto.CustomerFilter{Tags: []string{"TXDB-VIP", "txdb-churn-risk"}, Page: 1, PageSize: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+
+	listed, total, err := customers.ListWithContext(ctx, dto.CustomerFilter{Tags: []string{"txdb-vip", "txdb-churn-risk"}, TagMatch: "all", Page: 1, PageSize: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, first, listed[0].ID)
+
+	counts, err := repository.CountWithContext(ctx)
+	assert.NoError(t, err)
+	assert.Contains(t, counts, domain.TagCount{Name: "txdb-vip", Count: 2})
+
+	assert.NoError(t, repository.RemoveWithContext(ctx, second, "txdb-vip"))
+	assert.ErrorIs(t, repository.RemoveWithContext(ctx, second, "txdb-vip"), ErrorTagNotFound)
+}
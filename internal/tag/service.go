@@ -0,0 +1,156 @@
+package tag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/pkg/database"
+)
+
+var (
+	ErrorTagNotFound = errors.New("tag not found")
+	ErrorInvalidTag  = errors.New("invalid tag")
+)
+
+// namePattern is the shape of a normalized tag name, such as "vip" or "churn-risk".
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_:-]*$`)
+
+type Service interface {
+	GetAll(ctx context.Context, customerID int) ([]domain.Tag, error)
+	Add(ctx context.Context, customerID int, names []string) ([]domain.Tag, error)
+	AddToCustomers(ctx context.Context, customerIDs []int, names []string) error
+	Remove(ctx context.Context, customerID int, name string) error
+	Counts(ctx context.Context) ([]domain.TagCount, error)
+	GetByCustomerIDs(ctx context.Context, customerIDs []int) (map[int][]domain.Tag, error)
+}
+
+type service struct {
+	repository Repository
+	customers  customer.Repository
+	transactor database.Transactor
+}
+
+type Option func(*service)
+
+// WithTransactor creates the tags and puts them on the customers in a transaction.
+func WithTransactor(t database.Transactor) Option {
+	return func(s *service) {
+		s.transactor = t
+	}
+}
+
+// NewService manages the tags of the customers found in customers. Tags are
+// created the first time they are put on a customer.
+func NewService(r Repository, customers customer.Repository, opts ...Option) Service {
+	s := &service{
+		repository: r,
+		customers:  customers,
+		transactor: database.NoopTransactor(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *service) GetAll(ctx context.Context, customerID int) ([]domain.Tag, error) {
+	if customerExist := s.customers.ExistsByIDWithContext(ctx, customerID); !customerExist {
+		return nil, customer.ErrorCustomerNotFound
+	}
+
+	return s.repository.GetByCustomerIDWithContext(ctx, customerID)
+}
+
+// Add puts the tags on a customer and returns every tag the customer carries.
+func (s *service) Add(ctx context.Context, customerID int, names []string) ([]domain.Tag, error) {
+	if customerExist := s.customers.ExistsByIDWithContext(ctx, customerID); !customerExist {
+		return nil, customer.ErrorCustomerNotFound
+	}
+
+	if err := s.add(ctx, []int{customerID}, names); err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetByCustomerIDWithContext(ctx, customerID)
+}
+
+// AddToCustomers puts the same tags on several customers, all or none of them.
+func (s *service) AddToCustomers(ctx context.Context, customerIDs []int, names []string) error {
+	for _, id := range customerIDs {
+		if customerExist := s.customers.ExistsByIDWithContext(ctx, id); !customerExist {
+			return fmt.Errorf("%w: %d", customer.ErrorCustomerNotFound, id)
+		}
+	}
+
+	return s.add(ctx, customerIDs, names)
+}
+
+// add creates the tags that do not exist yet and puts them on the customers.
+func (s *service) add(ctx context.Context, customerIDs []int, names []string) error {
+	names, err := normalizeAll(names)
+	if err != nil {
+		return err
+	}
+
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		now := time.Now().Truncate(time.Second)
+		tagIDs, err := s.repository.EnsureWithContext(ctx, names, now)
+		if err != nil {
+			return err
+		}
+
+		return s.repository.AddWithContext(ctx, customerIDs, tagIDs, now)
+	})
+}
+
+func (s *service) Remove(ctx context.Context, customerID int, name string) error {
+	if customerExist := s.customers.ExistsByIDWithContext(ctx, customerID); !customerExist {
+		return customer.ErrorCustomerNotFound
+	}
+
+	return s.repository.RemoveWithContext(ctx, customerID, strings.ToLower(strings.TrimSpace(name)))
+}
+
+func (s *service) Counts(ctx context.Context) ([]domain.TagCount, error) {
+	return s.repository.CountWithContext(ctx)
+}
+
+// GetByCustomerIDs returns the tags of several customers, keyed by customer ID.
+// Customers without tags are left out.
+func (s *service) GetByCustomerIDs(ctx context.Context, customerIDs []int) (map[int][]domain.Tag, error) {
+	return s.repository.GetByCustomerIDsWithContext(ctx, customerIDs)
+}
+
+// Normalize returns a tag name in lower case without surrounding spaces. Names
+// made of anything but letters, digits, '-', '_' and ':' are rejected with
+// ErrorInvalidTag.
+func Normalize(name string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(name))
+	if !namePattern.MatchString(normalized) {
+		return "", fmt.Errorf("%w: %q", ErrorInvalidTag, name)
+	}
+	return normalized, nil
+}
+
+// normalizeAll normalizes the names, dropping repeated ones.
+func normalizeAll(names []string) ([]string, error) {
+	var normalized []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		n, err := Normalize(name)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[n] {
+			seen[n] = true
+			normalized = append(normalized, n)
+		}
+	}
+	return normalized, nil
+}
@@ -0,0 +1,135 @@
+package tag
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain"
+	customerMocks "github.com/danilosano/web-golang-api/pkg/tests/customers"
+	mocks "github.com/danilosano/web-golang-api/pkg/tests/tags"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func createService(t *testing.T) (Service, *mocks.TagRepositoryMock, *customerMocks.CustomersRepositoryMock, context.Context) {
+	t.Helper()
+	repoMock := new(mocks.TagRepositoryMock)
+	customersMock := new(customerMocks.CustomersRepositoryMock)
+	return NewService(repoMock, customersMock), repoMock, customersMock, context.Background()
+}
+
+var mockedTags = []domain.Tag{
+	{Name: "churn-risk", TaggedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+	{Name: "vip", TaggedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+}
+
+func TestAdd(t *testing.T) {
+	t.Run("The names are normalized and deduplicated, and every tag of the customer is returned.", func(t *testing.T) {
+		service, repoMock, customersMock, ctx := createService(t)
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("EnsureWithContext", ctx, []string{"vip", "churn-risk"}, mock.Anything).Return([]int{1, 2}, nil)
+		repoMock.On("AddWithContext", ctx, []int{1}, []int{1, 2}, mock.Anything).Return(nil)
+		repoMock.On("GetByCustomerIDWithContext", ctx, 1).Return(mockedTags, nil)
+
+		result, err := service.Add(ctx, 1, []string{" VIP", "churn-risk", "vip"})
+		assert.Nil(t, err)
+		assert.Equal(t, mockedTags, result)
+	})
+
+	t.Run("A name with other than letters, digits, '-', '_' and ':' is rejected.", func(t *testing.T) {
+		service, repoMock, customersMock, ctx := createService(t)
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+
+		_, err := service.Add(ctx, 1, []string{"vip", "high value"})
+		assert.ErrorIs(t, err, ErrorInvalidTag)
+		repoMock.AssertNotCalled(t, "EnsureWithContext", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("When the customer does not exist, an error will be returned.", func(t *testing.T) {
+		service, _, customersMock, ctx := createService(t)
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(false)
+
+		_, err := service.Add(ctx, 1, []string{"vip"})
+		assert.Equal(t, customer.ErrorCustomerNotFound, err)
+	})
+}
+
+func TestAddToCustomers(t *testing.T) {
+	t.Run("The tags are put on every customer at once.", func(t *testing.T) {
+		service, repoMock, customersMock, ctx := createService(t)
+		customersMock.On("ExistsByIDWithContext", ctx, mock.Anything).Return(true)
+		repoMock.On("EnsureWithContext", ctx, []string{"vip"}, mock.Anything).Return([]int{1}, nil)
+		repoMock.On("AddWithContext", ctx, []int{1, 2, 3}, []int{1}, mock.Anything).Return(nil)
+
+		err := service.AddToCustomers(ctx, []int{1, 2, 3}, []string{"vip"})
+		assert.Nil(t, err)
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("When a customer does not exist, no customer is tagged and its ID is reported.", func(t *testing.T) {
+		service, repoMock, customersMock, ctx := createService(t)
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		customersMock.On("ExistsByIDWithContext", ctx, 2).Return(false)
+
+		err := service.AddToCustomers(ctx, []int{1, 2}, []string{"vip"})
+		assert.ErrorIs(t, err, customer.ErrorCustomerNotFound)
+		assert.EqualError(t, err, "customer not found: 2")
+		repoMock.AssertNotCalled(t, "AddWithContext", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestRemove(t *testing.T) {
+	t.Run("The tag is removed regardless of case.", func(t *testing.T) {
+		service, repoMock, customersMock, ctx := createService(t)
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("RemoveWithContext", ctx, 1, "vip").Return(nil)
+
+		err := service.Remove(ctx, 1, "VIP")
+		assert.Nil(t, err)
+	})
+
+	t.Run("When the customer does not carry the tag, an error will be returned.", func(t *testing.T) {
+		service, repoMock, customersMock, ctx := createService(t)
+		customersMock.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		repoMock.On("RemoveWithContext", ctx, 1, "vip").Return(ErrorTagNotFound)
+
+		err := service.Remove(ctx, 1, "vip")
+		assert.Equal(t, ErrorTagNotFound, err)
+	})
+}
+
+func TestCounts(t *testing.T) {
+	t.Run("The counts of the repository are returned.", func(t *testing.T) {
+		service, repoMock, _, ctx := createService(t)
+		counts := []domain.TagCount{{Name: "vip", Count: 3}}
+		repoMock.On("CountWithContext", ctx).Return(counts, nil)
+
+		result, err := service.Counts(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, counts, result)
+	})
+
+	t.Run("If the backend returns an unexpected error, return the error.", func(t *testing.T) {
+		service, repoMock, _, ctx := createService(t)
+		repoMock.On("CountWithContext", ctx).Return(nil, errors.New("generic error"))
+
+		_, err := service.Counts(ctx)
+		assert.Equal(t, errors.New("generic error"), err)
+	})
+}
+
+func TestNormalize(t *testing.T) {
+	t.Run("Names are trimmed and lower-cased, and invalid ones rejected.", func(t *testing.T) {
+		name, err := Normalize("  Churn-Risk ")
+		assert.Nil(t, err)
+		assert.Equal(t, "churn-risk", name)
+
+		for _, invalid := range []string{"", " ", "-vip", "high value", "vip!"} {
+			_, err := Normalize(invalid)
+			assert.ErrorIs(t, err, ErrorInvalidTag, invalid)
+		}
+	})
+}
@@ -0,0 +1,204 @@
+package timeline
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/audit"
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/danilosano/web-golang-api/internal/note"
+)
+
+var (
+	ErrorInvalidCursor = errors.New("invalid cursor")
+)
+
+const (
+	DefaultLimit = 20
+)
+
+// eventTypes orders the events of different types recorded at the same time.
+var eventTypes = []string{domain.TimelineEventAudit, domain.TimelineEventNote, domain.TimelineEventStatusChange}
+
+type Service interface {
+	Get(ctx context.Context, customerID int, f dto.TimelineFilter) (dto.TimelinePage, error)
+}
+
+type service struct {
+	notes     note.Repository
+	audits    audit.Repository
+	customers customer.Repository
+}
+
+// NewService merges the audit log, the notes and the status changes of the
+// customers into a single timeline.
+func NewService(notes note.Repository, audits audit.Repository, customers customer.Repository) Service {
+	return &service{
+		notes:     notes,
+		audits:    audits,
+		customers: customers,
+	}
+}
+
+// position is the place of an event in the timeline, ordered by date, type and
+// ID. The cursor of a page is the position of its last event.
+type position struct {
+	at        time.Time
+	eventType string
+	id        int
+}
+
+type event struct {
+	position
+	domain.TimelineEvent
+}
+
+// Get returns the events of a customer that follow the cursor of f, oldest
+// first. Each source is read from the cursor on, so a page costs a query per
+// source however deep it is.
+func (s *service) Get(ctx context.Context, customerID int, f dto.TimelineFilter) (dto.TimelinePage, error) {
+	if customerExist := s.customers.ExistsByIDWithContext(ctx, customerID); !customerExist {
+		return dto.TimelinePage{}, customer.ErrorCustomerNotFound
+	}
+
+	after, err := decodeCursor(f.Cursor)
+	if err != nil {
+		return dto.TimelinePage{}, err
+	}
+
+	limit := f.Limit
+	if limit == 0 {
+		limit = DefaultLimit
+	}
+
+	// One more event than requested tells whether another page follows.
+	events, err := s.events(ctx, customerID, after, limit+1)
+	if err != nil {
+		return dto.TimelinePage{}, err
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].position.before(events[j].position)
+	})
+
+	page := dto.TimelinePage{Events: []domain.TimelineEvent{}}
+	if len(events) > limit {
+		events = events[:limit]
+		page.NextCursor = encodeCursor(events[limit-1].position)
+	}
+	for _, e := range events {
+		page.Events = append(page.Events, e.TimelineEvent)
+	}
+
+	return page, nil
+}
+
+// events reads up to limit events of each source following after.
+func (s *service) events(ctx context.Context, customerID int, after position, limit int) ([]event, error) {
+	var events []event
+
+	entries, err := s.audits.GetByCustomerIDAfterWithContext(ctx, customerID, after.at, after.idFor(domain.TimelineEventAudit), limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		events = append(events, event{
+			position:      position{at: e.CreatedAt, eventType: domain.TimelineEventAudit, id: e.ID},
+			TimelineEvent: domain.TimelineEvent{Type: domain.TimelineEventAudit, OccurredAt: e.CreatedAt, Actor: e.Actor, Audit: &e},
+		})
+	}
+
+	notes, err := s.notes.GetAfterWithContext(ctx, customerID, after.at, after.idFor(domain.TimelineEventNote), limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range notes {
+		events = append(events, event{
+			position:      position{at: n.CreatedAt, eventType: domain.TimelineEventNote, id: n.ID},
+			TimelineEvent: domain.TimelineEvent{Type: domain.TimelineEventNote, OccurredAt: n.CreatedAt, Actor: n.Author, Note: &n},
+		})
+	}
+
+	changes, err := s.customers.GetStatusChangesAfterWithContext(ctx, customerID, after.at, after.idFor(domain.TimelineEventStatusChange), limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range changes {
+		events = append(events, event{
+			position:      position{at: c.CreatedAt, eventType: domain.TimelineEventStatusChange, id: c.ID},
+			TimelineEvent: domain.TimelineEvent{Type: domain.TimelineEventStatusChange, OccurredAt: c.CreatedAt, Actor: c.Actor, StatusChange: &c},
+		})
+	}
+
+	return events, nil
+}
+
+func (p position) before(other position) bool {
+	if !p.at.Equal(other.at) {
+		return p.at.Before(other.at)
+	}
+	if p.eventType != other.eventType {
+		return slices.Index(eventTypes, p.eventType) < slices.Index(eventTypes, other.eventType)
+	}
+	return p.id < other.id
+}
+
+// idFor returns the ID after which the events of eventType recorded at p.at
+// follow p: those of a type ordered after p all do, those of a type ordered
+// before none do.
+func (p position) idFor(eventType string) int {
+	switch rank, current := slices.Index(eventTypes, eventType), slices.Index(eventTypes, p.eventType); {
+	case rank > current:
+		return 0
+	case rank < current:
+		return math.MaxInt32
+	default:
+		return p.id
+	}
+}
+
+// encodeCursor makes an opaque cursor of the position of an event.
+func encodeCursor(p position) string {
+	raw := fmt.Sprintf("%d:%s:%d", p.at.Unix(), p.eventType, p.id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor reads a cursor made by encodeCursor. The empty cursor is the
+// position before every event.
+func decodeCursor(cursor string) (position, error) {
+	if cursor == "" {
+		return position{}, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return position{}, ErrorInvalidCursor
+	}
+
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 || !slices.Contains(eventTypes, parts[1]) {
+		return position{}, ErrorInvalidCursor
+	}
+
+	sec, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return position{}, ErrorInvalidCursor
+	}
+
+	id, err := strconv.Atoi(parts[2])
+	if err != nil || id < 1 {
+		return position{}, ErrorInvalidCursor
+	}
+
+	return position{at: time.Unix(sec, 0), eventType: parts[1], id: id}, nil
+}
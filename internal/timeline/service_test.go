@@ -0,0 +1,109 @@
+package timeline
+
+import (
+	"context"
+	"encoding/base64"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/customer"
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	auditMocks "github.com/danilosano/web-golang-api/pkg/tests/audit"
+	customerMocks "github.com/danilosano/web-golang-api/pkg/tests/customers"
+	noteMocks "github.com/danilosano/web-golang-api/pkg/tests/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type sources struct {
+	notes     *noteMocks.NoteRepositoryMock
+	audits    *auditMocks.AuditRepositoryMock
+	customers *customerMocks.CustomersRepositoryMock
+}
+
+func createService(t *testing.T) (Service, sources, context.Context) {
+	t.Helper()
+	s := sources{
+		notes:     new(noteMocks.NoteRepositoryMock),
+		audits:    new(auditMocks.AuditRepositoryMock),
+		customers: new(customerMocks.CustomersRepositoryMock),
+	}
+	return NewService(s.notes, s.audits, s.customers), s, context.Background()
+}
+
+var (
+	day1 = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 = time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+	day3 = time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC)
+)
+
+func TestGet(t *testing.T) {
+	t.Run("The sources are merged oldest first, ties ordered by type, and a cursor is returned when more follow.", func(t *testing.T) {
+		service, s, ctx := createService(t)
+		s.customers.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		s.audits.On("GetByCustomerIDAfterWithContext", ctx, 1, time.Time{}, 0, 3).Return([]domain.AuditEntry{
+			{ID: 1, Actor: "agent-1", Action: domain.AuditActionCreate, CreatedAt: day1},
+			{ID: 2, Actor: "agent-1", Action: domain.AuditActionUpdate, CreatedAt: day2},
+		}, nil)
+		s.notes.On("GetAfterWithContext", ctx, 1, time.Time{}, 0, 3).Return([]domain.Note{
+			{ID: 7, Author: "agent-2", Body: "Called.", CreatedAt: day3},
+		}, nil)
+		s.customers.On("GetStatusChangesAfterWithContext", ctx, 1, time.Time{}, 0, 3).Return([]domain.CustomerStatusChange{
+			{ID: 4, Actor: "agent-1", From: "prospect", To: "active", CreatedAt: day2},
+		}, nil)
+
+		page, err := service.Get(ctx, 1, dto.TimelineFilter{Limit: 2})
+		assert.Nil(t, err)
+		if assert.Len(t, page.Events, 2) {
+			assert.Equal(t, domain.TimelineEventAudit, page.Events[0].Type)
+			assert.Equal(t, 1, page.Events[0].Audit.ID)
+			assert.Equal(t, domain.TimelineEventAudit, page.Events[1].Type)
+			assert.Equal(t, day2, page.Events[1].OccurredAt)
+		}
+		assert.NotEmpty(t, page.NextCursor)
+
+		after, err := decodeCursor(page.NextCursor)
+		assert.Nil(t, err)
+		assert.True(t, after.at.Equal(day2))
+		assert.Equal(t, position{at: after.at, eventType: domain.TimelineEventAudit, id: 2}, after)
+	})
+
+	t.Run("The next page starts after the cursor in every source.", func(t *testing.T) {
+		service, s, ctx := createService(t)
+		cursor := encodeCursor(position{at: day2, eventType: domain.TimelineEventNote, id: 5})
+		s.customers.On("ExistsByIDWithContext", ctx, 1).Return(true)
+		s.audits.On("GetByCustomerIDAfterWithContext", ctx, 1, mock.Anything, math.MaxInt32, 21).Return(nil, nil)
+		s.notes.On("GetAfterWithContext", ctx, 1, mock.Anything, 5, 21).Return(nil, nil)
+		s.customers.On("GetStatusChangesAfterWithContext", ctx, 1, mock.Anything, 0, 21).Return(nil, nil)
+
+		page, err := service.Get(ctx, 1, dto.TimelineFilter{Cursor: cursor})
+		assert.Nil(t, err)
+		assert.Equal(t, dto.TimelinePage{Events: []domain.TimelineEvent{}}, page)
+		s.audits.AssertExpectations(t)
+		s.notes.AssertExpectations(t)
+	})
+
+	t.Run("A cursor not made by the service is rejected.", func(t *testing.T) {
+		for _, cursor := range []string{"not base64!", encodeRaw("1609459200:order:1"), encodeRaw("1609459200:note")} {
+			service, s, ctx := createService(t)
+			s.customers.On("ExistsByIDWithContext", ctx, 1).Return(true)
+
+			_, err := service.Get(ctx, 1, dto.TimelineFilter{Cursor: cursor})
+			assert.Equal(t, ErrorInvalidCursor, err)
+		}
+	})
+
+	t.Run("When the customer does not exist, an error will be returned.", func(t *testing.T) {
+		service, s, ctx := createService(t)
+		s.customers.On("ExistsByIDWithContext", ctx, 1).Return(false)
+
+		_, err := service.Get(ctx, 1, dto.TimelineFilter{})
+		assert.Equal(t, customer.ErrorCustomerNotFound, err)
+	})
+}
+
+func encodeRaw(raw string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
@@ -0,0 +1,27 @@
+package webhook
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublicAddress(t *testing.T) {
+	t.Run("The addresses that are not globally reachable, or may translate to one, are refused.", func(t *testing.T) {
+		for _, ip := range []string{
+			"0.0.0.0", "0.1.2.3", "10.0.0.5", "100.64.0.1", "100.127.255.254", "127.0.0.1", "169.254.169.254",
+			"172.16.0.1", "192.0.0.8", "192.168.1.1", "198.18.0.1", "198.19.255.255", "224.0.0.1", "255.255.255.255",
+			"::", "::1", "::ffff:10.0.0.5", "64:ff9b::a00:5", "64:ff9b:1::1", "2001::1", "2002:a00:5::1",
+			"fd00::1", "fe80::1", "ff02::1",
+		} {
+			assert.False(t, publicAddress(net.ParseIP(ip)), ip)
+		}
+	})
+
+	t.Run("Public addresses are allowed.", func(t *testing.T) {
+		for _, ip := range []string{"8.8.8.8", "100.128.0.1", "198.20.0.1", "203.0.113.10", "::ffff:8.8.8.8", "2606:4700:4700::1111"} {
+			assert.True(t, publicAddress(net.ParseIP(ip)), ip)
+		}
+	})
+}
@@ -0,0 +1,39 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/outbox"
+)
+
+type publisher struct {
+	repository Repository
+}
+
+// NewPublisher returns an outbox.Publisher scheduling a delivery of every event to
+// each active subscription listening to its type. The Worker sends them. An event
+// published again is not scheduled twice.
+func NewPublisher(r Repository) outbox.Publisher {
+	return &publisher{repository: r}
+}
+
+func (p *publisher) Publish(ctx context.Context, e domain.Event) error {
+	subscriptions, err := p.repository.GetActiveByEventTypeWithContext(ctx, e.Type)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range subscriptions {
+		if _, err := p.repository.SaveDeliveryWithContext(ctx, newDelivery(sub.ID, e.ID, e.Type, payload)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,253 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/pkg/database"
+)
+
+type Repository interface {
+	GetAllWithContext(ctx context.Context) ([]domain.WebhookSubscription, error)
+	GetWithContext(ctx context.Context, id int) (domain.WebhookSubscription, error)
+	GetActiveByEventTypeWithContext(ctx context.Context, eventType string) ([]domain.WebhookSubscription, error)
+	SaveWithContext(ctx context.Context, s domain.WebhookSubscription) (int, error)
+	UpdateWithContext(ctx context.Context, s domain.WebhookSubscription) error
+	DeleteWithContext(ctx context.Context, id int) error
+
+	GetDeliveriesWithContext(ctx context.Context, subscriptionID int) ([]domain.WebhookDelivery, error)
+	GetDeliveryWithContext(ctx context.Context, subscriptionID, id int) (domain.WebhookDelivery, error)
+	GetDueDeliveriesWithContext(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error)
+	SaveDeliveryWithContext(ctx context.Context, d domain.WebhookDelivery) (int, error)
+	SaveRedeliveryWithContext(ctx context.Context, d domain.WebhookDelivery) (int, error)
+	LockDeliveriesWithContext(ctx context.Context, ids []int, until time.Time) error
+	UpdateDeliveryWithContext(ctx context.Context, d domain.WebhookDelivery) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+const subscriptionColumns = "subscription_id, url, event_types, secret, active, created_at, updated_at"
+
+func (r *repository) GetAllWithContext(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	query := "SELECT " + subscriptionColumns + " FROM webhook_subscriptions WHERE deleted_at IS NULL;"
+	return r.querySubscriptions(ctx, query)
+}
+
+func (r *repository) GetWithContext(ctx context.Context, id int) (domain.WebhookSubscription, error) {
+	query := "SELECT " + subscriptionColumns + " FROM webhook_subscriptions WHERE deleted_at IS NULL and subscription_id=?;"
+	row := database.Conn(ctx, r.db).QueryRowContext(ctx, query, id)
+	s, err := scanSubscription(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.WebhookSubscription{}, ErrorWebhookNotFound
+		}
+		return domain.WebhookSubscription{}, err
+	}
+
+	return s, nil
+}
+
+func (r *repository) GetActiveByEventTypeWithContext(ctx context.Context, eventType string) ([]domain.WebhookSubscription, error) {
+	query := "SELECT " + subscriptionColumns + " FROM webhook_subscriptions WHERE deleted_at IS NULL and active=TRUE and FIND_IN_SET(?, event_types);"
+	return r.querySubscriptions(ctx, query, eventType)
+}
+
+func (r *repository) SaveWithContext(ctx context.Context, s domain.WebhookSubscription) (int, error) {
+	query := "INSERT INTO webhook_subscriptions (url, event_types, secret, active, created_at) VALUES (?, ?, ?, ?, ?);"
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, query, s.URL, strings.Join(s.EventTypes, ","), s.Secret, s.Active, s.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+func (r *repository) UpdateWithContext(ctx context.Context, s domain.WebhookSubscription) error {
+	query := "UPDATE webhook_subscriptions SET url=?, event_types=?, secret=?, active=?, updated_at=? WHERE subscription_id=? and deleted_at IS NULL;"
+	_, err := database.Conn(ctx, r.db).ExecContext(ctx, query, s.URL, strings.Join(s.EventTypes, ","), s.Secret, s.Active, s.UpdatedAt, s.ID)
+	return err
+}
+
+func (r *repository) DeleteWithContext(ctx context.Context, id int) error {
+	query := "UPDATE webhook_subscriptions SET deleted_at=? WHERE subscription_id=? and deleted_at IS NULL;"
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, query, time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affect < 1 {
+		return ErrorWebhookNotFound
+	}
+
+	return nil
+}
+
+const deliveryColumns = "delivery_id, subscription_id, event_id, event_type, payload, status, attempts, response_status, last_error, next_attempt_at, delivered_at, created_at"
+
+func (r *repository) GetDeliveriesWithContext(ctx context.Context, subscriptionID int) ([]domain.WebhookDelivery, error) {
+	query := "SELECT " + deliveryColumns + " FROM webhook_deliveries WHERE subscription_id=? ORDER BY created_at DESC, delivery_id DESC;"
+	return r.queryDeliveries(ctx, query, subscriptionID)
+}
+
+func (r *repository) GetDeliveryWithContext(ctx context.Context, subscriptionID, id int) (domain.WebhookDelivery, error) {
+	query := "SELECT " + deliveryColumns + " FROM webhook_deliveries WHERE subscription_id=? and delivery_id=?;"
+	row := database.Conn(ctx, r.db).QueryRowContext(ctx, query, subscriptionID, id)
+	d, err := scanDelivery(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.WebhookDelivery{}, ErrorDeliveryNotFound
+		}
+		return domain.WebhookDelivery{}, err
+	}
+
+	return d, nil
+}
+
+// GetDueDeliveriesWithContext returns the pending deliveries whose next attempt is due.
+// Within a transaction the rows are locked, and rows locked by another worker are skipped.
+func (r *repository) GetDueDeliveriesWithContext(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	query := "SELECT " + deliveryColumns + " FROM webhook_deliveries WHERE status=? and next_attempt_at<=? ORDER BY next_attempt_at, delivery_id LIMIT ?"
+	if database.InTransaction(ctx) {
+		query += " FOR UPDATE SKIP LOCKED"
+	}
+	return r.queryDeliveries(ctx, query+";", domain.DeliveryStatusPending, now, limit)
+}
+
+// SaveDeliveryWithContext schedules the delivery of an event to a subscription,
+// once: it returns 0 when the event was already scheduled for the subscription.
+func (r *repository) SaveDeliveryWithContext(ctx context.Context, d domain.WebhookDelivery) (int, error) {
+	query := "INSERT IGNORE INTO webhook_deliveries (subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?);"
+	return r.insertDelivery(ctx, query, d.SubscriptionID, d.EventID, d.EventType, string(d.Payload), d.Status, d.Attempts, d.NextAttemptAt, d.CreatedAt)
+}
+
+// SaveRedeliveryWithContext schedules another delivery of an event already
+// delivered to a subscription, numbered after the previous ones.
+func (r *repository) SaveRedeliveryWithContext(ctx context.Context, d domain.WebhookDelivery) (int, error) {
+	query := "INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at, created_at, redelivery) " +
+		"SELECT ?, ?, ?, ?, ?, ?, ?, ?, COALESCE(MAX(redelivery), 0) + 1 FROM webhook_deliveries WHERE subscription_id=? and event_id=?;"
+	return r.insertDelivery(ctx, query, d.SubscriptionID, d.EventID, d.EventType, string(d.Payload), d.Status, d.Attempts, d.NextAttemptAt, d.CreatedAt, d.SubscriptionID, d.EventID)
+}
+
+func (r *repository) insertDelivery(ctx context.Context, query string, args ...any) (int, error) {
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+// LockDeliveriesWithContext postpones the next attempt of the deliveries until
+// the given time, while a worker sends them outside of any transaction.
+func (r *repository) LockDeliveriesWithContext(ctx context.Context, ids []int, until time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	args := []any{until}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	query := "UPDATE webhook_deliveries SET next_attempt_at=? WHERE delivery_id IN (" + strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ") + ");"
+	_, err := database.Conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *repository) UpdateDeliveryWithContext(ctx context.Context, d domain.WebhookDelivery) error {
+	query := "UPDATE webhook_deliveries SET status=?, attempts=?, response_status=?, last_error=?, next_attempt_at=?, delivered_at=? WHERE delivery_id=?;"
+	_, err := database.Conn(ctx, r.db).ExecContext(ctx, query, d.Status, d.Attempts, d.ResponseStatus, d.LastError, d.NextAttemptAt, d.DeliveredAt, d.ID)
+	return err
+}
+
+func (r *repository) querySubscriptions(ctx context.Context, query string, args ...any) ([]domain.WebhookSubscription, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []domain.WebhookSubscription
+
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, s)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+func (r *repository) queryDeliveries(ctx context.Context, query string, args ...any) ([]domain.WebhookDelivery, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []domain.WebhookDelivery
+
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSubscription(row scanner) (domain.WebhookSubscription, error) {
+	var eventTypes string
+	s := domain.WebhookSubscription{}
+	if err := row.Scan(&s.ID, &s.URL, &eventTypes, &s.Secret, &s.Active, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+	s.EventTypes = strings.Split(eventTypes, ",")
+	return s, nil
+}
+
+func scanDelivery(row scanner) (domain.WebhookDelivery, error) {
+	var payload []byte
+	d := domain.WebhookDelivery{}
+	err := row.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &payload, &d.Status, &d.Attempts,
+		&d.ResponseStatus, &d.LastError, &d.NextAttemptAt, &d.DeliveredAt, &d.CreatedAt)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	d.Payload = payload
+	return d, nil
+}
@@ -0,0 +1,130 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuite_WebhookRepository(t *testing.T) {
+	db, err := testutil.InitTxdbDatabase(t)
+	assert.NoError(t, err)
+	repository := NewRepository(db)
+
+	testSaveAndGetWithContext(t, repository)
+	testSaveDeliveryOnceWithContext(t, repository)
+	testDueDeliveriesWithContext(t, repository)
+
+	db.Close()
+}
+
+func saveSubscription(ctx context.Context, t *testing.T, repository Repository, eventTypes ...string) int {
+	t.Helper()
+	id, err := repository.SaveWithContext(ctx, domain.WebhookSubscription{
+		URL:        "https://203.0.113.10/hooks",
+		EventTypes: eventTypes,
+		Secret:     secret,
+		Active:     true,
+		CreatedAt:  time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	assert.NoError(t, err)
+	return id
+}
+
+func testSaveAndGetWithContext(t *testing.T, repository Repository) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	id := saveSubscription(ctx, t, repository, domain.EventCustomerCreated, domain.EventCustomerDeleted)
+
+	result, err := repository.GetWithContext(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{domain.EventCustomerCreated, domain.EventCustomerDeleted}, result.EventTypes)
+	assert.Equal(t, secret, result.Secret)
+
+	active, err := repository.GetActiveByEventTypeWithContext(ctx, domain.EventCustomerDeleted)
+	assert.NoError(t, err)
+	assert.Contains(t, active, result)
+
+	active, err = repository.GetActiveByEventTypeWithContext(ctx, domain.EventCustomerUpdated)
+	assert.NoError(t, err)
+	assert.NotContains(t, active, result)
+
+	assert.NoError(t, repository.DeleteWithContext(ctx, id))
+	_, err = repository.GetWithContext(ctx, id)
+	assert.Equal(t, ErrorWebhookNotFound, err)
+	assert.Equal(t, ErrorWebhookNotFound, repository.DeleteWithContext(ctx, id))
+}
+
+func testSaveDeliveryOnceWithContext(t *testing.T, repository Repository) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	subscriptionID := saveSubscription(ctx, t, repository, domain.EventCustomerCreated)
+	d := newDelivery(subscriptionID, "00000000-0000-0000-0000-000000000001", domain.EventCustomerCreated, json.RawMessage(`{"id":1}`))
+
+	id, err := repository.SaveDeliveryWithContext(ctx, d)
+	assert.NoError(t, err)
+	assert.NotZero(t, id)
+
+	again, err := repository.SaveDeliveryWithContext(ctx, d)
+	assert.NoError(t, err)
+	assert.Zero(t, again)
+
+	redelivery, err := repository.SaveRedeliveryWithContext(ctx, d)
+	assert.NoError(t, err)
+	assert.NotZero(t, redelivery)
+
+	deliveries, err := repository.GetDeliveriesWithContext(ctx, subscriptionID)
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 2)
+
+	_, err = repository.GetDeliveryWithContext(ctx, subscriptionID+1, id)
+	assert.Equal(t, ErrorDeliveryNotFound, err)
+}
+
+func testDueDeliveriesWithContext(t *testing.T, repository Repository) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	subscriptionID := saveSubscription(ctx, t, repository, domain.EventCustomerUpdated)
+	d := newDelivery(subscriptionID, "00000000-0000-0000-0000-000000000002", domain.EventCustomerUpdated, json.RawMessage(`{"id":2}`))
+	id, err := repository.SaveDeliveryWithContext(ctx, d)
+	assert.NoError(t, err)
+
+	isDue := func() bool {
+		due, err := repository.GetDueDeliveriesWithContext(ctx, d.NextAttemptAt.Add(time.Second), 100)
+		assert.NoError(t, err)
+		for _, delivery := range due {
+			if delivery.ID == id {
+				return true
+			}
+		}
+		return false
+	}
+	assert.True(t, isDue())
+
+	assert.NoError(t, repository.LockDeliveriesWithContext(ctx, []int{id}, d.NextAttemptAt.Add(time.Minute)))
+	assert.False(t, isDue())
+
+	delivered := d.NextAttemptAt.Add(time.Second)
+	status := 204
+	d.ID = id
+	d.Status = domain.DeliveryStatusSucceeded
+	d.Attempts = 1
+	d.ResponseStatus = &status
+	d.NextAttemptAt = nil
+	d.DeliveredAt = &delivered
+	assert.NoError(t, repository.UpdateDeliveryWithContext(ctx, d))
+
+	result, err := repository.GetDeliveryWithContext(ctx, subscriptionID, id)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.DeliveryStatusSucceeded, result.Status)
+	assert.Equal(t, &status, result.ResponseStatus)
+}
@@ -0,0 +1,137 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+)
+
+var (
+	ErrorWebhookNotFound  = errors.New("webhook subscription not found")
+	ErrorDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrorURLNotAllowed    = errors.New("webhook url must resolve to public addresses only")
+)
+
+type Service interface {
+	Save(ctx context.Context, input dto.WebhookRequest) (domain.WebhookSubscription, error)
+	GetAll(ctx context.Context) ([]domain.WebhookSubscription, error)
+	Get(ctx context.Context, id int) (domain.WebhookSubscription, error)
+	Update(ctx context.Context, input dto.WebhookRequest, id int) (domain.WebhookSubscription, error)
+	Delete(ctx context.Context, id int) error
+	GetDeliveries(ctx context.Context, id int) ([]domain.WebhookDelivery, error)
+	Redeliver(ctx context.Context, id, deliveryID int) (domain.WebhookDelivery, error)
+}
+
+type service struct {
+	repository Repository
+}
+
+func NewService(r Repository) Service {
+	return &service{
+		repository: r,
+	}
+}
+
+func (s *service) Save(ctx context.Context, input dto.WebhookRequest) (domain.WebhookSubscription, error) {
+	if err := checkURL(ctx, input.URL); err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+
+	sub := domain.WebhookSubscription{
+		URL:        input.URL,
+		EventTypes: input.EventTypes,
+		Secret:     input.Secret,
+		Active:     input.Active == nil || *input.Active,
+		CreatedAt:  time.Now().Truncate(time.Second),
+	}
+
+	id, err := s.repository.SaveWithContext(ctx, sub)
+	if err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+
+	return s.repository.GetWithContext(ctx, id)
+}
+
+func (s *service) GetAll(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	return s.repository.GetAllWithContext(ctx)
+}
+
+func (s *service) Get(ctx context.Context, id int) (domain.WebhookSubscription, error) {
+	return s.repository.GetWithContext(ctx, id)
+}
+
+func (s *service) Update(ctx context.Context, input dto.WebhookRequest, id int) (domain.WebhookSubscription, error) {
+	sub, err := s.repository.GetWithContext(ctx, id)
+	if err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+
+	if err := checkURL(ctx, input.URL); err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+
+	now := time.Now().Truncate(time.Second)
+	sub.URL = input.URL
+	sub.EventTypes = input.EventTypes
+	sub.Secret = input.Secret
+	sub.UpdatedAt = &now
+	if input.Active != nil {
+		sub.Active = *input.Active
+	}
+
+	if err := s.repository.UpdateWithContext(ctx, sub); err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+
+	return s.repository.GetWithContext(ctx, id)
+}
+
+func (s *service) Delete(ctx context.Context, id int) error {
+	return s.repository.DeleteWithContext(ctx, id)
+}
+
+func (s *service) GetDeliveries(ctx context.Context, id int) ([]domain.WebhookDelivery, error) {
+	if _, err := s.repository.GetWithContext(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetDeliveriesWithContext(ctx, id)
+}
+
+// Redeliver schedules a new delivery of the same event, leaving the original
+// delivery and its outcome untouched in the log.
+func (s *service) Redeliver(ctx context.Context, id, deliveryID int) (domain.WebhookDelivery, error) {
+	if _, err := s.repository.GetWithContext(ctx, id); err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+
+	original, err := s.repository.GetDeliveryWithContext(ctx, id, deliveryID)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+
+	delivery := newDelivery(id, original.EventID, original.EventType, original.Payload)
+	newID, err := s.repository.SaveRedeliveryWithContext(ctx, delivery)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+
+	return s.repository.GetDeliveryWithContext(ctx, id, newID)
+}
+
+func newDelivery(subscriptionID int, eventID, eventType string, payload []byte) domain.WebhookDelivery {
+	now := time.Now().Truncate(time.Second)
+	return domain.WebhookDelivery{
+		SubscriptionID: subscriptionID,
+		EventID:        eventID,
+		EventType:      eventType,
+		Payload:        payload,
+		Status:         domain.DeliveryStatusPending,
+		NextAttemptAt:  &now,
+		CreatedAt:      now,
+	}
+}
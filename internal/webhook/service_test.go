@@ -0,0 +1,159 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	mocks "github.com/danilosano/web-golang-api/pkg/tests/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func createService(t *testing.T) (Service, *mocks.WebhookRepositoryMock, context.Context) {
+	t.Helper()
+	repoMock := new(mocks.WebhookRepositoryMock)
+	return NewService(repoMock), repoMock, context.Background()
+}
+
+var (
+	input = dto.WebhookRequest{
+		URL:        "https://203.0.113.10/hooks",
+		EventTypes: []string{domain.EventCustomerCreated},
+		Secret:     secret,
+	}
+
+	mockedSubscription = domain.WebhookSubscription{
+		ID:         1,
+		URL:        "https://203.0.113.10/hooks",
+		EventTypes: []string{domain.EventCustomerCreated},
+		Secret:     secret,
+		Active:     true,
+		CreatedAt:  now,
+	}
+
+	privateURLs = []string{
+		"http://10.0.0.5/hooks",
+		"http://127.0.0.1:8080/hooks",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hooks",
+		"http://0.0.0.0/hooks",
+		"http://0.1.2.3/hooks",
+		"http://100.64.0.1/hooks",
+		"http://192.0.0.8/hooks",
+		"http://198.18.0.1/hooks",
+		"http://[::ffff:10.0.0.5]/hooks",
+		"http://[64:ff9b::a00:5]/hooks",
+		"http://[fd00::1]/hooks",
+	}
+)
+
+func TestSave(t *testing.T) {
+	t.Run("A subscription to a public address is active unless stated otherwise.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("SaveWithContext", ctx, mock.MatchedBy(func(s domain.WebhookSubscription) bool {
+			return s.URL == input.URL && s.Active && !s.CreatedAt.IsZero()
+		})).Return(1, nil)
+		repoMock.On("GetWithContext", ctx, 1).Return(mockedSubscription, nil)
+
+		result, err := service.Save(ctx, input)
+		assert.Nil(t, err)
+		assert.Equal(t, mockedSubscription, result)
+	})
+
+	t.Run("A URL resolving to a private, loopback or link-local address is refused.", func(t *testing.T) {
+		for _, url := range privateURLs {
+			service, repoMock, ctx := createService(t)
+			private := input
+			private.URL = url
+
+			_, err := service.Save(ctx, private)
+			assert.Equal(t, ErrorURLNotAllowed, err, url)
+			repoMock.AssertNotCalled(t, "SaveWithContext", mock.Anything, mock.Anything)
+		}
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	t.Run("The subscription keeps its state when active is omitted.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		inactive := mockedSubscription
+		inactive.Active = false
+		repoMock.On("GetWithContext", ctx, 1).Return(inactive, nil)
+		repoMock.On("UpdateWithContext", ctx, mock.MatchedBy(func(s domain.WebhookSubscription) bool {
+			return s.ID == 1 && !s.Active && s.UpdatedAt != nil
+		})).Return(nil)
+
+		_, err := service.Update(ctx, input, 1)
+		assert.Nil(t, err)
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("A URL resolving to a private address is refused.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("GetWithContext", ctx, 1).Return(mockedSubscription, nil)
+		private := input
+		private.URL = privateURLs[0]
+
+		_, err := service.Update(ctx, private, 1)
+		assert.Equal(t, ErrorURLNotAllowed, err)
+		repoMock.AssertNotCalled(t, "UpdateWithContext", mock.Anything, mock.Anything)
+	})
+
+	t.Run("When the subscription does not exist, return the not found error.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("GetWithContext", ctx, 9).Return(nil, ErrorWebhookNotFound)
+
+		_, err := service.Update(ctx, input, 9)
+		assert.Equal(t, ErrorWebhookNotFound, err)
+	})
+}
+
+func TestGetDeliveries(t *testing.T) {
+	t.Run("When the subscription does not exist, return the not found error.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("GetWithContext", ctx, 9).Return(nil, ErrorWebhookNotFound)
+
+		_, err := service.GetDeliveries(ctx, 9)
+		assert.Equal(t, ErrorWebhookNotFound, err)
+		repoMock.AssertNotCalled(t, "GetDeliveriesWithContext", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRedeliver(t *testing.T) {
+	original := domain.WebhookDelivery{
+		ID:             7,
+		SubscriptionID: 1,
+		EventID:        "e1",
+		EventType:      domain.EventCustomerCreated,
+		Payload:        json.RawMessage(`{"id":"e1"}`),
+		Status:         domain.DeliveryStatusFailed,
+		Attempts:       defaultMaxAttempts,
+	}
+
+	t.Run("The event is scheduled again as a new pending delivery.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("GetWithContext", ctx, 1).Return(mockedSubscription, nil)
+		repoMock.On("GetDeliveryWithContext", ctx, 1, 7).Return(original, nil)
+		repoMock.On("SaveRedeliveryWithContext", ctx, mock.MatchedBy(func(d domain.WebhookDelivery) bool {
+			return d.EventID == "e1" && d.Status == domain.DeliveryStatusPending && d.Attempts == 0 && d.NextAttemptAt != nil
+		})).Return(8, nil)
+		repoMock.On("GetDeliveryWithContext", ctx, 1, 8).Return(domain.WebhookDelivery{ID: 8}, nil)
+
+		result, err := service.Redeliver(ctx, 1, 7)
+		assert.Nil(t, err)
+		assert.Equal(t, 8, result.ID)
+		repoMock.AssertNotCalled(t, "SaveDeliveryWithContext", mock.Anything, mock.Anything)
+	})
+
+	t.Run("When the delivery does not belong to the subscription, return the not found error.", func(t *testing.T) {
+		service, repoMock, ctx := createService(t)
+		repoMock.On("GetWithContext", ctx, 2).Return(mockedSubscription, nil)
+		repoMock.On("GetDeliveryWithContext", ctx, 2, 7).Return(nil, ErrorDeliveryNotFound)
+
+		_, err := service.Redeliver(ctx, 2, 7)
+		assert.Equal(t, ErrorDeliveryNotFound, err)
+	})
+}
@@ -0,0 +1,51 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HeaderSignature carries the signature of a delivery, formatted as
+// "t=<unix timestamp>,v1=<hex HMAC-SHA256 of "<timestamp>.<body>">".
+const HeaderSignature = "X-Webhook-Signature"
+
+// Sign returns the signature header value of body sent at the given time.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", ts, digest(secret, ts, body))
+}
+
+// Verify checks a signature header value against body, rejecting signatures
+// older than tolerance. Receivers can use it to authenticate deliveries.
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) bool {
+	var ts, sig string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			sig = value
+		}
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || now.Sub(time.Unix(unix, 0)) > tolerance {
+		return false
+	}
+
+	return hmac.Equal([]byte(sig), []byte(digest(secret, ts, body)))
+}
+
+func digest(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,191 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/pkg/database"
+)
+
+const (
+	defaultMaxAttempts = 8
+	defaultBaseBackoff = 30 * time.Second
+	defaultMaxBackoff  = 6 * time.Hour
+	defaultLease       = 5 * time.Minute
+)
+
+// Worker sends the pending deliveries, retrying failed ones with exponential
+// backoff until MaxAttempts is reached. Deliveries are sent outside of any
+// transaction, postponed by Lease so that other workers leave them alone.
+type Worker struct {
+	repository  Repository
+	transactor  database.Transactor
+	client      *http.Client
+	interval    time.Duration
+	batchSize   int
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	Lease       time.Duration
+	now         func() time.Time
+}
+
+func NewWorker(r Repository, t database.Transactor, client *http.Client, interval time.Duration, batchSize int) *Worker {
+	if client == nil {
+		client = newClient()
+	}
+	return &Worker{
+		repository:  r,
+		transactor:  t,
+		client:      client,
+		interval:    interval,
+		batchSize:   batchSize,
+		MaxAttempts: defaultMaxAttempts,
+		BaseBackoff: defaultBaseBackoff,
+		MaxBackoff:  defaultMaxBackoff,
+		Lease:       defaultLease,
+		now:         time.Now,
+	}
+}
+
+// Run sends the due deliveries every interval until ctx is cancelled.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.DeliverDue(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("error sending webhook deliveries: %s\n", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue attempts one batch of due deliveries and returns how many succeeded.
+func (w *Worker) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := w.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	succeeded := 0
+	for _, d := range deliveries {
+		d = w.attempt(ctx, d)
+		if d.Status == domain.DeliveryStatusSucceeded {
+			succeeded++
+		}
+		if err := w.repository.UpdateDeliveryWithContext(ctx, d); err != nil {
+			return succeeded, err
+		}
+	}
+	return succeeded, nil
+}
+
+// claim postpones the next attempt of one batch of due deliveries by Lease and
+// returns them, so that the worker can send them without holding row locks.
+func (w *Worker) claim(ctx context.Context) ([]domain.WebhookDelivery, error) {
+	var claimed []domain.WebhookDelivery
+	err := w.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		now := w.now()
+		deliveries, err := w.repository.GetDueDeliveriesWithContext(ctx, now, w.batchSize)
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		ids := make([]int, len(deliveries))
+		for i, d := range deliveries {
+			ids[i] = d.ID
+		}
+		if err := w.repository.LockDeliveriesWithContext(ctx, ids, now.Add(w.Lease)); err != nil {
+			return err
+		}
+		claimed = deliveries
+		return nil
+	})
+	return claimed, err
+}
+
+// attempt sends d once and returns it updated with the outcome.
+func (w *Worker) attempt(ctx context.Context, d domain.WebhookDelivery) domain.WebhookDelivery {
+	d.Attempts++
+	now := w.now()
+
+	sub, err := w.repository.GetWithContext(ctx, d.SubscriptionID)
+	if err != nil {
+		return w.fail(d, now, nil, fmt.Errorf("subscription unavailable: %w", err), true)
+	}
+
+	status, err := w.send(ctx, sub, d, now)
+	if err != nil {
+		return w.fail(d, now, status, err, d.Attempts >= w.MaxAttempts)
+	}
+
+	d.Status = domain.DeliveryStatusSucceeded
+	d.ResponseStatus = status
+	d.LastError = nil
+	d.NextAttemptAt = nil
+	d.DeliveredAt = &now
+	return d
+}
+
+func (w *Worker) send(ctx context.Context, sub domain.WebhookSubscription, d domain.WebhookDelivery, now time.Time) (*int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", d.EventType)
+	req.Header.Set("X-Webhook-Delivery", strconv.Itoa(d.ID))
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, now, d.Payload))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &resp.StatusCode, fmt.Errorf("receiver answered %d", resp.StatusCode)
+	}
+	return &resp.StatusCode, nil
+}
+
+// fail records a failed attempt, scheduling a retry unless final is set.
+func (w *Worker) fail(d domain.WebhookDelivery, now time.Time, status *int, cause error, final bool) domain.WebhookDelivery {
+	msg := cause.Error()
+	d.ResponseStatus = status
+	d.LastError = &msg
+
+	if final {
+		d.Status = domain.DeliveryStatusFailed
+		d.NextAttemptAt = nil
+		return d
+	}
+
+	next := now.Add(w.backoff(d.Attempts))
+	d.NextAttemptAt = &next
+	return d
+}
+
+// backoff doubles the delay after every failed attempt, up to MaxBackoff.
+func (w *Worker) backoff(attempts int) time.Duration {
+	delay := w.BaseBackoff
+	for i := 1; i < attempts && delay < w.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > w.MaxBackoff {
+		delay = w.MaxBackoff
+	}
+	return delay
+}
@@ -0,0 +1,157 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/pkg/database"
+	mocks "github.com/danilosano/web-golang-api/pkg/tests/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const secret = "0123456789abcdef"
+
+var now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func createWorker(t *testing.T, receiver *httptest.Server) (*Worker, *mocks.WebhookRepositoryMock, context.Context) {
+	t.Helper()
+	repoMock := new(mocks.WebhookRepositoryMock)
+	worker := NewWorker(repoMock, database.NoopTransactor(), receiver.Client(), time.Second, 10)
+	worker.now = func() time.Time { return now }
+	repoMock.On("GetWithContext", mock.Anything, 1).Return(domain.WebhookSubscription{ID: 1, URL: receiver.URL, Secret: secret, Active: true}, nil)
+	repoMock.On("LockDeliveriesWithContext", mock.Anything, []int{7}, now.Add(defaultLease)).Return(nil)
+	return worker, repoMock, context.Background()
+}
+
+func pendingDelivery(attempts int) domain.WebhookDelivery {
+	return domain.WebhookDelivery{
+		ID:             7,
+		SubscriptionID: 1,
+		EventID:        "e1",
+		EventType:      domain.EventCustomerCreated,
+		Payload:        json.RawMessage(`{"id":"e1"}`),
+		Status:         domain.DeliveryStatusPending,
+		Attempts:       attempts,
+	}
+}
+
+func TestWorker(t *testing.T) {
+	t.Run("A delivery is signed with the subscription secret and marked as succeeded.", func(t *testing.T) {
+		var verified bool
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			verified = Verify(secret, r.Header.Get(HeaderSignature), body, time.Minute, now) &&
+				r.Header.Get("X-Webhook-Event") == domain.EventCustomerCreated
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer receiver.Close()
+
+		worker, repoMock, ctx := createWorker(t, receiver)
+		repoMock.On("GetDueDeliveriesWithContext", ctx, now, 10).Return([]domain.WebhookDelivery{pendingDelivery(0)}, nil)
+		repoMock.On("UpdateDeliveryWithContext", ctx, mock.MatchedBy(func(d domain.WebhookDelivery) bool {
+			return d.Status == domain.DeliveryStatusSucceeded && d.Attempts == 1 &&
+				*d.ResponseStatus == http.StatusNoContent && d.DeliveredAt.Equal(now) && d.NextAttemptAt == nil
+		})).Return(nil)
+
+		n, err := worker.DeliverDue(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.True(t, verified)
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("A failed delivery is retried later with exponential backoff.", func(t *testing.T) {
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer receiver.Close()
+
+		worker, repoMock, ctx := createWorker(t, receiver)
+		repoMock.On("GetDueDeliveriesWithContext", ctx, now, 10).Return([]domain.WebhookDelivery{pendingDelivery(2)}, nil)
+		repoMock.On("UpdateDeliveryWithContext", ctx, mock.MatchedBy(func(d domain.WebhookDelivery) bool {
+			return d.Status == domain.DeliveryStatusPending && d.Attempts == 3 &&
+				*d.ResponseStatus == http.StatusInternalServerError &&
+				d.NextAttemptAt.Equal(now.Add(4*defaultBaseBackoff))
+		})).Return(nil)
+
+		n, err := worker.DeliverDue(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("After the last attempt the delivery is marked as failed.", func(t *testing.T) {
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusGone)
+		}))
+		defer receiver.Close()
+
+		worker, repoMock, ctx := createWorker(t, receiver)
+		repoMock.On("GetDueDeliveriesWithContext", ctx, now, 10).Return([]domain.WebhookDelivery{pendingDelivery(defaultMaxAttempts - 1)}, nil)
+		repoMock.On("UpdateDeliveryWithContext", ctx, mock.MatchedBy(func(d domain.WebhookDelivery) bool {
+			return d.Status == domain.DeliveryStatusFailed && d.NextAttemptAt == nil && *d.LastError == "receiver answered 410"
+		})).Return(nil)
+
+		_, err := worker.DeliverDue(ctx)
+		assert.NoError(t, err)
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("The default client refuses to connect to a private address.", func(t *testing.T) {
+		called := false
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		defer receiver.Close()
+
+		worker, repoMock, ctx := createWorker(t, receiver)
+		worker.client = newClient()
+		repoMock.On("GetDueDeliveriesWithContext", ctx, now, 10).Return([]domain.WebhookDelivery{pendingDelivery(0)}, nil)
+		repoMock.On("UpdateDeliveryWithContext", ctx, mock.MatchedBy(func(d domain.WebhookDelivery) bool {
+			return d.Status == domain.DeliveryStatusPending && d.Attempts == 1 && strings.Contains(*d.LastError, ErrorURLNotAllowed.Error())
+		})).Return(nil)
+
+		n, err := worker.DeliverDue(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+		assert.False(t, called)
+		repoMock.AssertExpectations(t)
+	})
+
+	t.Run("When nothing is due, nothing is locked.", func(t *testing.T) {
+		repoMock := new(mocks.WebhookRepositoryMock)
+		worker := NewWorker(repoMock, database.NoopTransactor(), nil, time.Second, 10)
+		repoMock.On("GetDueDeliveriesWithContext", mock.Anything, mock.Anything, 10).Return([]domain.WebhookDelivery{}, nil)
+
+		n, err := worker.DeliverDue(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+		repoMock.AssertExpectations(t)
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	worker := NewWorker(nil, nil, nil, time.Second, 1)
+
+	assert.Equal(t, defaultBaseBackoff, worker.backoff(1))
+	assert.Equal(t, 2*defaultBaseBackoff, worker.backoff(2))
+	assert.Equal(t, defaultMaxBackoff, worker.backoff(50))
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"id":"e1"}`)
+	header := Sign(secret, now, body)
+
+	assert.True(t, Verify(secret, header, body, time.Minute, now.Add(time.Second)))
+	assert.False(t, Verify("another secret", header, body, time.Minute, now))
+	assert.False(t, Verify(secret, header, []byte(`{"id":"e2"}`), time.Minute, now))
+	assert.False(t, Verify(secret, header, body, time.Minute, now.Add(time.Hour)))
+}
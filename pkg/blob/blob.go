@@ -0,0 +1,27 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var (
+	ErrorBlobNotFound     = errors.New("blob not found")
+	ErrorChecksumMismatch = errors.New("blob checksum mismatch")
+	ErrorInvalidKey       = errors.New("invalid blob key")
+)
+
+// Storage keeps binary content by key. Keys are slash-separated paths such as
+// "customers/1/contract.pdf".
+type Storage interface {
+	// Put stores the size bytes of r under key. checksum is the hex-encoded
+	// SHA-256 of the content, which the storage verifies before keeping it,
+	// failing with ErrorChecksumMismatch.
+	Put(ctx context.Context, key string, r io.Reader, size int64, checksum string) error
+	// Get opens the content stored under key, failing with ErrorBlobNotFound.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the content stored under key. Deleting a missing key is not
+	// an error.
+	Delete(ctx context.Context, key string) error
+}
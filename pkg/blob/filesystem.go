@@ -0,0 +1,91 @@
+package blob
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileSystem stores the blobs as files under a root directory, a key being the
+// path of its file relative to the root.
+type FileSystem struct {
+	root string
+}
+
+func NewFileSystem(root string) *FileSystem {
+	return &FileSystem{root: root}
+}
+
+// Put writes the content to a temporary file renamed to its key once its
+// checksum is verified, so that a blob is never seen partially written.
+func (f *FileSystem) Put(ctx context.Context, key string, r io.Reader, size int64, checksum string) error {
+	path, err := f.path(key)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(tmp, h), r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if n != size || !strings.EqualFold(hex.EncodeToString(h.Sum(nil)), checksum) {
+		return ErrorChecksumMismatch
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (f *FileSystem) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := f.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrorBlobNotFound
+	}
+	return file, err
+}
+
+func (f *FileSystem) Delete(ctx context.Context, key string) error {
+	path, err := f.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path returns the file of key, rejecting the keys that would leave the root.
+func (f *FileSystem) path(key string) (string, error) {
+	if key == "" || !fs.ValidPath(key) {
+		return "", ErrorInvalidKey
+	}
+	return filepath.Join(f.root, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,64 @@
+package blob
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func checksumOf(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestFileSystem(t *testing.T) {
+	ctx := context.Background()
+	content := "signed contract"
+
+	t.Run("A stored blob is read back and deleted.", func(t *testing.T) {
+		storage := NewFileSystem(t.TempDir())
+
+		err := storage.Put(ctx, "customers/1/contract.pdf", strings.NewReader(content), int64(len(content)), checksumOf(content))
+		assert.NoError(t, err)
+
+		r, err := storage.Get(ctx, "customers/1/contract.pdf")
+		assert.NoError(t, err)
+		data, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.NoError(t, r.Close())
+		assert.Equal(t, content, string(data))
+
+		assert.NoError(t, storage.Delete(ctx, "customers/1/contract.pdf"))
+		_, err = storage.Get(ctx, "customers/1/contract.pdf")
+		assert.Equal(t, ErrorBlobNotFound, err)
+		assert.NoError(t, storage.Delete(ctx, "customers/1/contract.pdf"))
+	})
+
+	t.Run("Content that does not match its checksum is not kept.", func(t *testing.T) {
+		root := t.TempDir()
+		storage := NewFileSystem(root)
+
+		err := storage.Put(ctx, "customers/1/contract.pdf", strings.NewReader(content), int64(len(content)), checksumOf("another contract"))
+		assert.Equal(t, ErrorChecksumMismatch, err)
+
+		entries, err := os.ReadDir(filepath.Join(root, "customers", "1"))
+		assert.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("Keys leaving the root directory are rejected.", func(t *testing.T) {
+		storage := NewFileSystem(t.TempDir())
+
+		for _, key := range []string{"", "../secret", "/etc/passwd", "customers/../../secret"} {
+			_, err := storage.Get(ctx, key)
+			assert.Equal(t, ErrorInvalidKey, err, key)
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package blob
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// S3Config locates a bucket of an S3-compatible service.
+type S3Config struct {
+	// Endpoint is the base URL of the service, such as
+	// https://s3.us-east-1.amazonaws.com or http://localhost:9000.
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// S3 stores the blobs as objects of a bucket of Amazon S3 or any service
+// implementing its API, such as MinIO. Objects are addressed path-style, as
+// <endpoint>/<bucket>/<key>, and requests are signed with Signature Version 4.
+type S3 struct {
+	cfg    S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3 returns the storage of the bucket of cfg, sending its requests with
+// client or, when nil, http.DefaultClient.
+func NewS3(cfg S3Config, client *http.Client) *S3 {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	cfg.Endpoint = strings.TrimSuffix(cfg.Endpoint, "/")
+	return &S3{cfg: cfg, client: client, now: time.Now}
+}
+
+// Put uploads the content with its SHA-256 as the signed payload hash, which the
+// service checks against the content received.
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64, checksum string) error {
+	req, err := s.newRequest(ctx, http.MethodPut, key, r)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+
+	res, err := s.do(req, strings.ToLower(checksum))
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
+
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.do(req, emptyPayloadHash)
+	if errors.Is(err, ErrorBlobNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
+
+func (s *S3) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	if key == "" || !fs.ValidPath(key) {
+		return nil, ErrorInvalidKey
+	}
+
+	url := s.cfg.Endpoint + "/" + uriEncode(s.cfg.Bucket, false) + "/" + uriEncode(key, true)
+	return http.NewRequestWithContext(ctx, method, url, body)
+}
+
+// s3Error is the body of the error responses of S3.
+type s3Error struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// do signs and sends req, turning the error responses into errors.
+func (s *S3) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	signV4(req, payloadHash, s.cfg, s.now().UTC())
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode < http.StatusMultipleChoices {
+		return res, nil
+	}
+	defer res.Body.Close()
+
+	var e s3Error
+	_ = xml.NewDecoder(io.LimitReader(res.Body, 64<<10)).Decode(&e)
+
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return nil, ErrorBlobNotFound
+	case e.Code == "XAmzContentSHA256Mismatch" || e.Code == "BadDigest":
+		return nil, ErrorChecksumMismatch
+	case e.Code != "":
+		return nil, fmt.Errorf("s3: %s %s: %s: %s", req.Method, req.URL.Path, e.Code, e.Message)
+	default:
+		return nil, fmt.Errorf("s3: %s %s: %s", req.Method, req.URL.Path, res.Status)
+	}
+}
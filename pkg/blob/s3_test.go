@@ -0,0 +1,139 @@
+package blob
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeS3 is a stand-in for an S3-compatible service holding the objects of a
+// single bucket in memory. Like S3, it rejects requests whose signature or
+// payload hash do not match.
+type fakeS3 struct {
+	mu      sync.Mutex
+	cfg     S3Config
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !f.validSignature(r) {
+		writeS3Error(w, http.StatusForbidden, "SignatureDoesNotMatch")
+		return
+	}
+
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+f.cfg.Bucket+"/")
+	if !ok {
+		writeS3Error(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		sum := sha256.Sum256(body)
+		if hex.EncodeToString(sum[:]) != r.Header.Get("X-Amz-Content-Sha256") {
+			writeS3Error(w, http.StatusBadRequest, "XAmzContentSHA256Mismatch")
+			return
+		}
+		f.objects[key] = body
+	case http.MethodGet:
+		body, ok := f.objects[key]
+		if !ok {
+			writeS3Error(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		_, _ = w.Write(body)
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// validSignature signs a copy of the request as received and compares the
+// signatures.
+func (f *fakeS3) validSignature(r *http.Request) bool {
+	at, err := time.Parse(amzDateLayout, r.Header.Get("X-Amz-Date"))
+	if err != nil {
+		return false
+	}
+
+	signed, _ := http.NewRequest(r.Method, "http://"+r.Host+r.URL.RequestURI(), nil)
+	for name, values := range r.Header {
+		if name != "Authorization" && name != "User-Agent" && name != "Accept-Encoding" && name != "Content-Length" {
+			signed.Header[name] = values
+		}
+	}
+	signV4(signed, r.Header.Get("X-Amz-Content-Sha256"), f.cfg, at)
+	return signed.Header.Get("Authorization") == r.Header.Get("Authorization")
+}
+
+func writeS3Error(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+}
+
+func newS3(t *testing.T) (*S3, *fakeS3) {
+	t.Helper()
+	cfg := exampleConfig
+	fake := &fakeS3{cfg: cfg, objects: map[string][]byte{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	cfg.Endpoint = server.URL + "/"
+	return NewS3(cfg, server.Client()), fake
+}
+
+func TestS3(t *testing.T) {
+	ctx := context.Background()
+	content := "scan of the ID card"
+
+	t.Run("A stored object is read back and deleted.", func(t *testing.T) {
+		storage, fake := newS3(t)
+		key := "customers/1/id card.png"
+
+		err := storage.Put(ctx, key, strings.NewReader(content), int64(len(content)), checksumOf(content))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte(content), fake.objects[key])
+
+		r, err := storage.Get(ctx, key)
+		assert.NoError(t, err)
+		data, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.NoError(t, r.Close())
+		assert.Equal(t, content, string(data))
+
+		assert.NoError(t, storage.Delete(ctx, key))
+		_, err = storage.Get(ctx, key)
+		assert.Equal(t, ErrorBlobNotFound, err)
+	})
+
+	t.Run("Content that does not match its checksum is rejected by the service.", func(t *testing.T) {
+		storage, fake := newS3(t)
+
+		err := storage.Put(ctx, "customers/1/contract.pdf", strings.NewReader(content), int64(len(content)), checksumOf("another content"))
+		assert.Equal(t, ErrorChecksumMismatch, err)
+		assert.Empty(t, fake.objects)
+	})
+
+	t.Run("Requests signed with other credentials are refused.", func(t *testing.T) {
+		storage, _ := newS3(t)
+		storage.cfg.SecretAccessKey = "wrong"
+
+		_, err := storage.Get(ctx, "customers/1/contract.pdf")
+		assert.EqualError(t, err, "s3: GET /examplebucket/customers/1/contract.pdf: SignatureDoesNotMatch: SignatureDoesNotMatch")
+	})
+}
@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("When the cache is full, the least recently used entry is evicted.", func(t *testing.T) {
+		c := NewLRU(2)
+		c.Set(ctx, "a", []byte("1"), 0)
+		c.Set(ctx, "b", []byte("2"), 0)
+		_, _ = c.Get(ctx, "a")
+		c.Set(ctx, "c", []byte("3"), 0)
+
+		_, ok := c.Get(ctx, "b")
+		assert.False(t, ok)
+		value, ok := c.Get(ctx, "a")
+		assert.True(t, ok)
+		assert.Equal(t, []byte("1"), value)
+		assert.Equal(t, 2, c.Len())
+	})
+
+	t.Run("Entries expire after their TTL.", func(t *testing.T) {
+		now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		c := NewLRU(2)
+		c.now = func() time.Time { return now }
+		c.Set(ctx, "a", []byte("1"), time.Minute)
+
+		_, ok := c.Get(ctx, "a")
+		assert.True(t, ok)
+
+		now = now.Add(time.Minute)
+		_, ok = c.Get(ctx, "a")
+		assert.False(t, ok)
+		assert.Equal(t, 0, c.Len())
+	})
+
+	t.Run("Deleted entries are no longer returned.", func(t *testing.T) {
+		c := NewLRU(2)
+		c.Set(ctx, "a", []byte("1"), 0)
+		c.Delete(ctx, "a")
+
+		_, ok := c.Get(ctx, "a")
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Executor is implemented by both *sql.DB and *sql.Tx.
+type Executor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type (
+	txKey    struct{}
+	hooksKey struct{}
+)
+
+// Conn returns the transaction carried by ctx, or db when there is none, so that
+// repositories transparently join a transaction started by WithinTransaction.
+func Conn(ctx context.Context, db *sql.DB) Executor {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// InTransaction reports whether ctx carries a transaction.
+func InTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*sql.Tx)
+	return ok
+}
+
+// AfterCommit runs fn once the transaction carried by ctx commits, and never if it
+// rolls back. Without a transaction fn runs right away.
+func AfterCommit(ctx context.Context, fn func()) {
+	hooks, ok := ctx.Value(hooksKey{}).(*[]func())
+	if !ok {
+		fn()
+		return
+	}
+	*hooks = append(*hooks, fn)
+}
+
+// Transactor runs a function inside a transaction.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type transactor struct {
+	db *sql.DB
+}
+
+func NewTransactor(db *sql.DB) Transactor {
+	return &transactor{db: db}
+}
+
+// WithinTransaction commits when fn succeeds and rolls back otherwise, then runs
+// the functions registered with AfterCommit. Nested calls join the outer
+// transaction.
+func (t *transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if InTransaction(ctx) {
+		return fn(ctx)
+	}
+
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	hooks := &[]func(){}
+	txCtx := context.WithValue(context.WithValue(ctx, txKey{}, tx), hooksKey{}, hooks)
+	if err := fn(txCtx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	for _, hook := range *hooks {
+		hook()
+	}
+	return nil
+}
+
+type noopTransactor struct{}
+
+// NoopTransactor runs functions directly, without a transaction.
+func NoopTransactor() Transactor {
+	return noopTransactor{}
+}
+
+func (noopTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
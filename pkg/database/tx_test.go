@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAfterCommit(t *testing.T) {
+	transactor := NewTransactor(testutil.NopDB(t))
+
+	t.Run("The hooks run once the transaction commits, nested calls included.", func(t *testing.T) {
+		var calls []string
+		err := transactor.WithinTransaction(context.Background(), func(ctx context.Context) error {
+			AfterCommit(ctx, func() { calls = append(calls, "outer") })
+			_ = transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+				AfterCommit(ctx, func() { calls = append(calls, "nested") })
+				return nil
+			})
+			assert.Empty(t, calls)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"outer", "nested"}, calls)
+	})
+
+	t.Run("The hooks do not run when the transaction rolls back.", func(t *testing.T) {
+		called := false
+		err := transactor.WithinTransaction(context.Background(), func(ctx context.Context) error {
+			AfterCommit(ctx, func() { called = true })
+			return errors.New("generic error")
+		})
+
+		assert.Error(t, err)
+		assert.False(t, called)
+	})
+
+	t.Run("Without a transaction the hook runs right away.", func(t *testing.T) {
+		called := false
+		AfterCommit(context.Background(), func() { called = true })
+		assert.True(t, called)
+	})
+}
@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/zstd"
+)
+
+// encoder is implemented by the gzip, brotli and zstd writers.
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// encoders lists the supported content codings, the preferred one first, with a
+// pool of writers for each of them.
+var encoders = []struct {
+	name string
+	pool *sync.Pool
+}{
+	{"br", &sync.Pool{New: func() any { return brotli.NewWriterLevel(nil, brotli.DefaultCompression) }}},
+	{"zstd", &sync.Pool{New: func() any {
+		enc, _ := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault), zstd.WithEncoderConcurrency(1))
+		return enc
+	}}},
+	{"gzip", &sync.Pool{New: func() any { return gzip.NewWriter(nil) }}},
+}
+
+// Compress encodes the responses of at least minSize bytes with brotli, zstd or
+// gzip, the first one the client accepts through Accept-Encoding. Smaller
+// responses, responses already encoded, and event streams are written as they are.
+func Compress(minSize int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+
+		name, pool := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if pool == nil || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		w := &compressWriter{ResponseWriter: c.Writer, name: name, pool: pool, minSize: minSize}
+		c.Writer = w
+		defer w.close()
+		c.Next()
+	}
+}
+
+// negotiateEncoding returns the preferred encoding among those accepted with a
+// non-zero quality, or a nil pool when none is.
+func negotiateEncoding(header string) (string, *sync.Pool) {
+	accepted := map[string]bool{}
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		accepted[strings.ToLower(strings.TrimSpace(name))] = q > 0
+	}
+
+	for _, e := range encoders {
+		if ok, listed := accepted[e.name]; ok || (!listed && accepted["*"]) {
+			return e.name, e.pool
+		}
+	}
+	return "", nil
+}
+
+// compressWriter buffers the response until it reaches minSize bytes, then
+// compresses it, or writes it as it is when the handler finishes or flushes first.
+type compressWriter struct {
+	gin.ResponseWriter
+	name    string
+	pool    *sync.Pool
+	minSize int
+
+	buf     []byte
+	decided bool
+	enc     encoder
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if w.decided {
+		if w.enc != nil {
+			return w.enc.Write(b)
+		}
+		return w.ResponseWriter.Write(b)
+	}
+
+	w.buf = append(w.buf, b...)
+	if len(w.buf) >= w.minSize {
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *compressWriter) Written() bool {
+	return w.ResponseWriter.Written() || len(w.buf) > 0
+}
+
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		_ = w.decide(false)
+	}
+	if w.enc != nil {
+		_ = w.enc.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide starts compressing when asked and the response can be compressed, and
+// writes out what was buffered.
+func (w *compressWriter) decide(compress bool) error {
+	w.decided = true
+	buf := w.buf
+	w.buf = nil
+
+	header := w.Header()
+	if compress && header.Get("Content-Encoding") == "" && !strings.HasPrefix(header.Get("Content-Type"), "text/event-stream") {
+		header.Set("Content-Encoding", w.name)
+		header.Del("Content-Length")
+		w.enc = w.pool.Get().(encoder)
+		w.enc.Reset(w.ResponseWriter)
+		_, err := w.enc.Write(buf)
+		return err
+	}
+
+	if len(buf) == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(buf)
+	return err
+}
+
+func (w *compressWriter) close() {
+	if !w.decided {
+		_ = w.decide(false)
+	}
+	if w.enc != nil {
+		_ = w.enc.Close()
+		w.enc.Reset(io.Discard)
+		w.pool.Put(w.enc)
+		w.enc = nil
+	}
+}
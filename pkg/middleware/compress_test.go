@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompress(t *testing.T) {
+	large := strings.Repeat("customer;", 200)
+	server := testutil.CreateServer()
+	server.Use(Compress(1024))
+	server.GET("/large", func(c *gin.Context) { c.String(http.StatusOK, large) })
+	server.GET("/small", func(c *gin.Context) { c.String(http.StatusOK, "customer") })
+	server.GET("/chunks", func(c *gin.Context) {
+		for i := 0; i < 200; i++ {
+			_, _ = c.Writer.WriteString("customer;")
+		}
+	})
+	server.GET("/stream", func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		_, _ = c.Writer.WriteString(large)
+		c.Writer.Flush()
+	})
+
+	decoders := map[string]func(io.Reader) (io.Reader, error){
+		"gzip": func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		"br":   func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil },
+		"zstd": func(r io.Reader) (io.Reader, error) { return zstd.NewReader(r) },
+	}
+
+	for encoding, decode := range decoders {
+		t.Run("A large response is compressed with "+encoding+" when it is accepted.", func(t *testing.T) {
+			for _, path := range []string{"/large", "/chunks"} {
+				request, response := testutil.MakeRequest(http.MethodGet, path, "")
+				request.Header.Set("Accept-Encoding", encoding)
+				server.ServeHTTP(response, request)
+
+				assert.Equal(t, http.StatusOK, response.Code)
+				assert.Equal(t, encoding, response.Header().Get("Content-Encoding"))
+				assert.Contains(t, response.Header().Values("Vary"), "Accept-Encoding")
+				r, err := decode(response.Body)
+				require.NoError(t, err)
+				body, err := io.ReadAll(r)
+				require.NoError(t, err)
+				assert.Equal(t, large, string(body))
+			}
+		})
+	}
+
+	t.Run("Brotli is preferred, and encodings with a zero quality are never used.", func(t *testing.T) {
+		request, response := testutil.MakeRequest(http.MethodGet, "/large", "")
+		request.Header.Set("Accept-Encoding", "gzip, br;q=0, zstd;q=0.5")
+		server.ServeHTTP(response, request)
+		assert.Equal(t, "zstd", response.Header().Get("Content-Encoding"))
+
+		request, response = testutil.MakeRequest(http.MethodGet, "/large", "")
+		request.Header.Set("Accept-Encoding", "*")
+		server.ServeHTTP(response, request)
+		assert.Equal(t, "br", response.Header().Get("Content-Encoding"))
+	})
+
+	t.Run("A response under the threshold is written as it is.", func(t *testing.T) {
+		request, response := testutil.MakeRequest(http.MethodGet, "/small", "")
+		request.Header.Set("Accept-Encoding", "gzip")
+		server.ServeHTTP(response, request)
+
+		assert.Empty(t, response.Header().Get("Content-Encoding"))
+		assert.Equal(t, "customer", response.Body.String())
+	})
+
+	t.Run("Without Accept-Encoding, or for an event stream, nothing is compressed.", func(t *testing.T) {
+		request, response := testutil.MakeRequest(http.MethodGet, "/large", "")
+		server.ServeHTTP(response, request)
+		assert.Empty(t, response.Header().Get("Content-Encoding"))
+		assert.Equal(t, large, response.Body.String())
+
+		request, response = testutil.MakeRequest(http.MethodGet, "/stream", "")
+		request.Header.Set("Accept-Encoding", "gzip")
+		server.ServeHTTP(response, request)
+		assert.Empty(t, response.Header().Get("Content-Encoding"))
+		assert.Equal(t, large, response.Body.String())
+	})
+}
@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSConfig describes which cross-origin requests browsers may send.
+type CORSConfig struct {
+	// AllowedOrigins lists the origins, e.g. "https://admin.example.com", allowed
+	// to call the API. "*" allows any origin, and "https://*.example.com" any
+	// subdomain of example.com.
+	AllowedOrigins []string
+	// AllowedMethods lists the methods allowed in cross-origin requests.
+	AllowedMethods []string
+	// AllowedHeaders lists the request headers allowed in cross-origin requests,
+	// or "*" for any.
+	AllowedHeaders []string
+	// ExposedHeaders lists the response headers scripts may read.
+	ExposedHeaders []string
+	// AllowCredentials lets browsers send cookies and authorization headers. It
+	// never applies to the origins only allowed by "*", as any site could then
+	// make requests on behalf of the users.
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache the answer to a preflight request.
+	MaxAge time.Duration
+}
+
+// CORS answers the preflight requests and adds the CORS headers to the responses
+// of the allowed origins. Preflight requests from other origins, or asking for a
+// method or a header that is not allowed, are answered with a 403.
+func CORS(cfg CORSConfig) gin.HandlerFunc {
+	methods := strings.Join(cfg.AllowedMethods, ", ")
+	exposed := strings.Join(cfg.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		allowed, listed := cfg.allowsOrigin(origin)
+		if !allowed {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		credentials := cfg.AllowCredentials && listed
+		if credentials || !contains(cfg.AllowedOrigins, "*") {
+			c.Header("Access-Control-Allow-Origin", origin)
+		} else {
+			c.Header("Access-Control-Allow-Origin", "*")
+		}
+		if credentials {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			if exposed != "" {
+				c.Header("Access-Control-Expose-Headers", exposed)
+			}
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+		c.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+		requested := c.GetHeader("Access-Control-Request-Headers")
+		if !contains(cfg.AllowedMethods, c.GetHeader("Access-Control-Request-Method")) || !cfg.allowsHeaders(requested) {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+
+		c.Header("Access-Control-Allow-Methods", methods)
+		if requested != "" {
+			c.Header("Access-Control-Allow-Headers", requested)
+		}
+		if cfg.MaxAge > 0 {
+			c.Header("Access-Control-Max-Age", maxAge)
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+// allowsOrigin reports whether origin is allowed, and whether it is listed by
+// name or subdomain rather than only allowed by "*".
+func (cfg CORSConfig) allowsOrigin(origin string) (allowed, listed bool) {
+	for _, pattern := range cfg.AllowedOrigins {
+		if pattern == "*" {
+			allowed = true
+			continue
+		}
+		if strings.EqualFold(pattern, origin) {
+			return true, true
+		}
+		if scheme, domain, ok := strings.Cut(pattern, "://*."); ok {
+			prefix := scheme + "://"
+			if strings.HasPrefix(origin, prefix) && strings.HasSuffix(strings.ToLower(origin), "."+strings.ToLower(domain)) {
+				return true, true
+			}
+		}
+	}
+	return allowed, false
+}
+
+// allowsHeaders reports whether every header of the comma-separated list is allowed.
+func (cfg CORSConfig) allowsHeaders(requested string) bool {
+	if requested == "" || contains(cfg.AllowedHeaders, "*") {
+		return true
+	}
+	for _, h := range strings.Split(requested, ",") {
+		if !contains(cfg.AllowedHeaders, strings.TrimSpace(h)) {
+			return false
+		}
+	}
+	return true
+}
+
+// contains reports whether list holds s, ignoring case.
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORS(t *testing.T) {
+	server := testutil.CreateServer()
+	server.Use(CORS(CORSConfig{
+		AllowedOrigins:   []string{"https://admin.example.com", "https://*.partner.com"},
+		AllowedMethods:   []string{http.MethodGet, http.MethodPost},
+		AllowedHeaders:   []string{"Content-Type", HeaderRequestID},
+		ExposedHeaders:   []string{HeaderRequestID, "ETag"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}))
+	server.GET("/customers", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	preflight := func(origin, method, headers string) (*http.Request, *httptest.ResponseRecorder) {
+		request, response := testutil.MakeRequest(http.MethodOptions, "/customers", "")
+		request.Header.Set("Origin", origin)
+		request.Header.Set("Access-Control-Request-Method", method)
+		request.Header.Set("Access-Control-Request-Headers", headers)
+		return request, response
+	}
+
+	t.Run("A preflight request from an allowed origin is answered with a 204 code and the allowed methods.", func(t *testing.T) {
+		request, response := preflight("https://admin.example.com", http.MethodPost, "content-type, x-request-id")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNoContent, response.Code)
+		assert.Equal(t, "https://admin.example.com", response.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", response.Header().Get("Access-Control-Allow-Credentials"))
+		assert.Equal(t, "GET, POST", response.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "content-type, x-request-id", response.Header().Get("Access-Control-Allow-Headers"))
+		assert.Equal(t, "600", response.Header().Get("Access-Control-Max-Age"))
+	})
+
+	t.Run("A preflight request from another origin is answered with a 403 code.", func(t *testing.T) {
+		request, response := preflight("https://evil.com", http.MethodGet, "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusForbidden, response.Code)
+		assert.Empty(t, response.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("A preflight request asking for a method or a header that is not allowed is answered with a 403 code.", func(t *testing.T) {
+		request, response := preflight("https://admin.example.com", http.MethodDelete, "")
+		server.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+
+		request, response = preflight("https://admin.example.com", http.MethodGet, "X-Secret")
+		server.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("A request from an allowed origin gets the origin and the exposed headers.", func(t *testing.T) {
+		request, response := testutil.MakeRequest(http.MethodGet, "/customers", "")
+		request.Header.Set("Origin", "https://admin.example.com")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "https://admin.example.com", response.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "X-Request-ID, ETag", response.Header().Get("Access-Control-Expose-Headers"))
+		assert.Equal(t, "Origin", response.Header().Get("Vary"))
+	})
+
+	t.Run("A subdomain of a wildcard origin is allowed, but not the domain itself.", func(t *testing.T) {
+		request, response := testutil.MakeRequest(http.MethodGet, "/customers", "")
+		request.Header.Set("Origin", "https://app.partner.com")
+		server.ServeHTTP(response, request)
+		assert.Equal(t, "https://app.partner.com", response.Header().Get("Access-Control-Allow-Origin"))
+
+		request, response = testutil.MakeRequest(http.MethodGet, "/customers", "")
+		request.Header.Set("Origin", "https://partner.com")
+		server.ServeHTTP(response, request)
+		assert.Empty(t, response.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("A request without an Origin gets no CORS headers.", func(t *testing.T) {
+		request, response := testutil.MakeRequest(http.MethodGet, "/customers", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Empty(t, response.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, response.Header().Get("Vary"))
+	})
+}
+
+func TestCORSAnyOrigin(t *testing.T) {
+	server := testutil.CreateServer()
+	server.Use(CORS(CORSConfig{AllowedOrigins: []string{"*"}, AllowedMethods: []string{http.MethodGet}}))
+	server.GET("/customers", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	t.Run("Without credentials, any origin is allowed with a wildcard.", func(t *testing.T) {
+		request, response := testutil.MakeRequest(http.MethodGet, "/customers", "")
+		request.Header.Set("Origin", "https://anywhere.com")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, "*", response.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, response.Header().Get("Access-Control-Allow-Credentials"))
+	})
+}
+
+func TestCORSAnyOriginWithCredentials(t *testing.T) {
+	server := testutil.CreateServer()
+	server.Use(CORS(CORSConfig{
+		AllowedOrigins:   []string{"*", "https://admin.example.com"},
+		AllowedMethods:   []string{http.MethodGet},
+		AllowCredentials: true,
+	}))
+	server.GET("/customers", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	t.Run("An origin only allowed by the wildcard gets no credentials.", func(t *testing.T) {
+		request, response := testutil.MakeRequest(http.MethodGet, "/customers", "")
+		request.Header.Set("Origin", "https://evil.com")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, "*", response.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, response.Header().Get("Access-Control-Allow-Credentials"))
+	})
+
+	t.Run("A listed origin still gets the credentials.", func(t *testing.T) {
+		request, response := testutil.MakeRequest(http.MethodGet, "/customers", "")
+		request.Header.Set("Origin", "https://admin.example.com")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, "https://admin.example.com", response.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", response.Header().Get("Access-Control-Allow-Credentials"))
+	})
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecation announces that the routes it applies to are deprecated: the
+// Deprecation header (RFC 9745) tells since when, the Sunset header (RFC 8594)
+// when they will stop working, and the Link header points to their successor.
+// A zero sunset or an empty successor leaves the matching header out.
+func Deprecation(deprecatedAt, sunset time.Time, successor string) gin.HandlerFunc {
+	deprecation := fmt.Sprintf("@%d", deprecatedAt.Unix())
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		if !sunset.IsZero() {
+			c.Header("Sunset", sunset.UTC().Format(http.TimeFormat))
+		}
+		if successor != "" {
+			c.Header("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, successor))
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecation(t *testing.T) {
+	deprecatedAt := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC)
+
+	server := testutil.CreateServer()
+	server.GET("/v1", Deprecation(deprecatedAt, sunset, "/v2"), func(c *gin.Context) { c.Status(http.StatusOK) })
+	server.GET("/open", Deprecation(deprecatedAt, time.Time{}, ""), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	t.Run("A deprecated route announces its deprecation, its sunset and its successor.", func(t *testing.T) {
+		request, response := testutil.MakeRequest(http.MethodGet, "/v1", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "@1792368000", response.Header().Get("Deprecation"))
+		assert.Equal(t, "Fri, 30 Apr 2027 00:00:00 GMT", response.Header().Get("Sunset"))
+		assert.Equal(t, `</v2>; rel="successor-version"`, response.Header().Get("Link"))
+	})
+
+	t.Run("Without a sunset or a successor, only the Deprecation header is sent.", func(t *testing.T) {
+		request, response := testutil.MakeRequest(http.MethodGet, "/open", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, "@1792368000", response.Header().Get("Deprecation"))
+		assert.Empty(t, response.Header().Get("Sunset"))
+		assert.Empty(t, response.Header().Get("Link"))
+	})
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// ErrorHandler renders the last error attached with c.Error through web.Error,
+// using the status registered with web.RegisterError or 500 when none matches.
+// Validation failures are reported field by field with a 400, and any other
+// unregistered error attached with gin.ErrorTypeBind (e.g. malformed JSON) with
+// a 422.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		last := c.Errors.Last()
+		err := last.Err
+
+		var verrs validator.ValidationErrors
+		if errors.As(err, &verrs) {
+			web.ValidationError(c, verrs)
+			return
+		}
+
+		mapping, ok := web.LookupError(err)
+		if ok {
+			web.ErrorWithCode(c, mapping.Status, mapping.Code, "%s", err.Error())
+			return
+		}
+
+		if last.IsType(gin.ErrorTypeBind) {
+			web.Error(c, http.StatusUnprocessableEntity, "%s", err.Error())
+			return
+		}
+
+		web.Error(c, http.StatusInternalServerError, "%s", err.Error())
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var errTeapot = errors.New("i am a teapot")
+
+func TestErrorHandler(t *testing.T) {
+	web.RegisterErrorWithCode(errTeapot, http.StatusTeapot, "teapot")
+
+	server := testutil.CreateServer()
+	server.Use(ErrorHandler())
+	server.GET("/registered", func(c *gin.Context) {
+		_ = c.Error(fmt.Errorf("brewing: %w", errTeapot))
+	})
+	server.GET("/unregistered", func(c *gin.Context) {
+		_ = c.Error(errors.New("generic error"))
+	})
+	server.GET("/written", func(c *gin.Context) {
+		_ = c.Error(errTeapot)
+		c.Status(http.StatusAccepted)
+		c.Writer.WriteHeaderNow()
+	})
+
+	t.Run("A registered error, even wrapped, is rendered with its mapped status and code.", func(t *testing.T) {
+		var resp web.ErrorResponse
+		request, response := testutil.MakeRequest(http.MethodGet, "/registered", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusTeapot, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.Equal(t, "teapot", resp.Code)
+		assert.Equal(t, "brewing: i am a teapot", resp.Message)
+	})
+
+	t.Run("An unregistered error is rendered as a 500.", func(t *testing.T) {
+		var resp web.ErrorResponse
+		request, response := testutil.MakeRequest(http.MethodGet, "/unregistered", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusInternalServerError, response.Code)
+		err := json.Unmarshal(response.Body.Bytes(), &resp)
+		assert.Nil(t, err)
+		assert.Equal(t, "internal_server_error", resp.Code)
+	})
+
+	t.Run("When the handler already wrote a response, it is left untouched.", func(t *testing.T) {
+		request, response := testutil.MakeRequest(http.MethodGet, "/written", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusAccepted, response.Code)
+		assert.Empty(t, response.Body.String())
+	})
+}
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/danilosano/web-golang-api/pkg/reqctx"
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// HeaderUserID carries the user recorded as the actor of the changes. It is
+	// sent by the client and only trustworthy when a gateway in front of the API
+	// authenticates the user and sets it, see TrustIdentity.
+	HeaderUserID = "X-User-ID"
+	// HeaderAPIToken carries the API token of the calling client.
+	HeaderAPIToken = "api_token"
+	// HeaderRequestID carries the ID correlating a request across services.
+	HeaderRequestID = "X-Request-ID"
+	// MaxIdentifierLength is the longest user or request ID accepted, the size of
+	// the columns recording them.
+	MaxIdentifierLength = 100
+)
+
+// ValidIdentifier reports whether id may be stored as a user or request ID: at
+// most MaxIdentifierLength printable ASCII characters.
+func ValidIdentifier(id string) bool {
+	if len(id) > MaxIdentifierLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// contextKeyAuthenticated marks, in the gin context, the requests whose API token
+// and user were verified.
+const contextKeyAuthenticated = "authenticated"
+
+// SetAuthenticated marks the API token and user of the request as verified.
+func SetAuthenticated(c *gin.Context) {
+	c.Set(contextKeyAuthenticated, true)
+}
+
+// Authenticated reports whether the API token and user of the request were
+// verified, either by an authentication middleware or by a trusted gateway.
+func Authenticated(c *gin.Context) bool {
+	return c.GetBool(contextKeyAuthenticated)
+}
+
+// TrustIdentity marks every request as authenticated. Use it only behind a
+// gateway that verifies the api_token and X-User-ID headers and drops them from
+// the requests it did not verify.
+func TrustIdentity() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		SetAuthenticated(c)
+		c.Next()
+	}
+}
+
+// Identity stores the calling user in the request context so that services
+// can read it through reqctx.UserID. Invalid user IDs are rejected.
+func Identity() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetHeader(HeaderUserID)
+		if !ValidIdentifier(userID) {
+			web.Error(c, http.StatusBadRequest, "invalid %s header: at most %d printable ASCII characters", HeaderUserID, MaxIdentifierLength)
+			c.Abort()
+			return
+		}
+		if userID != "" {
+			c.Request = c.Request.WithContext(reqctx.WithUserID(c.Request.Context(), userID))
+		}
+		c.Next()
+	}
+}
+
+// RequestID stores the request ID in the request context and echoes it in the
+// response, generating one when the client did not send it. Invalid request IDs
+// are rejected.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(HeaderRequestID)
+		if !ValidIdentifier(requestID) {
+			web.Error(c, http.StatusBadRequest, "invalid %s header: at most %d printable ASCII characters", HeaderRequestID, MaxIdentifierLength)
+			c.Abort()
+			return
+		}
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+
+		c.Header(HeaderRequestID, requestID)
+		c.Request = c.Request.WithContext(reqctx.WithRequestID(c.Request.Context(), requestID))
+		c.Next()
+	}
+}
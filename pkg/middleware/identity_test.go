@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/danilosano/web-golang-api/pkg/reqctx"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentity(t *testing.T) {
+	server := testutil.CreateServer()
+	server.Use(RequestID(), Identity())
+	server.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, reqctx.UserID(c.Request.Context()))
+	})
+
+	t.Run("The user and request IDs are stored in the request context.", func(t *testing.T) {
+		request, response := testutil.MakeRequest(http.MethodGet, "/", "")
+		request.Header.Set(HeaderUserID, "agent-7")
+		request.Header.Set(HeaderRequestID, "request-1")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "agent-7", response.Body.String())
+		assert.Equal(t, "request-1", response.Header().Get(HeaderRequestID))
+	})
+
+	t.Run("IDs too long to be recorded, or holding control characters, are rejected.", func(t *testing.T) {
+		for header, value := range map[string]string{
+			HeaderUserID:    strings.Repeat("a", MaxIdentifierLength+1),
+			HeaderRequestID: "request\t1",
+		} {
+			request, response := testutil.MakeRequest(http.MethodGet, "/", "")
+			request.Header.Set(header, value)
+			server.ServeHTTP(response, request)
+
+			assert.Equal(t, http.StatusBadRequest, response.Code, header)
+		}
+	})
+}
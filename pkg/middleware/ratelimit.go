@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/danilosano/web-golang-api/pkg/ratelimit"
+	"github.com/danilosano/web-golang-api/pkg/reqctx"
+	"github.com/danilosano/web-golang-api/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+// KeyFunc identifies the client a request is rate limited as. An empty key
+// means the function cannot identify the client.
+type KeyFunc func(c *gin.Context) string
+
+// KeyByAPIToken identifies clients by their API token, once authenticated. The
+// token is hashed so that it is never stored as-is.
+func KeyByAPIToken(c *gin.Context) string {
+	token := c.GetHeader(HeaderAPIToken)
+	if token == "" || !Authenticated(c) {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(token))
+	return "token:" + hex.EncodeToString(sum[:8])
+}
+
+// KeyByUserID identifies clients by the user stored by Identity, once
+// authenticated.
+func KeyByUserID(c *gin.Context) string {
+	userID := reqctx.UserID(c.Request.Context())
+	if userID == "" || !Authenticated(c) {
+		return ""
+	}
+	return "user:" + userID
+}
+
+// KeyByClientIP identifies clients by their IP address.
+func KeyByClientIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// KeyByClient identifies clients by API token, then user ID, then IP address.
+// Unauthenticated clients are always identified by IP address, as they could
+// otherwise get a new bucket by sending another token or user on each request.
+func KeyByClient(c *gin.Context) string {
+	for _, fn := range []KeyFunc{KeyByAPIToken, KeyByUserID, KeyByClientIP} {
+		if key := fn(c); key != "" {
+			return key
+		}
+	}
+	return ""
+}
+
+// RateLimit allows each client limit.Requests requests per limit.Period on every
+// route it is attached to, answering 429 once the bucket is empty. If the store
+// fails the request is let through.
+func RateLimit(store ratelimit.Store, limit ratelimit.Limit, key KeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bucket := limit.String() + "|" + c.Request.Method + " " + c.FullPath() + "|" + key(c)
+
+		res, err := store.Take(c.Request.Context(), bucket, limit)
+		if err != nil {
+			log.Printf("rate limit store error: %s\n", err.Error())
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("X-RateLimit-Reset", seconds(res.ResetAfter))
+
+		if !res.Allowed {
+			c.Header("Retry-After", seconds(res.RetryAfter))
+			web.Error(c, http.StatusTooManyRequests, "rate limit exceeded, retry in %s seconds", seconds(res.RetryAfter))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// seconds rounds d up to whole seconds, as expected by Retry-After.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
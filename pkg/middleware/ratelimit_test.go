@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/danilosano/web-golang-api/pkg/ratelimit"
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	server := testutil.CreateServer()
+	server.Use(RateLimit(ratelimit.NewMemoryStore(), ratelimit.PerMinute(1), KeyByClient))
+	server.GET("/a", func(c *gin.Context) { c.Status(http.StatusOK) })
+	server.GET("/b", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	t.Run("Requests within the limit go through with the rate limit headers.", func(t *testing.T) {
+		request, response := testutil.MakeRequest(http.MethodGet, "/a", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "1", response.Header().Get("X-RateLimit-Limit"))
+		assert.Equal(t, "0", response.Header().Get("X-RateLimit-Remaining"))
+		assert.Equal(t, "60", response.Header().Get("X-RateLimit-Reset"))
+	})
+
+	t.Run("Once the limit is exceeded a 429 is returned with Retry-After.", func(t *testing.T) {
+		request, response := testutil.MakeRequest(http.MethodGet, "/a", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusTooManyRequests, response.Code)
+		assert.Equal(t, "60", response.Header().Get("Retry-After"))
+	})
+
+	t.Run("Each route has its own bucket.", func(t *testing.T) {
+		request, response := testutil.MakeRequest(http.MethodGet, "/b", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("Unauthenticated clients are identified by IP address, whatever token or user they send.", func(t *testing.T) {
+		request, response := testutil.MakeRequest(http.MethodGet, "/a", "")
+		request.Header.Set(HeaderAPIToken, "another-token")
+		request.Header.Set(HeaderUserID, "another-user")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusTooManyRequests, response.Code)
+	})
+
+	t.Run("Each authenticated client has its own bucket.", func(t *testing.T) {
+		server := testutil.CreateServer()
+		server.Use(TrustIdentity(), Identity(), RateLimit(ratelimit.NewMemoryStore(), ratelimit.PerMinute(1), KeyByClient))
+		server.GET("/a", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		for _, token := range []string{"a-token", "another-token"} {
+			request, response := testutil.MakeRequest(http.MethodGet, "/a", "")
+			request.Header.Set(HeaderAPIToken, token)
+			server.ServeHTTP(response, request)
+
+			assert.Equal(t, http.StatusOK, response.Code)
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/danilosano/web-golang-api/pkg/testutil"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	server := testutil.CreateServer()
+	server.Use(SecurityHeaders(SecurityConfig{
+		HSTSMaxAge:            365 * 24 * time.Hour,
+		HSTSIncludeSubdomains: true,
+		FrameOptions:          "DENY",
+		ContentSecurityPolicy: "default-src 'none'",
+		ReferrerPolicy:        "no-referrer",
+	}))
+	server.GET("/customers", func(c *gin.Context) { c.Status(http.StatusOK) })
+	server.GET("/docs", ContentSecurityPolicy("default-src 'self'"), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	t.Run("Every response gets the security headers, but HSTS over plain HTTP.", func(t *testing.T) {
+		request, response := testutil.MakeRequest(http.MethodGet, "/customers", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, "nosniff", response.Header().Get("X-Content-Type-Options"))
+		assert.Equal(t, "DENY", response.Header().Get("X-Frame-Options"))
+		assert.Equal(t, "default-src 'none'", response.Header().Get("Content-Security-Policy"))
+		assert.Equal(t, "no-referrer", response.Header().Get("Referrer-Policy"))
+		assert.Empty(t, response.Header().Get("Strict-Transport-Security"))
+	})
+
+	t.Run("A request forwarded over HTTPS gets HSTS.", func(t *testing.T) {
+		request, response := testutil.MakeRequest(http.MethodGet, "/customers", "")
+		request.Header.Set("X-Forwarded-Proto", "https")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, "max-age=31536000; includeSubDomains", response.Header().Get("Strict-Transport-Security"))
+	})
+
+	t.Run("A route can replace the Content-Security-Policy.", func(t *testing.T) {
+		request, response := testutil.MakeRequest(http.MethodGet, "/docs", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, "default-src 'self'", response.Header().Get("Content-Security-Policy"))
+	})
+}
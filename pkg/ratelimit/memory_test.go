@@ -0,0 +1,55 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore_Take(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limit := PerMinute(2)
+
+	t.Run("The bucket starts full and is drained one token per request.", func(t *testing.T) {
+		res, err := store.Take(ctx, "a", limit)
+		assert.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 1, res.Remaining)
+
+		res, _ = store.Take(ctx, "a", limit)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 0, res.Remaining)
+		assert.Equal(t, time.Minute, res.ResetAfter)
+	})
+
+	t.Run("An empty bucket rejects the request and tells when a token will be available.", func(t *testing.T) {
+		res, _ := store.Take(ctx, "a", limit)
+		assert.False(t, res.Allowed)
+		assert.Equal(t, 30*time.Second, res.RetryAfter)
+	})
+
+	t.Run("Buckets are independent per key.", func(t *testing.T) {
+		res, _ := store.Take(ctx, "b", limit)
+		assert.True(t, res.Allowed)
+	})
+
+	t.Run("Tokens are refilled over time.", func(t *testing.T) {
+		now = now.Add(30 * time.Second)
+		res, _ := store.Take(ctx, "a", limit)
+		assert.True(t, res.Allowed)
+
+		res, _ = store.Take(ctx, "a", limit)
+		assert.False(t, res.Allowed)
+	})
+	t.Run("A limit allowing no request rejects every request.", func(t *testing.T) {
+		res, err := store.Take(ctx, "c", PerMinute(0))
+		assert.NoError(t, err)
+		assert.False(t, res.Allowed)
+		assert.Equal(t, time.Minute, res.RetryAfter)
+	})
+}
@@ -0,0 +1,50 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Limit is a token bucket holding up to Requests tokens that refills
+// Requests tokens every Period.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+func PerSecond(n int) Limit {
+	return Limit{Requests: n, Period: time.Second}
+}
+
+func PerMinute(n int) Limit {
+	return Limit{Requests: n, Period: time.Minute}
+}
+
+func (l Limit) String() string {
+	return fmt.Sprintf("%d/%s", l.Requests, l.Period)
+}
+
+// interval is the time needed to refill a single token. A limit allowing no
+// request refills none, and waits a whole period.
+func (l Limit) interval() time.Duration {
+	if l.Requests <= 0 {
+		return l.Period
+	}
+	return l.Period / time.Duration(l.Requests)
+}
+
+// Result is the outcome of taking a token from a bucket.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+	ResetAfter time.Duration
+}
+
+// Store keeps the token buckets. Implementations backed by a shared store
+// (e.g. Redis) allow several API instances to enforce the same limits.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
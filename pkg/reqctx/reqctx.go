@@ -0,0 +1,32 @@
+// Package reqctx carries request-scoped values, such as the calling user and the
+// request ID, from the HTTP layer down to the services.
+package reqctx
+
+import "context"
+
+type (
+	userIDKey    struct{}
+	requestIDKey struct{}
+)
+
+// WithUserID returns a copy of ctx carrying the given user ID.
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// UserID returns the user ID stored in ctx, or "" when anonymous.
+func UserID(ctx context.Context) string {
+	userID, _ := ctx.Value(userIDKey{}).(string)
+	return userID
+}
+
+// WithRequestID returns a copy of ctx carrying the given request ID.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the request ID stored in ctx, or "" when there is none.
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
@@ -0,0 +1,122 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/stretchr/testify/mock"
+)
+
+type AddressServiceMock struct {
+	mock.Mock
+}
+
+func (a *AddressServiceMock) Save(ctx context.Context, input dto.AddressRequest, customerID int) (domain.Address, error) {
+	args := a.Called(ctx, input, customerID)
+
+	arg0, ok := args.Get(0).(domain.Address)
+	if !ok {
+		return domain.Address{}, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (a *AddressServiceMock) GetAll(ctx context.Context, customerID int) ([]domain.Address, error) {
+	args := a.Called(ctx, customerID)
+
+	arg0, ok := args.Get(0).([]domain.Address)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (a *AddressServiceMock) Get(ctx context.Context, customerID, id int) (domain.Address, error) {
+	args := a.Called(ctx, customerID, id)
+
+	arg0, ok := args.Get(0).(domain.Address)
+	if !ok {
+		return domain.Address{}, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (a *AddressServiceMock) Update(ctx context.Context, input dto.AddressRequest, customerID, id int) (domain.Address, error) {
+	args := a.Called(ctx, input, customerID, id)
+
+	arg0, ok := args.Get(0).(domain.Address)
+	if !ok {
+		return domain.Address{}, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (a *AddressServiceMock) Delete(ctx context.Context, customerID, id int) error {
+	args := a.Called(ctx, customerID, id)
+	return args.Error(0)
+}
+
+func (a *AddressServiceMock) GetByCustomerIDs(ctx context.Context, customerIDs []int) (map[int][]domain.Address, error) {
+	args := a.Called(ctx, customerIDs)
+
+	arg0, ok := args.Get(0).(map[int][]domain.Address)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+type AddressRepositoryMock struct {
+	mock.Mock
+}
+
+func (a *AddressRepositoryMock) GetByCustomerIDWithContext(ctx context.Context, customerID int) ([]domain.Address, error) {
+	args := a.Called(ctx, customerID)
+
+	arg0, ok := args.Get(0).([]domain.Address)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (a *AddressRepositoryMock) GetByCustomerIDsWithContext(ctx context.Context, customerIDs []int) ([]domain.Address, error) {
+	args := a.Called(ctx, customerIDs)
+
+	arg0, ok := args.Get(0).([]domain.Address)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (a *AddressRepositoryMock) GetWithContext(ctx context.Context, customerID, id int) (domain.Address, error) {
+	args := a.Called(ctx, customerID, id)
+
+	arg0, ok := args.Get(0).(domain.Address)
+	if !ok {
+		return domain.Address{}, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (a *AddressRepositoryMock) SaveWithContext(ctx context.Context, addr domain.Address) (int, error) {
+	args := a.Called(ctx, addr)
+	return args.Int(0), args.Error(1)
+}
+
+func (a *AddressRepositoryMock) UpdateWithContext(ctx context.Context, addr domain.Address) error {
+	args := a.Called(ctx, addr)
+	return args.Error(0)
+}
+
+func (a *AddressRepositoryMock) DeleteWithContext(ctx context.Context, customerID, id int) error {
+	args := a.Called(ctx, customerID, id)
+	return args.Error(0)
+}
+
+func (a *AddressRepositoryMock) ClearDefaultWithContext(ctx context.Context, customerID int, addressType string) error {
+	args := a.Called(ctx, customerID, addressType)
+	return args.Error(0)
+}
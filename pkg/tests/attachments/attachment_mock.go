@@ -0,0 +1,84 @@
+package mocks
+
+import (
+	"context"
+	"io"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/stretchr/testify/mock"
+)
+
+type AttachmentServiceMock struct {
+	mock.Mock
+}
+
+func (a *AttachmentServiceMock) Upload(ctx context.Context, customerID int, file dto.AttachmentFile) (domain.Attachment, error) {
+	args := a.Called(ctx, customerID, file)
+
+	arg0, ok := args.Get(0).(domain.Attachment)
+	if !ok {
+		return domain.Attachment{}, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (a *AttachmentServiceMock) GetAll(ctx context.Context, customerID int) ([]domain.Attachment, error) {
+	args := a.Called(ctx, customerID)
+
+	arg0, ok := args.Get(0).([]domain.Attachment)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (a *AttachmentServiceMock) Download(ctx context.Context, customerID, id int) (domain.Attachment, io.ReadCloser, error) {
+	args := a.Called(ctx, customerID, id)
+
+	arg0, ok := args.Get(0).(domain.Attachment)
+	if !ok {
+		return domain.Attachment{}, nil, args.Error(2)
+	}
+	arg1, _ := args.Get(1).(io.ReadCloser)
+	return arg0, arg1, args.Error(2)
+}
+
+func (a *AttachmentServiceMock) Delete(ctx context.Context, customerID, id int) error {
+	args := a.Called(ctx, customerID, id)
+	return args.Error(0)
+}
+
+type AttachmentRepositoryMock struct {
+	mock.Mock
+}
+
+func (a *AttachmentRepositoryMock) GetByCustomerIDWithContext(ctx context.Context, customerID int) ([]domain.Attachment, error) {
+	args := a.Called(ctx, customerID)
+
+	arg0, ok := args.Get(0).([]domain.Attachment)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (a *AttachmentRepositoryMock) GetWithContext(ctx context.Context, customerID, id int) (domain.Attachment, error) {
+	args := a.Called(ctx, customerID, id)
+
+	arg0, ok := args.Get(0).(domain.Attachment)
+	if !ok {
+		return domain.Attachment{}, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (a *AttachmentRepositoryMock) SaveWithContext(ctx context.Context, attachment domain.Attachment) (int, error) {
+	args := a.Called(ctx, attachment)
+	return args.Int(0), args.Error(1)
+}
+
+func (a *AttachmentRepositoryMock) DeleteWithContext(ctx context.Context, customerID, id int) error {
+	args := a.Called(ctx, customerID, id)
+	return args.Error(0)
+}
@@ -0,0 +1,112 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/stretchr/testify/mock"
+)
+
+type AttributeServiceMock struct {
+	mock.Mock
+}
+
+func (a *AttributeServiceMock) Save(ctx context.Context, input dto.AttributeDefinitionRequest) (domain.AttributeDefinition, error) {
+	args := a.Called(ctx, input)
+
+	arg0, ok := args.Get(0).(domain.AttributeDefinition)
+	if !ok {
+		return domain.AttributeDefinition{}, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (a *AttributeServiceMock) GetAll(ctx context.Context) ([]domain.AttributeDefinition, error) {
+	args := a.Called(ctx)
+
+	arg0, ok := args.Get(0).([]domain.AttributeDefinition)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (a *AttributeServiceMock) Get(ctx context.Context, id int) (domain.AttributeDefinition, error) {
+	args := a.Called(ctx, id)
+
+	arg0, ok := args.Get(0).(domain.AttributeDefinition)
+	if !ok {
+		return domain.AttributeDefinition{}, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (a *AttributeServiceMock) Update(ctx context.Context, input dto.AttributeDefinitionRequest, id int) (domain.AttributeDefinition, error) {
+	args := a.Called(ctx, input, id)
+
+	arg0, ok := args.Get(0).(domain.AttributeDefinition)
+	if !ok {
+		return domain.AttributeDefinition{}, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (a *AttributeServiceMock) Delete(ctx context.Context, id int) error {
+	args := a.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (a *AttributeServiceMock) Validate(ctx context.Context, attributes domain.Attributes) (domain.Attributes, error) {
+	args := a.Called(ctx, attributes)
+
+	arg0, ok := args.Get(0).(domain.Attributes)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+type AttributeRepositoryMock struct {
+	mock.Mock
+}
+
+func (a *AttributeRepositoryMock) GetAllWithContext(ctx context.Context) ([]domain.AttributeDefinition, error) {
+	args := a.Called(ctx)
+
+	arg0, ok := args.Get(0).([]domain.AttributeDefinition)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (a *AttributeRepositoryMock) GetWithContext(ctx context.Context, id int) (domain.AttributeDefinition, error) {
+	args := a.Called(ctx, id)
+
+	arg0, ok := args.Get(0).(domain.AttributeDefinition)
+	if !ok {
+		return domain.AttributeDefinition{}, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (a *AttributeRepositoryMock) ExistsByNameWithContext(ctx context.Context, name string, id int) bool {
+	args := a.Called(ctx, name, id)
+	return args.Bool(0)
+}
+
+func (a *AttributeRepositoryMock) SaveWithContext(ctx context.Context, d domain.AttributeDefinition) (int, error) {
+	args := a.Called(ctx, d)
+	return args.Int(0), args.Error(1)
+}
+
+func (a *AttributeRepositoryMock) UpdateWithContext(ctx context.Context, d domain.AttributeDefinition) error {
+	args := a.Called(ctx, d)
+	return args.Error(0)
+}
+
+func (a *AttributeRepositoryMock) DeleteWithContext(ctx context.Context, id int) error {
+	args := a.Called(ctx, id)
+	return args.Error(0)
+}
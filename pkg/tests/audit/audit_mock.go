@@ -0,0 +1,52 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type AuditServiceMock struct {
+	mock.Mock
+}
+
+func (a *AuditServiceMock) GetByCustomerID(ctx context.Context, customerID int) ([]domain.AuditEntry, error) {
+	args := a.Called(ctx, customerID)
+
+	arg0, ok := args.Get(0).([]domain.AuditEntry)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+type AuditRepositoryMock struct {
+	mock.Mock
+}
+
+func (a *AuditRepositoryMock) SaveWithContext(ctx context.Context, e domain.AuditEntry) (int, error) {
+	args := a.Called(ctx, e)
+	return args.Int(0), args.Error(1)
+}
+
+func (a *AuditRepositoryMock) GetByCustomerIDWithContext(ctx context.Context, customerID int) ([]domain.AuditEntry, error) {
+	args := a.Called(ctx, customerID)
+
+	arg0, ok := args.Get(0).([]domain.AuditEntry)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (a *AuditRepositoryMock) GetByCustomerIDAfterWithContext(ctx context.Context, customerID int, after time.Time, afterID, limit int) ([]domain.AuditEntry, error) {
+	args := a.Called(ctx, customerID, after, afterID, limit)
+
+	arg0, ok := args.Get(0).([]domain.AuditEntry)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
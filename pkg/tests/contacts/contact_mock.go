@@ -0,0 +1,127 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/stretchr/testify/mock"
+)
+
+type ContactServiceMock struct {
+	mock.Mock
+}
+
+func (c *ContactServiceMock) Save(ctx context.Context, input dto.ContactRequest, customerID int) (domain.Contact, error) {
+	args := c.Called(ctx, input, customerID)
+
+	arg0, ok := args.Get(0).(domain.Contact)
+	if !ok {
+		return domain.Contact{}, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (c *ContactServiceMock) GetAll(ctx context.Context, customerID int) ([]domain.Contact, error) {
+	args := c.Called(ctx, customerID)
+
+	arg0, ok := args.Get(0).([]domain.Contact)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (c *ContactServiceMock) Get(ctx context.Context, customerID, id int) (domain.Contact, error) {
+	args := c.Called(ctx, customerID, id)
+
+	arg0, ok := args.Get(0).(domain.Contact)
+	if !ok {
+		return domain.Contact{}, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (c *ContactServiceMock) Update(ctx context.Context, input dto.ContactRequest, customerID, id int) (domain.Contact, error) {
+	args := c.Called(ctx, input, customerID, id)
+
+	arg0, ok := args.Get(0).(domain.Contact)
+	if !ok {
+		return domain.Contact{}, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (c *ContactServiceMock) Delete(ctx context.Context, customerID, id int) error {
+	args := c.Called(ctx, customerID, id)
+	return args.Error(0)
+}
+
+func (c *ContactServiceMock) GetByCustomerIDs(ctx context.Context, customerIDs []int) (map[int][]domain.Contact, error) {
+	args := c.Called(ctx, customerIDs)
+
+	arg0, ok := args.Get(0).(map[int][]domain.Contact)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+type ContactRepositoryMock struct {
+	mock.Mock
+}
+
+func (c *ContactRepositoryMock) GetByCustomerIDWithContext(ctx context.Context, customerID int) ([]domain.Contact, error) {
+	args := c.Called(ctx, customerID)
+
+	arg0, ok := args.Get(0).([]domain.Contact)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (c *ContactRepositoryMock) GetByCustomerIDsWithContext(ctx context.Context, customerIDs []int) ([]domain.Contact, error) {
+	args := c.Called(ctx, customerIDs)
+
+	arg0, ok := args.Get(0).([]domain.Contact)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (c *ContactRepositoryMock) GetWithContext(ctx context.Context, customerID, id int) (domain.Contact, error) {
+	args := c.Called(ctx, customerID, id)
+
+	arg0, ok := args.Get(0).(domain.Contact)
+	if !ok {
+		return domain.Contact{}, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (c *ContactRepositoryMock) SaveWithContext(ctx context.Context, ct domain.Contact) (int, error) {
+	args := c.Called(ctx, ct)
+	return args.Int(0), args.Error(1)
+}
+
+func (c *ContactRepositoryMock) UpdateWithContext(ctx context.Context, ct domain.Contact) error {
+	args := c.Called(ctx, ct)
+	return args.Error(0)
+}
+
+func (c *ContactRepositoryMock) DeleteWithContext(ctx context.Context, customerID, id int) error {
+	args := c.Called(ctx, customerID, id)
+	return args.Error(0)
+}
+
+func (c *ContactRepositoryMock) ClearPrimaryWithContext(ctx context.Context, customerID int, contactType string) error {
+	args := c.Called(ctx, customerID, contactType)
+	return args.Error(0)
+}
+
+func (c *ContactRepositoryMock) ExistsPrimaryEmailWithContext(ctx context.Context, email string, customerID int) bool {
+	args := c.Called(ctx, email, customerID)
+	return args.Bool(0)
+}
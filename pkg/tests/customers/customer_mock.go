@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/danilosano/web-golang-api/internal/domain"
 	"github.com/danilosano/web-golang-api/internal/domain/dto"
@@ -64,6 +65,89 @@ func (p *CustomersServiceMock) Get(ctx context.Context, id int) (dto.ResultCusto
 	return arg0, args.Error(1)
 }
 
+func (p *CustomersServiceMock) GetByCustomerNumber(ctx context.Context, customerNumber int) (dto.ResultCustomerRequest, error) {
+	args := p.Called(ctx, customerNumber)
+
+	arg0, ok := args.Get(0).(dto.ResultCustomerRequest)
+	if !ok {
+		return dto.ResultCustomerRequest{}, args.Error(1)
+	}
+
+	return arg0, args.Error(1)
+}
+
+func (p *CustomersServiceMock) List(ctx context.Context, f dto.CustomerFilter) (dto.CustomerPage, error) {
+	args := p.Called(ctx, f)
+
+	arg0, ok := args.Get(0).(dto.CustomerPage)
+	if !ok {
+		return dto.CustomerPage{}, args.Error(1)
+	}
+
+	return arg0, args.Error(1)
+}
+
+func (p *CustomersServiceMock) ChangeStatus(ctx context.Context, id int, input dto.CustomerStatusRequest) (dto.ResultCustomerRequest, error) {
+	args := p.Called(ctx, id, input)
+
+	arg0, ok := args.Get(0).(dto.ResultCustomerRequest)
+	if !ok {
+		return dto.ResultCustomerRequest{}, args.Error(1)
+	}
+
+	return arg0, args.Error(1)
+}
+
+func (p *CustomersServiceMock) FindDuplicates(ctx context.Context, id int) ([]dto.DuplicateCandidate, error) {
+	args := p.Called(ctx, id)
+
+	arg0, ok := args.Get(0).([]dto.DuplicateCandidate)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (p *CustomersServiceMock) Merge(ctx context.Context, input dto.MergeRequest) (dto.ResultCustomerRequest, error) {
+	args := p.Called(ctx, input)
+
+	arg0, ok := args.Get(0).(dto.ResultCustomerRequest)
+	if !ok {
+		return dto.ResultCustomerRequest{}, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (p *CustomersServiceMock) Children(ctx context.Context, id int) ([]dto.ResultCustomerRequest, error) {
+	args := p.Called(ctx, id)
+
+	arg0, ok := args.Get(0).([]dto.ResultCustomerRequest)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (p *CustomersServiceMock) Ancestors(ctx context.Context, id int) ([]dto.ResultCustomerRequest, error) {
+	args := p.Called(ctx, id)
+
+	arg0, ok := args.Get(0).([]dto.ResultCustomerRequest)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (p *CustomersServiceMock) Tree(ctx context.Context, id int) (dto.CustomerTree, error) {
+	args := p.Called(ctx, id)
+
+	arg0, ok := args.Get(0).(dto.CustomerTree)
+	if !ok {
+		return dto.CustomerTree{}, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
 type CustomersRepositoryMock struct {
 	mock.Mock
 }
@@ -101,6 +185,17 @@ func (s *CustomersRepositoryMock) GetByCustomerNumberWithContext(ctx context.Con
 	return arg0, args.Error(1)
 }
 
+func (s *CustomersRepositoryMock) ListWithContext(ctx context.Context, f dto.CustomerFilter) ([]dto.ResultCustomerRequest, int, error) {
+	args := s.Called(ctx, f)
+
+	arg0, ok := args.Get(0).([]dto.ResultCustomerRequest)
+	if !ok {
+		return []dto.ResultCustomerRequest{}, args.Int(1), args.Error(2)
+	}
+
+	return arg0, args.Int(1), args.Error(2)
+}
+
 func (s *CustomersRepositoryMock) GetByNameWithContext(ctx context.Context, name string) (domain.Customer, error) {
 	args := s.Called(ctx, name)
 
@@ -141,3 +236,111 @@ func (s *CustomersRepositoryMock) DeleteWithContext(ctx context.Context, id int)
 	args := s.Called(ctx, id)
 	return args.Error(0)
 }
+
+func (s *CustomersRepositoryMock) UpdateStatusWithContext(ctx context.Context, id int, from, to string, updatedAt time.Time) error {
+	args := s.Called(ctx, id, from, to, updatedAt)
+	return args.Error(0)
+}
+
+func (s *CustomersRepositoryMock) SaveStatusChangeWithContext(ctx context.Context, change domain.CustomerStatusChange) (int, error) {
+	args := s.Called(ctx, change)
+	return args.Int(0), args.Error(1)
+}
+
+func (s *CustomersRepositoryMock) GetStatusChangesWithContext(ctx context.Context, customerID int) ([]domain.CustomerStatusChange, error) {
+	args := s.Called(ctx, customerID)
+
+	arg0, ok := args.Get(0).([]domain.CustomerStatusChange)
+	if !ok {
+		return []domain.CustomerStatusChange{}, args.Error(1)
+	}
+
+	return arg0, args.Error(1)
+}
+
+func (s *CustomersRepositoryMock) GetStatusChangesAfterWithContext(ctx context.Context, customerID int, after time.Time, afterID, limit int) ([]domain.CustomerStatusChange, error) {
+	args := s.Called(ctx, customerID, after, afterID, limit)
+
+	arg0, ok := args.Get(0).([]domain.CustomerStatusChange)
+	if !ok {
+		return nil, args.Error(1)
+	}
+
+	return arg0, args.Error(1)
+}
+
+func (s *CustomersRepositoryMock) GetByNameMatchWithContext(ctx context.Context, name string, excludeID, limit int) ([]dto.ResultCustomerRequest, error) {
+	args := s.Called(ctx, name, excludeID, limit)
+
+	arg0, ok := args.Get(0).([]dto.ResultCustomerRequest)
+	if !ok {
+		return nil, args.Error(1)
+	}
+
+	return arg0, args.Error(1)
+}
+
+func (s *CustomersRepositoryMock) GetSharedContactsWithContext(ctx context.Context, id int) (map[int][]string, error) {
+	args := s.Called(ctx, id)
+
+	arg0, ok := args.Get(0).(map[int][]string)
+	if !ok {
+		return nil, args.Error(1)
+	}
+
+	return arg0, args.Error(1)
+}
+
+func (s *CustomersRepositoryMock) MergeWithContext(ctx context.Context, sourceID, targetID int) error {
+	args := s.Called(ctx, sourceID, targetID)
+	return args.Error(0)
+}
+
+func (s *CustomersRepositoryMock) GetMergedIntoWithContext(ctx context.Context, id int) (int, error) {
+	args := s.Called(ctx, id)
+	return args.Int(0), args.Error(1)
+}
+
+func (s *CustomersRepositoryMock) GetChildrenWithContext(ctx context.Context, id int) ([]dto.ResultCustomerRequest, error) {
+	args := s.Called(ctx, id)
+
+	arg0, ok := args.Get(0).([]dto.ResultCustomerRequest)
+	if !ok {
+		return nil, args.Error(1)
+	}
+
+	return arg0, args.Error(1)
+}
+
+func (s *CustomersRepositoryMock) GetAncestorsWithContext(ctx context.Context, id int) ([]dto.ResultCustomerRequest, error) {
+	args := s.Called(ctx, id)
+
+	arg0, ok := args.Get(0).([]dto.ResultCustomerRequest)
+	if !ok {
+		return nil, args.Error(1)
+	}
+
+	return arg0, args.Error(1)
+}
+
+func (s *CustomersRepositoryMock) LockHierarchyWithContext(ctx context.Context, id int) ([]int, error) {
+	args := s.Called(ctx, id)
+
+	arg0, ok := args.Get(0).([]int)
+	if !ok {
+		return nil, args.Error(1)
+	}
+
+	return arg0, args.Error(1)
+}
+
+func (s *CustomersRepositoryMock) GetTreeWithContext(ctx context.Context, rootID int) ([]dto.ResultCustomerRequest, error) {
+	args := s.Called(ctx, rootID)
+
+	arg0, ok := args.Get(0).([]dto.ResultCustomerRequest)
+	if !ok {
+		return nil, args.Error(1)
+	}
+
+	return arg0, args.Error(1)
+}
@@ -0,0 +1,133 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/stretchr/testify/mock"
+)
+
+type NoteServiceMock struct {
+	mock.Mock
+}
+
+func (n *NoteServiceMock) Save(ctx context.Context, input dto.NoteRequest, customerID int) (domain.Note, error) {
+	args := n.Called(ctx, input, customerID)
+
+	arg0, ok := args.Get(0).(domain.Note)
+	if !ok {
+		return domain.Note{}, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (n *NoteServiceMock) GetAll(ctx context.Context, customerID int) ([]domain.Note, error) {
+	args := n.Called(ctx, customerID)
+
+	arg0, ok := args.Get(0).([]domain.Note)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (n *NoteServiceMock) Get(ctx context.Context, customerID, id int) (domain.Note, error) {
+	args := n.Called(ctx, customerID, id)
+
+	arg0, ok := args.Get(0).(domain.Note)
+	if !ok {
+		return domain.Note{}, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (n *NoteServiceMock) Update(ctx context.Context, input dto.NoteRequest, customerID, id int) (domain.Note, error) {
+	args := n.Called(ctx, input, customerID, id)
+
+	arg0, ok := args.Get(0).(domain.Note)
+	if !ok {
+		return domain.Note{}, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (n *NoteServiceMock) Delete(ctx context.Context, customerID, id int) error {
+	args := n.Called(ctx, customerID, id)
+	return args.Error(0)
+}
+
+func (n *NoteServiceMock) GetRevisions(ctx context.Context, customerID, id int) ([]domain.NoteRevision, error) {
+	args := n.Called(ctx, customerID, id)
+
+	arg0, ok := args.Get(0).([]domain.NoteRevision)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+type NoteRepositoryMock struct {
+	mock.Mock
+}
+
+func (n *NoteRepositoryMock) GetByCustomerIDWithContext(ctx context.Context, customerID int) ([]domain.Note, error) {
+	args := n.Called(ctx, customerID)
+
+	arg0, ok := args.Get(0).([]domain.Note)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (n *NoteRepositoryMock) GetAfterWithContext(ctx context.Context, customerID int, after time.Time, afterID, limit int) ([]domain.Note, error) {
+	args := n.Called(ctx, customerID, after, afterID, limit)
+
+	arg0, ok := args.Get(0).([]domain.Note)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (n *NoteRepositoryMock) GetWithContext(ctx context.Context, customerID, id int) (domain.Note, error) {
+	args := n.Called(ctx, customerID, id)
+
+	arg0, ok := args.Get(0).(domain.Note)
+	if !ok {
+		return domain.Note{}, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (n *NoteRepositoryMock) SaveWithContext(ctx context.Context, note domain.Note) (int, error) {
+	args := n.Called(ctx, note)
+	return args.Int(0), args.Error(1)
+}
+
+func (n *NoteRepositoryMock) UpdateWithContext(ctx context.Context, note domain.Note) error {
+	args := n.Called(ctx, note)
+	return args.Error(0)
+}
+
+func (n *NoteRepositoryMock) DeleteWithContext(ctx context.Context, customerID, id int) error {
+	args := n.Called(ctx, customerID, id)
+	return args.Error(0)
+}
+
+func (n *NoteRepositoryMock) SaveRevisionWithContext(ctx context.Context, rev domain.NoteRevision) (int, error) {
+	args := n.Called(ctx, rev)
+	return args.Int(0), args.Error(1)
+}
+
+func (n *NoteRepositoryMock) GetRevisionsWithContext(ctx context.Context, noteID int) ([]domain.NoteRevision, error) {
+	args := n.Called(ctx, noteID)
+
+	arg0, ok := args.Get(0).([]domain.NoteRevision)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
@@ -0,0 +1,53 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type OutboxRepositoryMock struct {
+	mock.Mock
+}
+
+func (o *OutboxRepositoryMock) SaveWithContext(ctx context.Context, e domain.Event) error {
+	args := o.Called(ctx, e)
+	return args.Error(0)
+}
+
+func (o *OutboxRepositoryMock) GetPendingWithContext(ctx context.Context, limit int) ([]domain.OutboxEntry, error) {
+	args := o.Called(ctx, limit)
+
+	arg0, ok := args.Get(0).([]domain.OutboxEntry)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (o *OutboxRepositoryMock) LockWithContext(ctx context.Context, ids []string, until time.Time) error {
+	args := o.Called(ctx, ids, until)
+	return args.Error(0)
+}
+
+func (o *OutboxRepositoryMock) UnlockWithContext(ctx context.Context, ids []string) error {
+	args := o.Called(ctx, ids)
+	return args.Error(0)
+}
+
+func (o *OutboxRepositoryMock) MarkDispatchedWithContext(ctx context.Context, id string) error {
+	args := o.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (o *OutboxRepositoryMock) MarkFailedWithContext(ctx context.Context, id string, cause error, retryAt time.Time) error {
+	args := o.Called(ctx, id, cause, retryAt)
+	return args.Error(0)
+}
+
+func (o *OutboxRepositoryMock) DeadLetterWithContext(ctx context.Context, id string, cause error) error {
+	args := o.Called(ctx, id, cause)
+	return args.Error(0)
+}
@@ -0,0 +1,21 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type SequenceRepositoryMock struct {
+	mock.Mock
+}
+
+func (s *SequenceRepositoryMock) NextWithContext(ctx context.Context, name string) (int64, error) {
+	args := s.Called(ctx, name)
+
+	arg0, ok := args.Get(0).(int64)
+	if !ok {
+		return 0, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
@@ -0,0 +1,117 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type TagServiceMock struct {
+	mock.Mock
+}
+
+func (t *TagServiceMock) GetAll(ctx context.Context, customerID int) ([]domain.Tag, error) {
+	args := t.Called(ctx, customerID)
+
+	arg0, ok := args.Get(0).([]domain.Tag)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (t *TagServiceMock) Add(ctx context.Context, customerID int, names []string) ([]domain.Tag, error) {
+	args := t.Called(ctx, customerID, names)
+
+	arg0, ok := args.Get(0).([]domain.Tag)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (t *TagServiceMock) AddToCustomers(ctx context.Context, customerIDs []int, names []string) error {
+	args := t.Called(ctx, customerIDs, names)
+	return args.Error(0)
+}
+
+func (t *TagServiceMock) Remove(ctx context.Context, customerID int, name string) error {
+	args := t.Called(ctx, customerID, name)
+	return args.Error(0)
+}
+
+func (t *TagServiceMock) Counts(ctx context.Context) ([]domain.TagCount, error) {
+	args := t.Called(ctx)
+
+	arg0, ok := args.Get(0).([]domain.TagCount)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (t *TagServiceMock) GetByCustomerIDs(ctx context.Context, customerIDs []int) (map[int][]domain.Tag, error) {
+	args := t.Called(ctx, customerIDs)
+
+	arg0, ok := args.Get(0).(map[int][]domain.Tag)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+type TagRepositoryMock struct {
+	mock.Mock
+}
+
+func (t *TagRepositoryMock) GetByCustomerIDWithContext(ctx context.Context, customerID int) ([]domain.Tag, error) {
+	args := t.Called(ctx, customerID)
+
+	arg0, ok := args.Get(0).([]domain.Tag)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (t *TagRepositoryMock) GetByCustomerIDsWithContext(ctx context.Context, customerIDs []int) (map[int][]domain.Tag, error) {
+	args := t.Called(ctx, customerIDs)
+
+	arg0, ok := args.Get(0).(map[int][]domain.Tag)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (t *TagRepositoryMock) EnsureWithContext(ctx context.Context, names []string, createdAt time.Time) ([]int, error) {
+	args := t.Called(ctx, names, createdAt)
+
+	arg0, ok := args.Get(0).([]int)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (t *TagRepositoryMock) AddWithContext(ctx context.Context, customerIDs, tagIDs []int, taggedAt time.Time) error {
+	args := t.Called(ctx, customerIDs, tagIDs, taggedAt)
+	return args.Error(0)
+}
+
+func (t *TagRepositoryMock) RemoveWithContext(ctx context.Context, customerID int, name string) error {
+	args := t.Called(ctx, customerID, name)
+	return args.Error(0)
+}
+
+func (t *TagRepositoryMock) CountWithContext(ctx context.Context) ([]domain.TagCount, error) {
+	args := t.Called(ctx)
+
+	arg0, ok := args.Get(0).([]domain.TagCount)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
@@ -0,0 +1,22 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/stretchr/testify/mock"
+)
+
+type TimelineServiceMock struct {
+	mock.Mock
+}
+
+func (t *TimelineServiceMock) Get(ctx context.Context, customerID int, f dto.TimelineFilter) (dto.TimelinePage, error) {
+	args := t.Called(ctx, customerID, f)
+
+	arg0, ok := args.Get(0).(dto.TimelinePage)
+	if !ok {
+		return dto.TimelinePage{}, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
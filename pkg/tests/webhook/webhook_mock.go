@@ -0,0 +1,178 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/danilosano/web-golang-api/internal/domain"
+	"github.com/danilosano/web-golang-api/internal/domain/dto"
+	"github.com/stretchr/testify/mock"
+)
+
+type WebhookRepositoryMock struct {
+	mock.Mock
+}
+
+func (w *WebhookRepositoryMock) GetAllWithContext(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	args := w.Called(ctx)
+
+	arg0, ok := args.Get(0).([]domain.WebhookSubscription)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (w *WebhookRepositoryMock) GetWithContext(ctx context.Context, id int) (domain.WebhookSubscription, error) {
+	args := w.Called(ctx, id)
+
+	arg0, ok := args.Get(0).(domain.WebhookSubscription)
+	if !ok {
+		return domain.WebhookSubscription{}, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (w *WebhookRepositoryMock) GetActiveByEventTypeWithContext(ctx context.Context, eventType string) ([]domain.WebhookSubscription, error) {
+	args := w.Called(ctx, eventType)
+
+	arg0, ok := args.Get(0).([]domain.WebhookSubscription)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (w *WebhookRepositoryMock) SaveWithContext(ctx context.Context, s domain.WebhookSubscription) (int, error) {
+	args := w.Called(ctx, s)
+	return args.Int(0), args.Error(1)
+}
+
+func (w *WebhookRepositoryMock) UpdateWithContext(ctx context.Context, s domain.WebhookSubscription) error {
+	args := w.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (w *WebhookRepositoryMock) DeleteWithContext(ctx context.Context, id int) error {
+	args := w.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (w *WebhookRepositoryMock) GetDeliveriesWithContext(ctx context.Context, subscriptionID int) ([]domain.WebhookDelivery, error) {
+	args := w.Called(ctx, subscriptionID)
+
+	arg0, ok := args.Get(0).([]domain.WebhookDelivery)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (w *WebhookRepositoryMock) GetDeliveryWithContext(ctx context.Context, subscriptionID, id int) (domain.WebhookDelivery, error) {
+	args := w.Called(ctx, subscriptionID, id)
+
+	arg0, ok := args.Get(0).(domain.WebhookDelivery)
+	if !ok {
+		return domain.WebhookDelivery{}, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (w *WebhookRepositoryMock) GetDueDeliveriesWithContext(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	args := w.Called(ctx, now, limit)
+
+	arg0, ok := args.Get(0).([]domain.WebhookDelivery)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (w *WebhookRepositoryMock) SaveDeliveryWithContext(ctx context.Context, d domain.WebhookDelivery) (int, error) {
+	args := w.Called(ctx, d)
+	return args.Int(0), args.Error(1)
+}
+
+func (w *WebhookRepositoryMock) SaveRedeliveryWithContext(ctx context.Context, d domain.WebhookDelivery) (int, error) {
+	args := w.Called(ctx, d)
+	return args.Int(0), args.Error(1)
+}
+
+func (w *WebhookRepositoryMock) LockDeliveriesWithContext(ctx context.Context, ids []int, until time.Time) error {
+	args := w.Called(ctx, ids, until)
+	return args.Error(0)
+}
+
+func (w *WebhookRepositoryMock) UpdateDeliveryWithContext(ctx context.Context, d domain.WebhookDelivery) error {
+	args := w.Called(ctx, d)
+	return args.Error(0)
+}
+
+type WebhookServiceMock struct {
+	mock.Mock
+}
+
+func (w *WebhookServiceMock) Save(ctx context.Context, input dto.WebhookRequest) (domain.WebhookSubscription, error) {
+	args := w.Called(ctx, input)
+
+	arg0, ok := args.Get(0).(domain.WebhookSubscription)
+	if !ok {
+		return domain.WebhookSubscription{}, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (w *WebhookServiceMock) GetAll(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	args := w.Called(ctx)
+
+	arg0, ok := args.Get(0).([]domain.WebhookSubscription)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (w *WebhookServiceMock) Get(ctx context.Context, id int) (domain.WebhookSubscription, error) {
+	args := w.Called(ctx, id)
+
+	arg0, ok := args.Get(0).(domain.WebhookSubscription)
+	if !ok {
+		return domain.WebhookSubscription{}, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (w *WebhookServiceMock) Update(ctx context.Context, input dto.WebhookRequest, id int) (domain.WebhookSubscription, error) {
+	args := w.Called(ctx, input, id)
+
+	arg0, ok := args.Get(0).(domain.WebhookSubscription)
+	if !ok {
+		return domain.WebhookSubscription{}, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (w *WebhookServiceMock) Delete(ctx context.Context, id int) error {
+	args := w.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (w *WebhookServiceMock) GetDeliveries(ctx context.Context, id int) ([]domain.WebhookDelivery, error) {
+	args := w.Called(ctx, id)
+
+	arg0, ok := args.Get(0).([]domain.WebhookDelivery)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
+
+func (w *WebhookServiceMock) Redeliver(ctx context.Context, id, deliveryID int) (domain.WebhookDelivery, error) {
+	args := w.Called(ctx, id, deliveryID)
+
+	arg0, ok := args.Get(0).(domain.WebhookDelivery)
+	if !ok {
+		return domain.WebhookDelivery{}, args.Error(1)
+	}
+	return arg0, args.Error(1)
+}
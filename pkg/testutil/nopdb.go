@@ -0,0 +1,65 @@
+package testutil
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+)
+
+var registerNopDriver sync.Once
+
+// NopDB opens a database whose transactions begin and commit without doing
+// anything, for the tests of code running in transactions without a MySQL
+// server. Any statement fails.
+func NopDB(t *testing.T) *sql.DB {
+	t.Helper()
+	registerNopDriver.Do(func() {
+		sql.Register("nop", nopDriver{})
+	})
+
+	db, err := sql.Open("nop", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+var errNopStatement = errors.New("nop database: statements are not supported")
+
+type nopDriver struct{}
+
+func (nopDriver) Open(string) (driver.Conn, error) {
+	return nopConn{}, nil
+}
+
+type nopConn struct{}
+
+func (nopConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errNopStatement
+}
+
+func (nopConn) Close() error {
+	return nil
+}
+
+func (nopConn) Begin() (driver.Tx, error) {
+	return nopTx{}, nil
+}
+
+func (nopConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	return nopTx{}, nil
+}
+
+type nopTx struct{}
+
+func (nopTx) Commit() error {
+	return nil
+}
+
+func (nopTx) Rollback() error {
+	return nil
+}
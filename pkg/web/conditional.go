@@ -0,0 +1,56 @@
+package web
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotModified sets the Last-Modified and ETag validators of the representation
+// and, when the conditional headers of a GET or HEAD request show the client
+// already has it, answers 304 and returns true. If-None-Match takes precedence
+// over If-Modified-Since, and ETags are compared weakly. A zero lastModified or
+// an empty etag leaves the matching validator out.
+func NotModified(c *gin.Context, lastModified time.Time, etag string) bool {
+	lastModified = lastModified.UTC().Truncate(time.Second)
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+	}
+	if etag != "" {
+		c.Header("ETag", etag)
+	}
+
+	if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+		return false
+	}
+
+	if inm := c.GetHeader("If-None-Match"); inm != "" {
+		if etag == "" || !etagMatch(inm, etag) {
+			return false
+		}
+	} else if ims := c.GetHeader("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(ims)
+		if err != nil || lastModified.After(since) {
+			return false
+		}
+	} else {
+		return false
+	}
+
+	c.Status(http.StatusNotModified)
+	c.Writer.WriteHeaderNow()
+	return true
+}
+
+// etagMatch reports whether the If-None-Match header lists etag, or is "*".
+func etagMatch(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func conditional(method string, headers map[string]string, lastModified time.Time, etag string) (*httptest.ResponseRecorder, bool) {
+	response := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(response)
+	c.Request = httptest.NewRequest(method, "/", nil)
+	for k, v := range headers {
+		c.Request.Header.Set(k, v)
+	}
+	notModified := NotModified(c, lastModified, etag)
+	if !notModified {
+		c.Status(http.StatusOK)
+		c.Writer.WriteHeaderNow()
+	}
+	return response, notModified
+}
+
+func TestNotModified(t *testing.T) {
+	lastModified := time.Date(2021, 10, 10, 12, 30, 15, 500, time.UTC)
+	etag := `W/"abc"`
+
+	t.Run("The validators are always set.", func(t *testing.T) {
+		response, notModified := conditional(http.MethodGet, nil, lastModified, etag)
+
+		assert.False(t, notModified)
+		assert.Equal(t, "Sun, 10 Oct 2021 12:30:15 GMT", response.Header().Get("Last-Modified"))
+		assert.Equal(t, etag, response.Header().Get("ETag"))
+	})
+
+	t.Run("A matching If-None-Match, compared weakly, is answered with a 304.", func(t *testing.T) {
+		response, notModified := conditional(http.MethodGet, map[string]string{"If-None-Match": `"xyz", "abc"`}, lastModified, etag)
+
+		assert.True(t, notModified)
+		assert.Equal(t, http.StatusNotModified, response.Code)
+	})
+
+	t.Run("If-None-Match takes precedence over If-Modified-Since.", func(t *testing.T) {
+		_, notModified := conditional(http.MethodGet, map[string]string{
+			"If-None-Match":     `W/"old"`,
+			"If-Modified-Since": "Mon, 11 Oct 2021 00:00:00 GMT",
+		}, lastModified, etag)
+
+		assert.False(t, notModified)
+	})
+
+	t.Run("If-Modified-Since is honoured to the second.", func(t *testing.T) {
+		_, notModified := conditional(http.MethodGet, map[string]string{"If-Modified-Since": "Sun, 10 Oct 2021 12:30:15 GMT"}, lastModified, etag)
+		assert.True(t, notModified)
+
+		_, notModified = conditional(http.MethodGet, map[string]string{"If-Modified-Since": "Sun, 10 Oct 2021 12:30:14 GMT"}, lastModified, etag)
+		assert.False(t, notModified)
+	})
+
+	t.Run("Other methods are never answered with a 304.", func(t *testing.T) {
+		_, notModified := conditional(http.MethodPut, map[string]string{"If-None-Match": "*"}, lastModified, etag)
+
+		assert.False(t, notModified)
+	})
+}
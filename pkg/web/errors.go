@@ -0,0 +1,53 @@
+package web
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrorMapping describes how a domain error is rendered as an HTTP error response.
+// When Code is empty, the code is derived from the status text.
+type ErrorMapping struct {
+	Status int
+	Code   string
+}
+
+type registeredError struct {
+	target  error
+	mapping ErrorMapping
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []registeredError
+)
+
+// RegisterError maps every error matching target (via errors.Is) to the given status.
+func RegisterError(target error, status int) {
+	RegisterErrorWithCode(target, status, "")
+}
+
+// RegisterErrorWithCode maps every error matching target (via errors.Is) to the given
+// status and error code.
+func RegisterErrorWithCode(target error, status int, code string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry = append(registry, registeredError{
+		target:  target,
+		mapping: ErrorMapping{Status: status, Code: code},
+	})
+}
+
+// LookupError returns the mapping registered for err, if any.
+func LookupError(err error) (ErrorMapping, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for _, r := range registry {
+		if errors.Is(err, r.target) {
+			return r.mapping, true
+		}
+	}
+	return ErrorMapping{}, false
+}
@@ -0,0 +1,230 @@
+package web
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+)
+
+const (
+	MIMEJSON     = binding.MIMEJSON
+	MIMEXML      = binding.MIMEXML
+	MIMEXML2     = binding.MIMEXML2
+	MIMEMsgPack  = binding.MIMEMSGPACK2
+	MIMEMsgPack2 = binding.MIMEMSGPACK
+	MIMECSV      = "text/csv"
+)
+
+// ErrUnsupportedMediaType is returned by ShouldBind when the request body is in a
+// format the API does not read.
+var ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+func init() {
+	RegisterError(ErrUnsupportedMediaType, http.StatusUnsupportedMediaType)
+}
+
+var (
+	// offers are the formats every response can be written in, JSON first as the default.
+	offers = []string{MIMEJSON, MIMEXML, MIMEXML2, MIMEMsgPack, MIMEMsgPack2}
+	// listOffers add CSV for the responses holding a list.
+	listOffers = append(offers[:len(offers):len(offers)], MIMECSV)
+)
+
+// Format returns the media type data is written in for the Accept header of the
+// request, or "" when none of the formats offered for data is acceptable.
+func Format(c *gin.Context, data interface{}) string {
+	return c.NegotiateFormat(formats(data)...)
+}
+
+// Vary adds field to the Vary header of the response unless already listed.
+func Vary(c *gin.Context, field string) {
+	for _, value := range c.Writer.Header().Values("Vary") {
+		for _, listed := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(listed), field) {
+				return
+			}
+		}
+	}
+	c.Writer.Header().Add("Vary", field)
+}
+
+func formats(data interface{}) []string {
+	if _, ok := csvList(data); ok {
+		return listOffers
+	}
+	return offers
+}
+
+// negotiate writes data in the format negotiated from the Accept header, answering
+// 406 in JSON when none of the formats offered for data is acceptable.
+func negotiate(c *gin.Context, status int, data interface{}) {
+	available := formats(data)
+	Vary(c, "Accept")
+
+	switch c.NegotiateFormat(available...) {
+	case MIMEJSON:
+		c.JSON(status, data)
+	case MIMEXML, MIMEXML2:
+		c.XML(status, data)
+	case MIMEMsgPack, MIMEMsgPack2:
+		c.Render(status, render.MsgPack{Data: data})
+	case MIMECSV:
+		list, _ := csvList(data)
+		c.Render(status, csvRender{list: list})
+	default:
+		notAcceptable := http.StatusNotAcceptable
+		c.JSON(notAcceptable, ErrorResponse{
+			Code:    StatusCode(notAcceptable),
+			Message: fmt.Sprintf("none of the accepted media types is available, use one of: %s", strings.Join(available, ", ")),
+			Status:  notAcceptable,
+		})
+	}
+}
+
+// ShouldBind decodes the request body according to its Content-Type, which may
+// be JSON (also assumed when missing), XML or MessagePack, and validates it.
+func ShouldBind(c *gin.Context, obj interface{}) error {
+	contentType := c.ContentType()
+	if contentType == "" {
+		return c.ShouldBindWith(obj, binding.JSON)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrUnsupportedMediaType, contentType)
+	}
+
+	switch mediaType {
+	case MIMEJSON:
+		return c.ShouldBindWith(obj, binding.JSON)
+	case MIMEXML, MIMEXML2:
+		return c.ShouldBindWith(obj, binding.XML)
+	case MIMEMsgPack, MIMEMsgPack2:
+		return c.ShouldBindWith(obj, binding.MsgPack)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedMediaType, mediaType)
+	}
+}
+
+// csvList returns the list held by data, directly or as the data of Responses.
+func csvList(data interface{}) (reflect.Value, bool) {
+	if r, ok := data.(Responses); ok {
+		data = r.Data
+	}
+
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Slice || v.Type().Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	return v, true
+}
+
+// csvRender writes a list of structs as CSV: a header with the JSON names of the
+// fields, including those of embedded structs, then a row per item. Nested lists,
+// such as expanded related resources, have no column.
+type csvRender struct {
+	list reflect.Value
+}
+
+func (r csvRender) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+
+	cw := csv.NewWriter(w)
+	fields := csvFields(r.list.Type().Elem(), nil)
+
+	header := make([]string, len(fields))
+	for i, f := range fields {
+		header[i] = f.name
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for i := 0; i < r.list.Len(); i++ {
+		item := r.list.Index(i)
+		row := make([]string, len(fields))
+		for j, f := range fields {
+			row[j] = csvValue(item.FieldByIndex(f.index))
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func (r csvRender) WriteContentType(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", MIMECSV+"; charset=utf-8")
+}
+
+type csvField struct {
+	name  string
+	index []int
+}
+
+func csvFields(t reflect.Type, index []int) []csvField {
+	var fields []csvField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fieldIndex := append(index[:len(index):len(index)], i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			fields = append(fields, csvFields(f.Type, fieldIndex)...)
+			continue
+		}
+		if !f.IsExported() || f.Type.Kind() == reflect.Slice || f.Type.Kind() == reflect.Map {
+			continue
+		}
+
+		name := jsonFieldName(f)
+		if name == "" {
+			continue
+		}
+		fields = append(fields, csvField{name: name, index: fieldIndex})
+	}
+	return fields
+}
+
+func csvValue(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch x := v.Interface().(type) {
+	case time.Time:
+		if x.IsZero() {
+			return ""
+		}
+		return x.Format(time.RFC3339)
+	case fmt.Stringer:
+		return x.String()
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	}
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,150 @@
+package web
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/ugorji/go/codec"
+)
+
+type contact struct {
+	Email string `json:"email" xml:"email"`
+}
+
+type person struct {
+	contact
+	ID        int        `json:"id" xml:"id"`
+	Name      string     `json:"name" xml:"name" binding:"required"`
+	Hidden    string     `json:"-" xml:"-"`
+	CreatedAt time.Time  `json:"created_at" xml:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at" xml:"updated_at,omitempty"`
+	Phones    []string   `json:"phones,omitempty" xml:"phones>phone,omitempty"`
+}
+
+var people = []person{
+	{contact: contact{Email: "danilo@example.com"}, ID: 1, Name: "Danilo, Sano", Hidden: "x", CreatedAt: time.Date(2021, 10, 10, 0, 0, 0, 0, time.UTC), Phones: []string{"+5511999999999"}},
+	{ID: 2, Name: "Ana"},
+}
+
+func respond(accept string, data interface{}) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(response)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	if accept != "" {
+		c.Request.Header.Set("Accept", accept)
+	}
+	Success(c, http.StatusOK, data)
+	return response
+}
+
+func TestResponse(t *testing.T) {
+	t.Run("Without an Accept header, the response is JSON.", func(t *testing.T) {
+		response := respond("", people[1])
+
+		assert.Equal(t, "application/json; charset=utf-8", response.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"data": {"email": "", "id": 2, "name": "Ana", "created_at": "0001-01-01T00:00:00Z", "updated_at": null}}`, response.Body.String())
+	})
+
+	t.Run("XML is written when preferred, with the XML names of the fields.", func(t *testing.T) {
+		var result struct {
+			XMLName xml.Name `xml:"response"`
+			Data    person   `xml:"data"`
+		}
+
+		response := respond("text/html, application/xml;q=0.9", people[1])
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "application/xml; charset=utf-8", response.Header().Get("Content-Type"))
+		assert.NoError(t, xml.Unmarshal(response.Body.Bytes(), &result))
+		assert.Equal(t, "Ana", result.Data.Name)
+	})
+
+	t.Run("MessagePack is written with the JSON names of the fields.", func(t *testing.T) {
+		var result map[string]map[string]interface{}
+
+		response := respond("application/msgpack", people[1])
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.NoError(t, codec.NewDecoderBytes(response.Body.Bytes(), new(codec.MsgpackHandle)).Decode(&result))
+		assert.Equal(t, "Ana", string(result["data"]["name"].([]byte)))
+	})
+
+	t.Run("A list is written as CSV, with a header and a row per item, leaving the nested lists out.", func(t *testing.T) {
+		response := respond("text/csv", people)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", response.Header().Get("Content-Type"))
+		assert.Equal(t, "email,id,name,created_at,updated_at\n"+
+			"danilo@example.com,1,\"Danilo, Sano\",2021-10-10T00:00:00Z,\n"+
+			",2,Ana,,\n", response.Body.String())
+	})
+
+	t.Run("CSV is not offered for a single item, so a 406 code is returned.", func(t *testing.T) {
+		var resp ErrorResponse
+
+		response := respond("text/csv", people[0])
+
+		assert.Equal(t, http.StatusNotAcceptable, response.Code)
+		assert.NoError(t, codec.NewDecoderBytes(response.Body.Bytes(), new(codec.JsonHandle)).Decode(&resp))
+		assert.Equal(t, "not_acceptable", resp.Code)
+	})
+
+	t.Run("An unsupported media type is answered with a 406 code.", func(t *testing.T) {
+		response := respond("image/png", people)
+
+		assert.Equal(t, http.StatusNotAcceptable, response.Code)
+		assert.Contains(t, response.Body.String(), "text/csv")
+	})
+}
+
+func bind(contentType string, body []byte, obj interface{}) error {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	if contentType != "" {
+		c.Request.Header.Set("Content-Type", contentType)
+	}
+	return ShouldBind(c, obj)
+}
+
+func TestShouldBind(t *testing.T) {
+	t.Run("A body without Content-Type is read as JSON.", func(t *testing.T) {
+		var p person
+		assert.NoError(t, bind("", []byte(`{"id": 3, "name": "Rui"}`), &p))
+		assert.Equal(t, "Rui", p.Name)
+	})
+
+	t.Run("An XML body is read and validated.", func(t *testing.T) {
+		var p person
+		assert.NoError(t, bind("application/xml; charset=utf-8", []byte(`<person><id>3</id><name>Rui</name></person>`), &p))
+		assert.Equal(t, 3, p.ID)
+
+		err := bind("text/xml", []byte(`<person><id>3</id></person>`), &person{})
+		assert.Error(t, err)
+		assert.False(t, errors.Is(err, ErrUnsupportedMediaType))
+	})
+
+	t.Run("A MessagePack body is read using the JSON names.", func(t *testing.T) {
+		var body []byte
+		assert.NoError(t, codec.NewEncoderBytes(&body, new(codec.MsgpackHandle)).Encode(map[string]interface{}{"id": 4, "name": "Eva"}))
+
+		var p person
+		assert.NoError(t, bind("application/x-msgpack", body, &p))
+		assert.Equal(t, person{ID: 4, Name: "Eva"}, p)
+	})
+
+	t.Run("Any other Content-Type is rejected as unsupported.", func(t *testing.T) {
+		err := bind("text/plain", []byte(`name=Rui`), &person{})
+		assert.True(t, errors.Is(err, ErrUnsupportedMediaType))
+
+		mapping, ok := LookupError(err)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusUnsupportedMediaType, mapping.Status)
+	})
+}
@@ -1,6 +1,7 @@
 package web
 
 import (
+	"encoding/xml"
 	"fmt"
 	"net/http"
 	"strings"
@@ -9,17 +10,22 @@ import (
 )
 
 type Responses struct {
-	Data interface{} `json:"data"`
+	XMLName xml.Name    `json:"-" xml:"response" swaggerignore:"true"`
+	Data    interface{} `json:"data" xml:"data"`
 }
 
 type ErrorResponse struct {
-	Status  int    `json:"-"`
-	Code    string `json:"code"`
-	Message string `json:"message"`
+	XMLName xml.Name     `json:"-" xml:"error" swaggerignore:"true"`
+	Status  int          `json:"-" xml:"-"`
+	Code    string       `json:"code" xml:"code"`
+	Message string       `json:"message" xml:"message"`
+	Fields  []FieldError `json:"fields,omitempty" xml:"fields>field,omitempty"`
 }
 
+// Response writes data in the format requested by the Accept header: JSON by
+// default, XML, MessagePack or, for lists, CSV.
 func Response(c *gin.Context, status int, data interface{}) {
-	c.JSON(status, data)
+	negotiate(c, status, data)
 }
 
 func Success(c *gin.Context, status int, data interface{}) {
@@ -27,11 +33,24 @@ func Success(c *gin.Context, status int, data interface{}) {
 }
 
 func Error(c *gin.Context, status int, format string, args ...interface{}) {
+	ErrorWithCode(c, status, "", format, args...)
+}
+
+func ErrorWithCode(c *gin.Context, status int, code string, format string, args ...interface{}) {
+	if code == "" {
+		code = StatusCode(status)
+	}
+
 	err := ErrorResponse{
-		Code:    strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_"),
+		Code:    code,
 		Message: fmt.Sprintf(format, args...),
 		Status:  status,
 	}
 
 	Response(c, status, err)
 }
+
+// StatusCode returns the error code derived from the status text, e.g. "not_found".
+func StatusCode(status int) string {
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
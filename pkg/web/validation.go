@@ -0,0 +1,130 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError reports a single invalid field of a request body.
+type FieldError struct {
+	Field   string `json:"field" xml:"field"`
+	Message string `json:"message" xml:"message"`
+}
+
+func init() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	v.RegisterTagNameFunc(jsonFieldName)
+	_ = v.RegisterValidation("varchar", validateVarchar)
+}
+
+// jsonFieldName reports fields by their JSON name so error paths match the request body.
+func jsonFieldName(f reflect.StructField) string {
+	name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+// validateVarchar checks that a string fits a VARCHAR(n) column, counting characters
+// rather than bytes as MySQL does. Usage: `binding:"varchar=100"`.
+func validateVarchar(fl validator.FieldLevel) bool {
+	var limit int
+	if _, err := fmt.Sscan(fl.Param(), &limit); err != nil {
+		return false
+	}
+	return utf8.RuneCountInString(fl.Field().String()) <= limit
+}
+
+// ValidationError renders a 400 response listing every invalid field.
+func ValidationError(c *gin.Context, errs validator.ValidationErrors) {
+	status := http.StatusBadRequest
+	Response(c, status, ErrorResponse{
+		Code:    StatusCode(status),
+		Message: "invalid input",
+		Status:  status,
+		Fields:  FieldErrors(errs),
+	})
+}
+
+// FieldErrors converts validator errors into field-level reports keyed by JSON path.
+func FieldErrors(errs validator.ValidationErrors) []FieldError {
+	fields := make([]FieldError, 0, len(errs))
+	for _, fe := range errs {
+		fields = append(fields, FieldError{
+			Field:   fieldPath(fe.Namespace()),
+			Message: fieldMessage(fe),
+		})
+	}
+	return fields
+}
+
+// fieldPath drops the root struct name from a namespace such as
+// "CreateCustomerRequest.first_name".
+func fieldPath(namespace string) string {
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
+
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "gt":
+		return fmt.Sprintf("must be greater than %s", fe.Param())
+	case "gte":
+		return fmt.Sprintf("must be greater than or equal to %s", fe.Param())
+	case "lt":
+		return fmt.Sprintf("must be less than %s", fe.Param())
+	case "lte":
+		return fmt.Sprintf("must be less than or equal to %s", fe.Param())
+	case "min":
+		if isCollection(fe.Kind()) {
+			return fmt.Sprintf("must contain at least %s items", fe.Param())
+		}
+		return fmt.Sprintf("must be at least %s characters long", fe.Param())
+	case "max", "varchar":
+		if isCollection(fe.Kind()) {
+			return fmt.Sprintf("must contain at most %s items", fe.Param())
+		}
+		return fmt.Sprintf("must be at most %s characters long", fe.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", strings.ReplaceAll(fe.Param(), " ", ", "))
+	case "contains":
+		return fmt.Sprintf("must contain '%s'", fe.Param())
+	case "email":
+		return "must be a valid email address"
+	case "url":
+		return "must be a valid URL"
+	case "http_url":
+		return "must be a valid http or https URL"
+	case "e164":
+		return "must be a valid phone number"
+	case "iso3166_1_alpha2":
+		return "must be an ISO 3166-1 alpha-2 country code"
+	case "postcode_iso3166_alpha2_field":
+		return "must be a valid postal code of the country"
+	default:
+		return fmt.Sprintf("failed on the '%s' rule", fe.Tag())
+	}
+}
+
+func isCollection(k reflect.Kind) bool {
+	return k == reflect.Slice || k == reflect.Array || k == reflect.Map
+}